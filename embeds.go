@@ -4,3 +4,6 @@ import _ "embed"
 
 //go:embed VERSION
 var Version string
+
+//go:embed config.schema.json
+var ConfigSchema []byte
@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
-	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd"
-	"github.com/urfave/cli/v3"
 )
 
 func main() {
-	if err := cmd.Commands.Run(context.Background(), os.Args); err != nil {
-		fmt.Fprintln(cli.ErrWriter, err)
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := cmd.Commands.Run(ctx, os.Args); err != nil {
+		cancel()
+		os.Exit(cmd.HandleError(cmd.Commands, err))
 	}
 }
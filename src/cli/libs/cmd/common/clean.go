@@ -16,7 +16,7 @@ var clean = &cli.Command{
 		&cli.BoolFlag{Name: "config", Usage: "If specified, remove all data from the CLI config directory", Required: false},
 		&cli.BoolFlag{Name: "cache", Usage: "If specified, remove all data from the CLI cache directory", Required: false},
 		&cli.BoolFlag{Name: "force", Usage: "If specified, skip all prompts", Aliases: []string{"f"}, Required: false, Value: false},
-		&cli.BoolFlag{Name: "all", Usage: "If specified, remove all data from prior CLI versions", Aliases: []string{"a"}, Required: false, Value: false},
+		&cli.BoolFlag{Name: "all", Usage: "If specified, remove all data from prior CLI versions as well as all installed and cached plugins", Aliases: []string{"a"}, Required: false, Value: false},
 	},
 	Action: func(ctx context.Context, c *cli.Command) error {
 		config := c.Bool("config")
@@ -24,6 +24,7 @@ var clean = &cli.Command{
 		force := c.Bool("force")
 		all := c.Bool("all")
 
+		results := []*core.CleanResult{}
 		if !config && !cache {
 			config = true
 			cache = true
@@ -36,8 +37,10 @@ var clean = &cli.Command{
 				configDir = filepath.Dir(dirs.Config)
 			}
 
-			if err := core.CleanDir(c, configDir, force); err != nil {
+			if result, err := core.CleanDir(c, configDir, force); err != nil {
 				return core.ErrExit(err)
+			} else {
+				results = append(results, result)
 			}
 		}
 
@@ -48,11 +51,17 @@ var clean = &cli.Command{
 				cacheDir = filepath.Dir(cacheDir)
 			}
 
-			if err := core.CleanDir(c, cacheDir, force); err != nil {
+			if result, err := core.CleanDir(c, cacheDir, force); err != nil {
 				return core.ErrExit(err)
+			} else {
+				results = append(results, result)
 			}
 		}
 
-		return nil
+		if err := core.PrintResult(c, results); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
 	},
 }
@@ -15,11 +15,21 @@ var version = &cli.Command{
 		&cli.BoolFlag{Name: "no-prefix", Usage: "If specified remove the leading 'v'", Required: false, Value: false},
 	},
 	Action: func(ctx context.Context, c *cli.Command) error {
+		version := core.VersionWithPrefix()
 		if c.Bool("no-prefix") {
-			fmt.Fprintln(c.Root().Writer, core.VersionWithoutPrefix())
+			version = core.VersionWithoutPrefix()
+		}
+
+		// NOTE: scripts read the version as plain text unless a format is requested
+		if !c.Root().IsSet(core.OUTPUT_FLAG) {
+			fmt.Fprintln(c.Root().Writer, version)
+			return nil
+		}
+
+		if err := core.PrintResult(c, version); err != nil {
+			return core.ErrExit(err)
 		} else {
-			fmt.Fprintln(c.Root().Writer, core.VersionWithPrefix())
+			return nil
 		}
-		return nil
 	},
 }
@@ -0,0 +1,275 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	cfg "github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/urfave/cli/v3"
+)
+
+// InitResult lists the chains that were added to a config file
+type InitResult struct {
+	Path   string
+	Chains map[string]cfg.ChainConfig
+}
+
+// selection is a chain that should be built from a template
+type selection struct {
+	template string
+	name     string
+}
+
+var initialize = &cli.Command{
+	Name:  "init",
+	Usage: "Creates a CLI config file from built-in chain templates (or adds chains to an existing one)",
+	Description: "Chains are selected with --chain TEMPLATE or --chain TEMPLATE=NAME (or interactively with --interactive). " +
+		"Each chain gets the first port (starting at --port) that does not conflict with a chain that is already in the " +
+		"config. If the config file exists, then the new chains are merged into it and the existing chains are kept.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file (the format is determined by the extension)", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false, Value: "config.json"},
+		&cli.StringSliceFlag{Name: "chain", Usage: "A template to add a chain from, optionally followed by the chain's name (e.g. polkadot or ethereum=eth-main)", Required: false},
+		&cli.StringFlag{Name: "network", Usage: "The network to connect to (mainnet, testnet)", Required: false, Value: cfg.NETWORK_MAINNET},
+		&cli.StringFlag{Name: "host", Usage: "The host that the chains' servers listen on", Required: false, Value: cfg.DEFAULT_SERVER_HOST},
+		&cli.IntFlag{Name: "port", Usage: "The first port to allocate to the chains' servers", Required: false, Value: cfg.DEFAULT_SERVER_PORT},
+		&cli.BoolFlag{Name: "interactive", Usage: "If specified, prompt for the network, chains and server settings", Aliases: []string{"i"}, Required: false, Value: false},
+		&cli.BoolFlag{Name: "list", Usage: "If specified, list the available templates and exit", Required: false, Value: false},
+		&cli.BoolFlag{Name: "dry-run", Usage: "If specified, print the resulting config file instead of writing it", Required: false, Value: false},
+		&cli.BoolFlag{Name: "force", Usage: "If specified, rewrite an existing TOML config even though its comments and key order are lost", Required: false, Value: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		if c.Bool("list") {
+			if err := core.PrintResult(c, cfg.Templates); err != nil {
+				return core.ErrExit(err)
+			} else {
+				return nil
+			}
+		}
+
+		configPath := c.String("config")
+		format := cfg.FormatFromPath(configPath)
+
+		data, err := os.ReadFile(configPath)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return core.ErrExit(err)
+		}
+
+		// NOTE: the existing chains are only needed to allocate ports and pick names, so
+		// problems in the existing config (e.g. an unset environment variable) are
+		// ignored unless the config cannot be decoded at all
+		current := &cfg.CliConfig{Chains: map[string]cfg.ChainConfig{}}
+		if len(bytes.TrimSpace(data)) != 0 {
+			conf, problems, err := cfg.CheckCliConfig(bytes.NewReader(data), cfg.DecodeOptions{Format: format, Dir: filepath.Dir(configPath)})
+			if err != nil {
+				return core.ErrExit(err)
+			}
+			if conf == nil {
+				return core.ErrExit(problems)
+			} else {
+				current = conf
+			}
+		}
+
+		network := c.String("network")
+		host := c.String("host")
+		port := c.Int("port")
+
+		selections := []selection{}
+		if c.Bool("interactive") {
+			p := &prompter{r: bufio.NewReader(c.Root().Reader), w: c.Root().Writer}
+			network, host, port, selections, err = p.run(current, network, host, port)
+			if err != nil {
+				return core.ErrExit(err)
+			}
+		} else {
+			for _, value := range c.StringSlice("chain") {
+				template, name, _ := strings.Cut(value, "=")
+				if name == "" {
+					name = template
+				}
+				selections = append(selections, selection{template: template, name: name})
+			}
+		}
+		if len(selections) == 0 {
+			return core.ErrExit(errors.New("no chains were selected - use --chain or --interactive (see --list for the available templates)"))
+		}
+
+		added := map[string]cfg.ChainConfig{}
+		for _, s := range selections {
+			chainConfig, err := build(current, s, network, host, port)
+			if err != nil {
+				return core.ErrExit(err)
+			} else {
+				current.Chains[s.name] = *chainConfig
+				added[s.name] = *chainConfig
+			}
+		}
+
+		output, err := cfg.AddChains(data, format, added, c.Bool("force"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		if c.Bool("dry-run") {
+			if _, err := c.Root().Writer.Write(output); err != nil {
+				return core.ErrExit(err)
+			} else {
+				return nil
+			}
+		}
+
+		if err := os.WriteFile(configPath, output, 0644); err != nil {
+			return core.ErrExit(err)
+		}
+
+		if err := core.PrintResult(c, &InitResult{Path: configPath, Chains: added}); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// build creates the config of a selected chain and allocates a port for it that does
+// not conflict with any chain in the current config
+func build(current *cfg.CliConfig, s selection, network string, host string, port int64) (*cfg.ChainConfig, error) {
+	if strings.TrimSpace(s.name) == "" {
+		return nil, fmt.Errorf("the chain created from template '%s' must have a name", s.template)
+	}
+	if _, exists := current.Chains[s.name]; exists {
+		return nil, fmt.Errorf("chain '%s' already exists in the config - pick another name with --chain %s=NAME", s.name, s.template)
+	}
+
+	template, err := cfg.FindTemplate(s.template)
+	if err != nil {
+		return nil, err
+	}
+
+	free, err := current.FreePort(host, port)
+	if err != nil {
+		return nil, err
+	}
+
+	chainConfig, err := template.Chain(network, cfg.ServerConfig{Host: host, Port: free})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := chainConfig.Validate(); err != nil {
+		return nil, err
+	} else {
+		return chainConfig, nil
+	}
+}
+
+type prompter struct {
+	r *bufio.Reader
+	w io.Writer
+}
+
+// ask prints a question and returns the answer (or the default answer if the answer
+// is empty)
+func (p *prompter) ask(question string, fallback string) (string, error) {
+	if fallback != "" {
+		fmt.Fprintf(p.w, "%s [%s]: ", question, fallback)
+	} else {
+		fmt.Fprintf(p.w, "%s: ", question)
+	}
+
+	line, err := p.r.ReadString('\n')
+	if err != nil && (!errors.Is(err, io.EOF) || line == "") {
+		return "", err
+	}
+
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	} else {
+		return fallback, nil
+	}
+}
+
+// run asks for the network, server settings and chains, using the flags as defaults
+func (p *prompter) run(current *cfg.CliConfig, network string, host string, port int64) (string, string, int64, []selection, error) {
+	network, err := p.ask(fmt.Sprintf("Network (%s, %s)", cfg.NETWORK_MAINNET, cfg.NETWORK_TESTNET), network)
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	host, err = p.ask("Server host", host)
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	answer, err := p.ask("First server port", strconv.FormatInt(port, 10))
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+	port, err = strconv.ParseInt(answer, 10, 64)
+	if err != nil {
+		return "", "", 0, nil, fmt.Errorf("invalid port '%s' - must be an integer", answer)
+	}
+
+	fmt.Fprintln(p.w, "Templates:")
+	for i, template := range cfg.Templates {
+		fmt.Fprintf(p.w, "  %d) %s - %s\n", i+1, template.Name, template.Description)
+	}
+
+	answer, err = p.ask("Chains to add (comma separated names or numbers)", "")
+	if err != nil {
+		return "", "", 0, nil, err
+	}
+
+	taken := map[string]bool{}
+	for chainName := range current.Chains {
+		taken[chainName] = true
+	}
+
+	selections := []selection{}
+	for _, choice := range strings.Split(answer, ",") {
+		choice = strings.TrimSpace(choice)
+		if choice == "" {
+			continue
+		}
+		if i, err := strconv.Atoi(choice); err == nil && i >= 1 && i <= len(cfg.Templates) {
+			choice = cfg.Templates[i-1].Name
+		}
+
+		template, err := cfg.FindTemplate(choice)
+		if err != nil {
+			return "", "", 0, nil, err
+		}
+
+		name, err := p.ask(fmt.Sprintf("Name of the %s chain", template.Name), uniqueName(taken, template.Name))
+		if err != nil {
+			return "", "", 0, nil, err
+		} else {
+			taken[name] = true
+			selections = append(selections, selection{template: template.Name, name: name})
+		}
+	}
+
+	return network, host, port, selections, nil
+}
+
+// uniqueName returns the name or, if it is taken, the name with the first free
+// numeric suffix (e.g. solana-2)
+func uniqueName(taken map[string]bool, name string) string {
+	if !taken[name] {
+		return name
+	}
+	for i := 2; ; i++ {
+		if candidate := fmt.Sprintf("%s-%d", name, i); !taken[candidate] {
+			return candidate
+		}
+	}
+}
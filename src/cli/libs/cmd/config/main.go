@@ -0,0 +1,16 @@
+package config
+
+import (
+	"github.com/urfave/cli/v3"
+)
+
+var Commands = &cli.Command{
+	Name:  "config",
+	Usage: "Commands for working with CLI config files",
+	Commands: []*cli.Command{
+		initialize,
+		validate,
+		show,
+		schema,
+	},
+}
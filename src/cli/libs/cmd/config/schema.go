@@ -0,0 +1,18 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	embeds "github.com/chris-de-leon/chain-connectors-prototype"
+	"github.com/urfave/cli/v3"
+)
+
+var schema = &cli.Command{
+	Name:  "schema",
+	Usage: "Prints the JSON Schema of the CLI config file",
+	Action: func(ctx context.Context, c *cli.Command) error {
+		_, err := fmt.Fprint(c.Root().Writer, string(embeds.ConfigSchema))
+		return err
+	},
+}
@@ -0,0 +1,49 @@
+package config
+
+import (
+	"context"
+
+	cfg "github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/urfave/cli/v3"
+)
+
+var show = &cli.Command{
+	Name:  "show",
+	Usage: "Prints a CLI config (with environment variables and file references interpolated and secrets redacted)",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to show (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.BoolFlag{Name: "resolved", Usage: "If specified, show the effective chain configs with the defaults and profiles applied", Required: false, Value: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		cliConfig, err := cfg.ParseCliConfig(c.String("config"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		result := cliConfig.Declared()
+		if c.Bool("resolved") {
+			result = cliConfig.Resolved()
+		}
+
+		chainNames := c.StringSlice("name")
+		if len(chainNames) != 0 {
+			chains := map[string]cfg.ChainConfig{}
+			for _, chainName := range chainNames {
+				if _, err := cliConfig.Chain(chainName); err != nil {
+					return core.ErrExit(err)
+				} else {
+					chains[chainName] = result.Chains[chainName]
+				}
+			}
+			result.Chains = chains
+		}
+
+		if err := core.PrintResult(c, result); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	cfg "github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+	"github.com/urfave/cli/v3"
+)
+
+// ValidateResult lists the problems that were found in a config file
+type ValidateResult struct {
+	Path     string               `json:"path"`
+	Valid    bool                 `json:"valid"`
+	Problems cfg.ValidationErrors `json:"problems"`
+}
+
+var validate = &cli.Command{
+	Name:  "validate",
+	Usage: "Checks a CLI config file and reports every problem along with its JSON path",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		configPath := c.String("config")
+
+		cliConfig, problems, err := cfg.CheckCliConfigFile(configPath)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		// NOTE: the remaining checks need a config that could be decoded
+		if cliConfig != nil {
+			if more, err := check(cliConfig); err != nil {
+				return core.ErrExit(err)
+			} else {
+				problems = append(problems, more...)
+			}
+		}
+
+		slices.SortStableFunc(problems, func(a, b *cfg.ValidationError) int {
+			return strings.Compare(a.Path, b.Path)
+		})
+
+		result := &ValidateResult{Path: configPath, Valid: len(problems) == 0, Problems: append(cfg.ValidationErrors{}, problems...)}
+		if err := core.PrintResult(c, result); err != nil {
+			return core.ErrExit(err)
+		}
+
+		if len(problems) != 0 {
+			return core.ErrExitWithCode(fmt.Errorf("found %d problem(s) in '%s'", len(problems), configPath), core.CODE_CONFIG, core.EXIT_CONFIG)
+		} else {
+			return nil
+		}
+	},
+}
+
+// check performs the checks that need more than the config itself (i.e. the plugins
+// that are installed, the supported restart policies and the supported registries)
+func check(cliConfig *cfg.CliConfig) (cfg.ValidationErrors, error) {
+	knownIDs, err := plgn.KnownIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	problems := cfg.ValidationErrors{}
+	fail := func(path string, err error) {
+		for _, line := range strings.Split(err.Error(), "\n") {
+			problems = append(problems, &cfg.ValidationError{Path: path, Message: line})
+		}
+	}
+
+	for _, chainName := range cliConfig.ChainNames() {
+		chainConfig := cliConfig.Chains[chainName]
+		path := cfg.JoinPath(cfg.JoinPath(cfg.ROOT_PATH, "chains"), chainName)
+
+		if chainConfig.Restart != nil {
+			if _, err := supervisor.ParseRestartPolicy(chainConfig.Restart.Policy); err != nil {
+				fail(cfg.JoinPath(cfg.JoinPath(path, "restart"), "policy"), err)
+			}
+		}
+
+		// NOTE: a missing plugin block or ID has already been reported
+		if chainConfig.Plugin == nil || chainConfig.Plugin.ID == "" {
+			continue
+		}
+
+		ref, err := plgn.RefFromConfig(chainConfig.Plugin)
+		if err != nil {
+			fail(cfg.JoinPath(path, "plugin"), err)
+			continue
+		}
+		if !slices.Contains(knownIDs, ref.ID) {
+			fail(cfg.JoinPath(cfg.JoinPath(path, "plugin"), "id"), fmt.Errorf(
+				"unknown plugin '%s' - must be one of: [ %s ] (plugins from other registries must be installed first)",
+				ref.ID,
+				strings.Join(knownIDs, ", "),
+			))
+			continue
+		}
+
+		// NOTE: the chain config can only be checked against the plugin's manifest if
+		// the plugin is installed
+		if installed, err := plgn.Store.IsInstalled(ref); err != nil {
+			return nil, err
+		} else if installed {
+			if err := plgn.Store.Validate(&chainConfig); err != nil {
+				fail(path, err)
+			}
+		}
+	}
+
+	for i, registryConfig := range cliConfig.Registries {
+		path := cfg.IndexPath(cfg.JoinPath(cfg.ROOT_PATH, "registries"), i)
+		// NOTE: a missing type has already been reported
+		if registryConfig.Type == "" {
+			continue
+		}
+		if _, err := registry.New(registryConfig); err != nil {
+			fail(path, err)
+		}
+		if registryConfig.PublicKey != "" {
+			if _, err := integrity.LoadPublicKey(registryConfig.PublicKey); err != nil {
+				fail(cfg.JoinPath(path, "publicKey"), err)
+			}
+		}
+	}
+
+	return problems, nil
+}
@@ -0,0 +1,146 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/control"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/urfave/cli/v3"
+)
+
+var Commands = &cli.Command{
+	Name:  "ctl",
+	Usage: "Commands for controlling the chains of a running daemon (see 'plugins run daemon')",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "socket", Usage: "The path of the daemon's control socket (defaults to '" + control.SOCKET_FILE_NAME + "' in the CLI config directory)", Sources: cli.EnvVars("CC_SOCKET"), Required: false},
+	},
+	Commands: []*cli.Command{
+		list,
+		status,
+		action("start", "Starts a chain that has stopped", (*control.Client).Start),
+		action("stop", "Stops a chain (it stays stopped until it is started again or its config changes)", (*control.Client).Stop),
+		action("restart", "Restarts a chain's plugin", (*control.Client).Restart),
+		cursor,
+		health,
+		logs,
+	},
+}
+
+func nameFlag() cli.Flag {
+	return &cli.StringFlag{Name: "name", Usage: "The name of the chain", Aliases: []string{"n"}, Sources: cli.EnvVars("CHAIN"), Required: true}
+}
+
+var list = &cli.Command{
+	Name:  "list",
+	Usage: "Lists the chains that the daemon manages along with their state and latest cursor",
+	Action: func(ctx context.Context, c *cli.Command) error {
+		return printResult(c, func() (any, error) {
+			return client(c).Chains(ctx)
+		})
+	},
+}
+
+var status = &cli.Command{
+	Name:  "status",
+	Usage: "Shows the state and latest cursor of a chain",
+	Flags: []cli.Flag{nameFlag()},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		return printResult(c, func() (any, error) {
+			return client(c).Chain(ctx, c.String("name"))
+		})
+	},
+}
+
+var cursor = &cli.Command{
+	Name:  "cursor",
+	Usage: "Shows the latest cursor that the daemon received from a chain",
+	Flags: []cli.Flag{nameFlag()},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		return printResult(c, func() (any, error) {
+			return client(c).Cursor(ctx, c.String("name"))
+		})
+	},
+}
+
+var health = &cli.Command{
+	Name:  "health",
+	Usage: "Checks whether chains are running and streaming cursors (exits with a non-zero code if any chain is unhealthy)",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "name", Usage: "The name of the chain (defaults to all chains)", Aliases: []string{"n"}, Sources: cli.EnvVars("CHAIN"), Required: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		healths, err := client(c).Health(ctx, c.String("name"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		if err := core.PrintResult(c, healths); err != nil {
+			return core.ErrExit(err)
+		}
+
+		failures := 0
+		for _, h := range healths {
+			if !h.Healthy {
+				failures += 1
+			}
+		}
+
+		if failures != 0 {
+			return core.ErrExitWithCode(fmt.Errorf("%d chain(s) are unhealthy", failures), core.CODE_CHECK_FAILED, core.EXIT_ERROR)
+		} else {
+			return nil
+		}
+	},
+}
+
+var logs = &cli.Command{
+	Name:  "logs",
+	Usage: "Prints the recent output of a chain's plugin and supervisor",
+	Flags: []cli.Flag{
+		nameFlag(),
+		&cli.IntFlag{Name: "lines", Usage: "The number of lines to print (defaults to every line that the daemon keeps)", Required: false, Value: 0},
+		&cli.BoolFlag{Name: "follow", Usage: "If specified, keep printing new lines until interrupted", Aliases: []string{"f"}, Required: false, Value: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		if err := client(c).Logs(ctx, c.String("name"), int(c.Int("lines")), c.Bool("follow"), c.Root().Writer); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+func action(name string, usage string, do func(client *control.Client, ctx context.Context, chainName string) (*control.ChainStatus, error)) *cli.Command {
+	return &cli.Command{
+		Name:  name,
+		Usage: usage,
+		Flags: []cli.Flag{nameFlag()},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return printResult(c, func() (any, error) {
+				return do(client(c), ctx, c.String("name"))
+			})
+		},
+	}
+}
+
+func client(c *cli.Command) *control.Client {
+	socket := c.String("socket")
+	if socket == "" {
+		socket = control.DefaultSocket()
+	}
+	return control.NewClient(socket)
+}
+
+func printResult(c *cli.Command, get func() (any, error)) error {
+	result, err := get()
+	if err != nil {
+		return core.ErrExit(err)
+	}
+
+	if err := core.PrintResult(c, result); err != nil {
+		return core.ErrExit(err)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,123 @@
+package deploy
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/deploy"
+	"github.com/urfave/cli/v3"
+)
+
+// GenerateResult lists the files that were written and anything that may stop the
+// deployed chains from working
+type GenerateResult struct {
+	Files    []string
+	Warnings []string
+}
+
+var Commands = &cli.Command{
+	Name:  "deploy",
+	Usage: "Commands for deploying chains",
+	Commands: []*cli.Command{
+		generate,
+	},
+}
+
+var generate = &cli.Command{
+	Name:  "generate",
+	Usage: "Generates systemd units, a compose file or Kubernetes manifests that run chains from a config file",
+	Description: "Every chain runs as its own 'cc plugins run from-config' process. The artifacts reference the config file " +
+		"as it was written (in '" + deploy.CONFIG_DIR + "'), so the values of the environment variables that it " +
+		"references are never written to them. Instead, they are passed through from the environment (systemd and " +
+		"compose) or read from a secret (Kubernetes).",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+		&cli.StringFlag{Name: "target", Usage: "The deployment target (" + strings.Join(deploy.Targets(), ", ") + ")", Aliases: []string{"t"}, Required: true},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to deploy (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.StringFlag{Name: "out", Usage: "The directory to write the files to (if omitted, the files are printed instead)", Aliases: []string{"o"}, Required: false},
+		&cli.StringFlag{Name: "image", Usage: "The container image to run (compose and kubernetes only)", Required: false, Value: deploy.DEFAULT_IMAGE_REPOSITORY + ":" + core.VersionWithoutPrefix()},
+		&cli.StringFlag{Name: "binary", Usage: "The path of the CLI binary on the target hosts (systemd only)", Required: false, Value: deploy.DEFAULT_BINARY},
+		&cli.StringFlag{Name: "namespace", Usage: "The namespace of the resources (kubernetes only)", Required: false},
+		&cli.StringFlag{Name: "secret", Usage: "The secret that holds the environment variables that the config references (kubernetes only)", Required: false, Value: deploy.DEFAULT_SECRET},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		configPath := c.String("config")
+		outDir := c.String("out")
+
+		src, err := deploy.ReadSource(configPath)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		result, err := deploy.Generate(c.String("target"), src, deploy.Options{
+			Chains:       c.StringSlice("name"),
+			Image:        c.String("image"),
+			Binary:       c.String("binary"),
+			ConfigSource: configSource(configPath, outDir),
+			Namespace:    c.String("namespace"),
+			Secret:       c.String("secret"),
+		})
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		if outDir == "" {
+			for _, warning := range result.Warnings {
+				fmt.Fprintf(c.Root().ErrWriter, "WARNING: %s\n", warning)
+			}
+			if _, err := fmt.Fprint(c.Root().Writer, deploy.Render(result.Files)); err != nil {
+				return core.ErrExit(err)
+			} else {
+				return nil
+			}
+		}
+
+		if err := os.MkdirAll(outDir, os.ModePerm); err != nil {
+			return core.ErrExit(err)
+		}
+
+		written := []string{}
+		for _, file := range result.Files {
+			filePath := filepath.Join(outDir, file.Path)
+			if err := os.WriteFile(filePath, []byte(file.Content), 0644); err != nil {
+				return core.ErrExit(err)
+			} else {
+				written = append(written, filePath)
+			}
+		}
+
+		if err := core.PrintResult(c, &GenerateResult{Files: written, Warnings: result.Warnings}); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// configSource returns the path of the config file relative to the directory that the
+// files are written to (which is where compose resolves relative paths from)
+func configSource(configPath string, outDir string) string {
+	if outDir == "" {
+		outDir = "."
+	}
+
+	absConfig, err := filepath.Abs(configPath)
+	if err != nil {
+		return configPath
+	}
+
+	absOut, err := filepath.Abs(outDir)
+	if err != nil {
+		return absConfig
+	}
+
+	if rel, err := filepath.Rel(absOut, absConfig); err != nil {
+		return absConfig
+	} else {
+		return filepath.ToSlash(rel)
+	}
+}
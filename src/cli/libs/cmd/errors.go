@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"io/fs"
+	"net"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/deploy"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/gh"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+	"github.com/urfave/cli/v3"
+)
+
+// errorCodes maps the errors that commands can fail with to the codes and exit
+// statuses that they are reported with (the first match wins)
+var errorCodes = []struct {
+	target error
+	code   string
+	status int
+}{
+	{target: config.ValidationErrors{}, code: core.CODE_CONFIG, status: core.EXIT_CONFIG},
+	{target: &config.ValidationError{}, code: core.CODE_CONFIG, status: core.EXIT_CONFIG},
+	{target: &config.ProfileNotFoundError{}, code: core.CODE_CONFIG, status: core.EXIT_CONFIG},
+	{target: &config.ProfileCycleError{}, code: core.CODE_CONFIG, status: core.EXIT_CONFIG},
+	{target: &integrity.ChecksumMismatchError{}, code: core.CODE_INTEGRITY, status: core.EXIT_INTEGRITY},
+	{target: &integrity.SignatureError{}, code: core.CODE_INTEGRITY, status: core.EXIT_INTEGRITY},
+	{target: &plgn.UnsafeArchiveError{}, code: core.CODE_INTEGRITY, status: core.EXIT_INTEGRITY},
+	{target: &handshake.IncompatibleProtocolError{}, code: core.CODE_INCOMPATIBLE, status: core.EXIT_ERROR},
+	{target: &plgn.PluginNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &registry.AssetNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &registry.ReleaseNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &gh.NotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &config.TemplateNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &deploy.TargetNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: &fleet.ChainNotFoundError{}, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+	{target: fs.ErrNotExist, code: core.CODE_NOT_FOUND, status: core.EXIT_NOT_FOUND},
+}
+
+// HandleError prints the error that the CLI failed with in the selected output format
+// and returns the status that the CLI should exit with. Errors that commands did not
+// fail with (e.g. unknown flags) are usage errors.
+func HandleError(c *cli.Command, err error) int {
+	result := &core.ErrorResult{Code: core.CODE_USAGE, Message: err.Error(), ExitCode: core.EXIT_USAGE}
+
+	cmdErr := &core.CommandError{}
+	if errors.As(err, &cmdErr) {
+		result.Code, result.ExitCode = classify(cmdErr)
+	}
+
+	// NOTE: the error is already as visible as it can be if it cannot be printed
+	_ = core.PrintError(c, result)
+	return result.ExitCode
+}
+
+func classify(err *core.CommandError) (string, int) {
+	if err.Code != "" {
+		return err.Code, err.ExitCode()
+	}
+
+	// NOTE: the run commands exit with the exit code of a plugin that failed
+	exitErr := &supervisor.ExitError{}
+	if errors.As(err, &exitErr) && exitErr.Code > 0 {
+		return core.CODE_PLUGIN_EXITED, exitErr.Code
+	}
+
+	// NOTE: a missing unix socket is an unreachable service rather than a missing file
+	opErr := &net.OpError{}
+	if errors.As(err, &opErr) {
+		return core.CODE_UNAVAILABLE, core.EXIT_UNAVAILABLE
+	}
+
+	for _, errorCode := range errorCodes {
+		if errors.Is(err, errorCode.target) {
+			return errorCode.code, errorCode.status
+		}
+	}
+
+	return core.CODE_ERROR, core.EXIT_ERROR
+}
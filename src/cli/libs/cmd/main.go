@@ -1,9 +1,18 @@
 package cmd
 
 import (
+	"context"
+	"strings"
+
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/common"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/ctl"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/deploy"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/tail"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/dirs"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
 	"github.com/urfave/cli/v3"
 )
 
@@ -11,8 +20,43 @@ var Commands = &cli.Command{
 	Name:    "Chain Connectors",
 	Usage:   "CLI",
 	Version: core.VersionWithPrefix(),
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "home", Usage: "The directory to keep all CLI data in (config, cache and plugins)", Sources: cli.EnvVars("CC_HOME"), Required: false},
+		&cli.BoolFlag{Name: "portable", Usage: "If specified, keep all CLI data in the '" + dirs.PORTABLE_DIR + "' directory next to the CLI binary", Sources: cli.EnvVars("CC_PORTABLE"), Required: false, Value: false},
+		&cli.StringFlag{Name: "config-dir", Usage: "The directory to keep CLI config data in (overrides --home)", Sources: cli.EnvVars("CC_CONFIG_DIR"), Required: false},
+		&cli.StringFlag{Name: "cache-dir", Usage: "The directory to keep CLI cache data in (overrides --home)", Sources: cli.EnvVars("CC_CACHE_DIR"), Required: false},
+		&cli.StringFlag{Name: core.OUTPUT_FLAG, Usage: "The output format (" + strings.Join(core.OutputFormats, ", ") + ")", Sources: cli.EnvVars("CC_OUTPUT"), Required: false, Value: core.OUTPUT_JSON},
+		&cli.StringFlag{Name: "plugins-dir", Usage: "The directory to install plugins in (overrides --home and --config-dir)", Sources: cli.EnvVars("CC_PLUGINS_DIR"), Required: false},
+	},
+	Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+		if err := core.CheckOutputFormat(c.String(core.OUTPUT_FLAG)); err != nil {
+			return ctx, err
+		}
+
+		err := dirs.Init(dirs.Options{
+			Home:       c.String("home"),
+			Portable:   c.Bool("portable"),
+			ConfigDir:  c.String("config-dir"),
+			CacheDir:   c.String("cache-dir"),
+			PluginsDir: c.String("plugins-dir"),
+		})
+		if err != nil {
+			return ctx, core.ErrExit(err)
+		}
+
+		// NOTE: the plugin store and cache were created before the flags were parsed
+		plgn.Store.Dir = dirs.PluginsConfig
+		plgn.Cache.Dir = dirs.PluginsCache
+		return ctx, nil
+	},
+	// NOTE: errors are printed by HandleError (in the selected output format) instead
+	ExitErrHandler: func(ctx context.Context, c *cli.Command, err error) {},
 	Commands: append(
 		common.Commands,
 		plugins.Commands,
+		config.Commands,
+		tail.Commands,
+		ctl.Commands,
+		deploy.Commands,
 	),
 }
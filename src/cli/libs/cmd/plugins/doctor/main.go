@@ -0,0 +1,97 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/doctor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/urfave/cli/v3"
+)
+
+var Commands = &cli.Command{
+	Name:  "doctor",
+	Usage: "Diagnoses problems with installed plugins and the chains that they serve",
+	Description: "Without a config file, only the installed plugins are checked. With a config file, each chain's " +
+		"config is also checked against its plugin's manifest, the chain's endpoints are tested for connectivity, and " +
+		"the plugin is started on a random local port to check that it completes the handshake.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file whose chains are diagnosed", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to diagnose (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of a plugin to diagnose, optionally with a version (defaults to all plugins if no config is given)", Required: false},
+		&cli.DurationFlag{Name: "timeout", Usage: "How long each connectivity check and handshake may take", Required: false, Value: doctor.DEFAULT_TIMEOUT},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		reports := []*doctor.Report{}
+
+		if c.String("config") != "" {
+			chainReports, err := diagnoseChains(ctx, c)
+			if err != nil {
+				return core.ErrExit(err)
+			} else {
+				reports = append(reports, chainReports...)
+			}
+		}
+
+		pluginIDs := c.StringSlice("plugin-id")
+		if len(pluginIDs) == 0 && c.String("config") == "" {
+			if ids, err := plgn.IDs(); err != nil {
+				return core.ErrExit(err)
+			} else {
+				pluginIDs = ids
+			}
+		}
+
+		for _, pluginID := range pluginIDs {
+			ref, err := plgn.ParseRef(pluginID)
+			if err != nil {
+				return core.ErrExit(err)
+			} else {
+				reports = append(reports, doctor.DiagnosePlugin(&plgn.Store, ref))
+			}
+		}
+
+		if err := core.PrintResult(c, reports); err != nil {
+			return core.ErrExit(err)
+		}
+
+		failures := 0
+		for _, report := range reports {
+			if !report.Ok {
+				failures += 1
+			}
+		}
+
+		if failures != 0 {
+			return core.ErrExitWithCode(fmt.Errorf("found problems with %d chain(s) or plugin(s)", failures), core.CODE_CHECK_FAILED, core.EXIT_ERROR)
+		} else {
+			return nil
+		}
+	},
+}
+
+func diagnoseChains(ctx context.Context, c *cli.Command) ([]*doctor.Report, error) {
+	cliConfig, err := config.ParseCliConfig(c.String("config"))
+	if err != nil {
+		return nil, err
+	}
+
+	chainNames := c.StringSlice("name")
+	if len(chainNames) == 0 {
+		chainNames = cliConfig.ChainNames()
+	}
+
+	reports := make([]*doctor.Report, len(chainNames))
+	for i, chainName := range chainNames {
+		conf, err := cliConfig.Chain(chainName)
+		if err != nil {
+			return nil, err
+		} else {
+			reports[i] = doctor.Diagnose(ctx, &plgn.Store, chainName, conf, c.Duration("timeout"))
+		}
+	}
+
+	return reports, nil
+}
@@ -0,0 +1,48 @@
+package info
+
+import (
+	"context"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/urfave/cli/v3"
+)
+
+var Commands = &cli.Command{
+	Name:  "info",
+	Usage: "Shows the path, version, size, checksum, install source and manifest of installed plugins",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to show, optionally with a version (defaults to all plugins)", Required: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		pluginIDs := c.StringSlice("plugin-id")
+		if len(pluginIDs) == 0 {
+			if ids, err := plgn.IDs(); err != nil {
+				return core.ErrExit(err)
+			} else {
+				pluginIDs = ids
+			}
+		}
+
+		results := make([]*plgn.PluginInfo, len(pluginIDs))
+		for i, pluginID := range pluginIDs {
+			ref, err := plgn.ParseRef(pluginID)
+			if err != nil {
+				return core.ErrExit(err)
+			}
+
+			info, err := plgn.Store.Info(ref)
+			if err != nil {
+				return core.ErrExit(err)
+			} else {
+				results[i] = info
+			}
+		}
+
+		if err := core.PrintResult(c, results); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
@@ -5,57 +5,86 @@ import (
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
 	"github.com/urfave/cli/v3"
 	"golang.org/x/sync/errgroup"
 )
 
 var github = &cli.Command{
 	Name:  "github",
-	Usage: "Installs a plugin from github",
+	Usage: "Installs a plugin from github (or from the registries defined in a config file)",
 	Flags: []cli.Flag{
-		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to install", Required: true},
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to install (optionally with a version, e.g. eth@1.2.0)", Required: true},
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file whose registries are searched (defaults to github)", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
 		&cli.BoolFlag{Name: "clean", Usage: "If the plugin already exists, then remove it and re-install it", Required: false, Value: false},
 		&cli.IntFlag{Name: "concurrency", Usage: "The maximum number of concurrent requests", Required: false, Value: 0},
+		&cli.StringFlag{Name: "public-key", Usage: "A minisign public key (or the path to one) used to verify release signatures", Sources: cli.EnvVars("PLUGIN_PUBLIC_KEY"), Required: false},
+		&cli.BoolFlag{Name: "allow-unverified", Usage: "Allow installing plugins from releases that do not have a checksums file (e.g. older releases)", Sources: cli.EnvVars("PLUGIN_ALLOW_UNVERIFIED"), Required: false},
 	},
 	Action: func(ctx context.Context, c *cli.Command) error {
-		concurrency := c.Int("concurrency")
-		clean := c.Bool("clean")
-
-		eg := new(errgroup.Group)
-		if concurrency > 0 {
-			eg.SetLimit(int(concurrency))
+		registries, err := registry.FromConfigFile(c.String("config"))
+		if err != nil {
+			return core.ErrExit(err)
 		}
 
-		for _, pluginID := range c.StringSlice("plugin-id") {
-			eg.Go(func() error {
-				if clean {
-					if err := plgn.Store.Remove(pluginID); err != nil {
-						return err
-					}
-				}
-
-				pluginPaths, err := plgn.Cache.Download(ctx, pluginID)
-				if err != nil {
-					return err
-				} else {
-					return plgn.Store.Install(pluginPaths)
-				}
-			})
+		opts, err := plgn.NewDownloadOptions(c.String("public-key"), registries)
+		if err != nil {
+			return core.ErrExit(err)
 		}
+		opts.AllowUnverified = c.Bool("allow-unverified")
 
-		if err := eg.Wait(); err != nil {
+		if err := download(ctx, c, opts); err != nil {
 			return core.ErrExit(err)
+		} else {
+			return nil
 		}
+	},
+}
 
-		ids, err := plgn.IDs()
+// download installs the plugins passed in with the 'plugin-id' flag and then prints
+// all installed plugins. Plugins without a version are installed at the version that
+// matches the CLI.
+func download(ctx context.Context, c *cli.Command, opts plgn.DownloadOptions) error {
+	concurrency := c.Int("concurrency")
+	clean := c.Bool("clean")
+
+	eg := new(errgroup.Group)
+	if concurrency > 0 {
+		eg.SetLimit(int(concurrency))
+	}
+
+	for _, pluginID := range c.StringSlice("plugin-id") {
+		ref, err := plgn.ParseRef(pluginID)
 		if err != nil {
-			return core.ErrExit(err)
+			return err
+		} else {
+			ref = ref.WithDefaultVersion()
 		}
 
-		if err := core.PrintResults(c, ids); err != nil {
-			return core.ErrExit(err)
-		}
+		eg.Go(func() error {
+			if clean {
+				if err := plgn.Store.Remove(ctx, ref); err != nil {
+					return err
+				}
+			}
 
-		return nil
-	},
+			pluginPaths, source, err := plgn.Cache.Download(ctx, ref, opts)
+			if err != nil {
+				return err
+			} else {
+				return plgn.Store.Install(ctx, ref.Version, source, pluginPaths)
+			}
+		})
+	}
+
+	if err := eg.Wait(); err != nil {
+		return err
+	}
+
+	summaries, err := plgn.Store.Summaries()
+	if err != nil {
+		return err
+	}
+
+	return core.PrintResult(c, summaries)
 }
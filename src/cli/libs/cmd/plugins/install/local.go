@@ -16,11 +16,14 @@ var local = &cli.Command{
 		&cli.StringSliceFlag{Name: "plugin-path", Usage: "The path to the compiled plugin", Required: true},
 		&cli.BoolFlag{Name: "clean", Usage: "If the plugin already exists, then remove it and re-install it", Required: false, Value: false},
 		&cli.IntFlag{Name: "concurrency", Usage: "The maximum number of concurrent requests", Required: false, Value: 0},
+		&cli.StringFlag{Name: "version", Usage: "The version to install plugins without a manifest as (defaults to the CLI version) - plugins with a manifest are installed under the version in their manifest, which must match this version if it is set", Required: false},
 	},
 	Action: func(ctx context.Context, c *cli.Command) error {
 		concurrency := c.Int("concurrency")
 		clean := c.Bool("clean")
 
+		version := c.String("version")
+
 		eg := new(errgroup.Group)
 		if concurrency > 0 {
 			eg.SetLimit(int(concurrency))
@@ -28,15 +31,18 @@ var local = &cli.Command{
 
 		for _, pluginPath := range c.StringSlice("plugin-path") {
 			eg.Go(func() error {
-				pluginID := plgn.ID(pluginPath)
+				ref, err := plgn.FileRef(pluginPath, version)
+				if err != nil {
+					return err
+				}
 
 				if clean {
-					if err := plgn.Store.Remove(pluginID); err != nil {
+					if err := plgn.Store.Remove(ctx, ref); err != nil {
 						return err
 					}
 				}
 
-				return plgn.Store.Install([]string{pluginPath})
+				return plgn.Store.InstallFile(ctx, ref, pluginPath)
 			})
 		}
 
@@ -44,12 +50,12 @@ var local = &cli.Command{
 			return core.ErrExit(err)
 		}
 
-		ids, err := plgn.IDs()
+		summaries, err := plgn.Store.Summaries()
 		if err != nil {
 			return core.ErrExit(err)
 		}
 
-		if err := core.PrintResults(c, ids); err != nil {
+		if err := core.PrintResult(c, summaries); err != nil {
 			return core.ErrExit(err)
 		}
 
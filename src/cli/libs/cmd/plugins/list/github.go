@@ -2,29 +2,46 @@ package list
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
 	"github.com/urfave/cli/v3"
 )
 
 var github = &cli.Command{
 	Name:  "github",
-	Usage: "Lists all plugins that can be downloaded from Github",
+	Usage: "Lists all plugins that can be downloaded from Github (or from the registries defined in a config file)",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file whose registries are listed (defaults to github)", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
+	},
 	Action: func(ctx context.Context, c *cli.Command) error {
-		release, err := core.GithubClient.GetReleaseByTag(ctx, core.VersionWithPrefix())
+		registries, err := registry.FromConfigFile(c.String("config"))
 		if err != nil {
 			return core.ErrExit(err)
 		}
 
-		names := []string{}
-		for _, asset := range release.Assets {
-			if plgn.IsPluginReleaseAssetName(asset.Name) {
-				names = append(names, asset.Name)
+		// NOTE: registries are listed in order of priority, so the first registry that
+		// lists an asset is the one it will be installed from
+		results := []*AssetResult{}
+		for _, entry := range registries {
+			assets, err := entry.Assets(ctx, core.VersionWithPrefix())
+			if errors.Is(err, &registry.ReleaseNotFoundError{}) {
+				continue
+			}
+			if err != nil {
+				return core.ErrExit(fmt.Errorf("registry '%s': %w", entry.Name, err))
+			}
+
+			for _, asset := range assets {
+				if result := assetResult(asset, entry.Name); result != nil {
+					results = append(results, result)
+				}
 			}
 		}
 
-		if err := core.PrintResults(c, names); err != nil {
+		if err := core.PrintResult(c, results); err != nil {
 			return core.ErrExit(err)
 		} else {
 			return nil
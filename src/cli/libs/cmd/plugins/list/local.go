@@ -10,14 +10,14 @@ import (
 
 var local = &cli.Command{
 	Name:  "local",
-	Usage: "Lists the IDs of all locally installed plugins",
+	Usage: "Lists all locally installed plugins along with their manifests",
 	Action: func(ctx context.Context, c *cli.Command) error {
-		ids, err := plgn.IDs()
+		summaries, err := plgn.Store.Summaries()
 		if err != nil {
 			return core.ErrExit(err)
 		}
 
-		if err := core.PrintResults(c, ids); err != nil {
+		if err := core.PrintResult(c, summaries); err != nil {
 			return core.ErrExit(err)
 		} else {
 			return nil
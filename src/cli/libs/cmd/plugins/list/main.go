@@ -1,6 +1,17 @@
 package list
 
-import "github.com/urfave/cli/v3"
+import (
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/urfave/cli/v3"
+)
+
+// AssetResult is a plugin archive that can be downloaded from a registry
+type AssetResult struct {
+	ID       string `json:"id"`
+	Version  string `json:"version"`
+	Asset    string `json:"asset"`
+	Registry string `json:"registry"`
+}
 
 var Commands = &cli.Command{
 	Name:  "list",
@@ -10,3 +21,14 @@ var Commands = &cli.Command{
 		local,
 	},
 }
+
+// assetResult returns nil if the asset is not a plugin archive of the CLI version that
+// was built for the current platform
+func assetResult(assetName string, registryName string) *AssetResult {
+	if !plgn.IsPluginReleaseAssetName(assetName, plgn.DefaultVersion()) {
+		return nil
+	}
+
+	ref, _ := plgn.ParsePluginReleaseAssetName(assetName)
+	return &AssetResult{ID: ref.ID, Version: ref.Version, Asset: assetName, Registry: registryName}
+}
@@ -1,10 +1,15 @@
 package plugins
 
 import (
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/doctor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/info"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/install"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/list"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/migrate"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/remove"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/run"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/upgrade"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/cmd/plugins/verify"
 	"github.com/urfave/cli/v3"
 )
 
@@ -16,5 +21,10 @@ var Commands = &cli.Command{
 		remove.Commands,
 		list.Commands,
 		run.Commands,
+		verify.Commands,
+		upgrade.Commands,
+		info.Commands,
+		doctor.Commands,
+		migrate.Commands,
 	},
 }
@@ -0,0 +1,76 @@
+package migrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/dirs"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/urfave/cli/v3"
+)
+
+var Commands = &cli.Command{
+	Name:  "migrate",
+	Usage: "Copies installed plugins from another CLI version's (or another home directory's) plugins directory",
+	Description: "The source is either a CLI version (e.g. v1.0.0), whose plugins directory is looked up in the current " +
+		"config directory, or the path of a plugins directory. Plugins that were installed before plugins were versioned " +
+		"are installed under the version that they report or, if they do not report one, under --version.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "from", Usage: "The CLI version or plugins directory to copy plugins from", Required: true},
+		&cli.StringFlag{Name: "version", Usage: "The version to install unversioned plugins as (defaults to the CLI version in --from or the current CLI version)", Required: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		srcDir, version, err := source(c.String("from"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		if c.IsSet("version") {
+			version = c.String("version")
+		}
+
+		refs, err := plgn.Store.Migrate(ctx, srcDir, version)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		summaries := make([]*plgn.PluginSummary, len(refs))
+		for i, ref := range refs {
+			if summary, err := plgn.Store.Summary(ref); err != nil {
+				return core.ErrExit(err)
+			} else {
+				summaries[i] = summary
+			}
+		}
+
+		if err := core.PrintResult(c, summaries); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// source returns the plugins directory to migrate from along with the default version
+// of its unversioned plugins
+func source(from string) (string, string, error) {
+	if info, err := os.Stat(from); err == nil && info.IsDir() {
+		return from, plgn.DefaultVersion(), nil
+	}
+
+	if !plgn.IsValidVersion(from) {
+		return "", "", fmt.Errorf("'%s' is neither a directory nor a CLI version", from)
+	}
+
+	// NOTE: before plugins were versioned, each CLI version had its own plugins
+	// directory in its config directory
+	version, err := plgn.NormalizeVersion(from)
+	if err != nil {
+		return "", "", err
+	} else {
+		return filepath.Join(dirs.ConfigRoot(), "v"+version, dirs.PLUGINS_DIR), version, nil
+	}
+}
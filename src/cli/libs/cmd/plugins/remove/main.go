@@ -12,7 +12,7 @@ var Commands = &cli.Command{
 	Name:  "remove",
 	Usage: "Removes a locally installed plugin",
 	Flags: []cli.Flag{
-		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to remove", Required: false},
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to remove (all of its versions unless a version is given, e.g. eth@1.2.0)", Required: false},
 		&cli.BoolFlag{Name: "all", Usage: "Remove all plugins", Aliases: []string{"a"}, Required: false},
 	},
 	Action: func(ctx context.Context, c *cli.Command) error {
@@ -27,17 +27,22 @@ var Commands = &cli.Command{
 		}
 
 		for _, pluginID := range pluginIDs {
-			if err := plgn.Store.Remove(pluginID); err != nil {
+			ref, err := plgn.ParseRef(pluginID)
+			if err != nil {
+				return core.ErrExit(err)
+			}
+
+			if err := plgn.Store.Remove(ctx, ref); err != nil {
 				return core.ErrExit(err)
 			}
 		}
 
-		ids, err := plgn.IDs()
+		summaries, err := plgn.Store.Summaries()
 		if err != nil {
 			return core.ErrExit(err)
 		}
 
-		if err := core.PrintResults(c, ids); err != nil {
+		if err := core.PrintResult(c, summaries); err != nil {
 			return core.ErrExit(err)
 		}
 
@@ -0,0 +1,100 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/urfave/cli/v3"
+)
+
+var all = &cli.Command{
+	Name:  "all",
+	Usage: "Run all chains (or a subset of them) that are defined in a config file",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to run (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.BoolFlag{Name: "fail-fast", Usage: "If specified, stop all chains as soon as one of them fails permanently", Required: false, Value: false},
+	}, slices.Concat(reloadFlags, installFlags, supervisorFlags)...),
+	Action: func(ctx context.Context, c *cli.Command) error {
+		names := c.StringSlice("name")
+		cliConfig, chainNames, err := loadConfig(ctx, c, names, nil)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		f := fleet.New(ctx, fleet.Options{
+			FailFast: c.Bool("fail-fast"),
+			Output:   prefixedOutput,
+			Configure: func(conf *config.ChainConfig) {
+				applySupervisorFlags(c, conf)
+			},
+			Logger: reloadLogger(),
+		})
+
+		f.Apply(ctx, cliConfig, chainNames)
+		go watchConfig(ctx, c, f, names, nil)
+		if err := f.Wait(); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+func selectChains(cliConfig *config.CliConfig, names []string) ([]string, error) {
+	if len(names) == 0 {
+		return cliConfig.ChainNames(), nil
+	}
+
+	// NOTE: a chain that is named more than once is only run once - otherwise both
+	// supervisors would start the same chain on the same port
+	selected := []string{}
+	for _, name := range names {
+		if _, err := cliConfig.Chain(name); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(selected, name) {
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+// installAll installs the plugins of the given chains and validates the chain configs
+// against the plugins' manifests. Several chains can share the same plugin, so
+// plugins are installed up front (one at a time) rather than racing each other
+// inside the supervisors.
+func installAll(ctx context.Context, c *cli.Command, cliConfig *config.CliConfig, chainNames []string) error {
+	registries, err := registry.FromConfig(cliConfig.Registries)
+	if err != nil {
+		return err
+	}
+
+	for _, chainName := range chainNames {
+		chainConfig := cliConfig.Chains[chainName]
+		if err := prepare(ctx, c, registries, &chainConfig); err != nil {
+			return fmt.Errorf("chain '%s': %w", chainName, err)
+		}
+	}
+	return nil
+}
+
+// prefixedOutput prefixes each line of a chain's output with the chain name
+func prefixedOutput(chainName string) plgn.ProcessOptions {
+	prefix := fmt.Sprintf("[%s] ", chainName)
+	return plgn.ProcessOptions{
+		Stdout: core.NewPrefixWriter(os.Stdout, prefix),
+		Stderr: core.NewPrefixWriter(os.Stderr, prefix),
+	}
+}
@@ -0,0 +1,85 @@
+package run
+
+import (
+	"context"
+	"log"
+	"os"
+	"slices"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/control"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/sync/errgroup"
+)
+
+var daemon = &cli.Command{
+	Name:  "daemon",
+	Usage: "Run chains from a config file in a long-lived process that can be controlled with 'cc ctl'",
+	Description: "The daemon serves a control API over a unix socket (by default in the CLI config directory). Chains keep " +
+		"running until they are stopped through the API or removed from the config, and the daemon keeps running until it " +
+		"receives SIGINT or SIGTERM, even if every chain has stopped.",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to run (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.StringFlag{Name: "socket", Usage: "The path of the control socket (defaults to '" + control.SOCKET_FILE_NAME + "' in the CLI config directory)", Sources: cli.EnvVars("CC_SOCKET"), Required: false},
+	}, slices.Concat(reloadFlags, installFlags, supervisorFlags)...),
+	Action: func(ctx context.Context, c *cli.Command) error {
+		names := c.StringSlice("name")
+		cliConfig, chainNames, err := loadConfig(ctx, c, names, nil)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		socket := c.String("socket")
+		if socket == "" {
+			socket = control.DefaultSocket()
+		}
+		lis, err := control.Listen(ctx, socket)
+		if err != nil {
+			return core.ErrExit(err)
+		} else {
+			defer lis.Close()
+		}
+
+		logger := log.New(os.Stderr, "[daemon] ", log.LstdFlags)
+		server := control.NewServer(ctx, logger)
+
+		eg, egCtx := errgroup.WithContext(ctx)
+		f := fleet.New(egCtx, fleet.Options{
+			Output: func(chainName string) plgn.ProcessOptions {
+				return server.Output(chainName, prefixedOutput(chainName))
+			},
+			Configure: func(conf *config.ChainConfig) {
+				applySupervisorFlags(c, conf)
+			},
+			Hooks:     server.Hooks,
+			KeepAlive: true,
+			Logger:    reloadLogger(),
+		})
+		f.Apply(egCtx, cliConfig, chainNames)
+
+		eg.Go(func() error {
+			return server.Serve(egCtx, lis, f)
+		})
+		eg.Go(func() error {
+			// NOTE: failed chains are reported through the control API, so the daemon
+			// does not exit with an error because of them
+			f.Wait()
+			return nil
+		})
+
+		watchCtx, cancel := context.WithCancel(egCtx)
+		defer cancel()
+		go watchConfig(watchCtx, c, f, names, nil)
+
+		logger.Printf("Listening on %s\n", socket)
+		if err := eg.Wait(); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
@@ -5,20 +5,23 @@ import (
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
 	"github.com/urfave/cli/v3"
 )
 
 var fromCLI = &cli.Command{
 	Name:  "from-cli",
 	Usage: "Run a plugin using the configurations passed in to this command",
-	Flags: []cli.Flag{
-		&cli.StringFlag{Name: "plugin-id", Usage: "The ID of the plugin to run", Sources: cli.EnvVars("PLUGIN_ID"), Required: true},
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{Name: "plugin-id", Usage: "The ID of the plugin to run (optionally with a version, e.g. eth@1.2.0)", Sources: cli.EnvVars("PLUGIN_ID"), Required: true},
 		&cli.StringFlag{Name: "server-host", Usage: "The server host", Sources: cli.EnvVars("SERVER_HOST"), Required: false, Value: "0.0.0.0"},
 		&cli.IntFlag{Name: "server-port", Usage: "The server port", Sources: cli.EnvVars("SERVER_PORT"), Required: false, Value: 3000},
 		&cli.StringFlag{Name: "chain-wss", Usage: "The chain WSS URL", Sources: cli.EnvVars("CHAIN_WSS_URL"), Required: false},
-		&cli.StringFlag{Name: "chain-rpc", Usage: "The chain RPC URL", Sources: cli.EnvVars("CHAIN_RPC_URL"), Required: false},
-	},
+		&cli.StringFlag{Name: "chain-rpc", Usage: "The chain RPC URL (some plugins also accept an IPC socket path)", Sources: cli.EnvVars("CHAIN_RPC_URL"), Required: false},
+		&cli.StringFlag{Name: "chain-finality", Usage: "The finality level to track (only supported by some plugins)", Sources: cli.EnvVars("CHAIN_FINALITY"), Required: false},
+	}, append(installFlags, supervisorFlags...)...),
 	Action: func(ctx context.Context, c *cli.Command) error {
 		pluginID := c.String("plugin-id")
 
@@ -34,25 +37,15 @@ var fromCLI = &cli.Command{
 				Wss: c.String("chain-wss"),
 				Rpc: c.String("chain-rpc"),
 			},
+			Finality: c.String("chain-finality"),
 		}
 
-		isInstalled, err := plgn.Store.IsInstalled(pluginID)
-		if err != nil {
+		applySupervisorFlags(c, conf)
+		if err := prepare(ctx, c, registry.Default(), conf); err != nil {
 			return core.ErrExit(err)
 		}
 
-		if !isInstalled {
-			pluginPaths, err := plgn.Cache.Download(ctx, pluginID)
-			if err != nil {
-				return core.ErrExit(err)
-			}
-
-			if err := plgn.Store.Install(pluginPaths); err != nil {
-				return core.ErrExit(err)
-			}
-		}
-
-		if err := plgn.Store.Exec(ctx, conf); err != nil {
+		if err := fleet.Supervise(ctx, fleet.NewChain(pluginID, conf), defaultOutput(), supervisor.Hooks{}); err != nil {
 			return core.ErrExit(err)
 		} else {
 			return nil
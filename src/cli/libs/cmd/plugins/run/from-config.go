@@ -2,9 +2,11 @@ package run
 
 import (
 	"context"
+	"slices"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
 	"github.com/urfave/cli/v3"
 )
@@ -12,36 +14,33 @@ import (
 var fromConfig = &cli.Command{
 	Name:  "from-config",
 	Usage: "Run a plugin using the configurations defined in a config file",
-	Flags: []cli.Flag{
+	Flags: append([]cli.Flag{
 		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
 		&cli.StringFlag{Name: "name", Usage: "The name of the chain", Aliases: []string{"n"}, Sources: cli.EnvVars("CHAIN"), Required: true},
-	},
+	}, slices.Concat(reloadFlags, installFlags, supervisorFlags)...),
 	Action: func(ctx context.Context, c *cli.Command) error {
-		configPath := c.String("config")
-		chainName := c.String("name")
-
-		chainConfig, err := config.ParseChainConfig(configPath, chainName)
+		names := []string{c.String("name")}
+		cliConfig, chainNames, err := loadConfig(ctx, c, names, nil)
 		if err != nil {
 			return core.ErrExit(err)
 		}
 
-		isInstalled, err := plgn.Store.IsInstalled(chainConfig.Plugin.ID)
-		if err != nil {
-			return core.ErrExit(err)
-		}
-
-		if !isInstalled {
-			pluginPaths, err := plgn.Cache.Download(ctx, chainConfig.Plugin.ID)
-			if err != nil {
-				return core.ErrExit(err)
-			}
-
-			if err := plgn.Store.Install(pluginPaths); err != nil {
-				return core.ErrExit(err)
-			}
-		}
-
-		if err := plgn.Store.Exec(ctx, chainConfig); err != nil {
+		ctx, cancel := context.WithCancel(ctx)
+		defer cancel()
+
+		f := fleet.New(ctx, fleet.Options{
+			Output: func(chainName string) plgn.ProcessOptions {
+				return defaultOutput()
+			},
+			Configure: func(conf *config.ChainConfig) {
+				applySupervisorFlags(c, conf)
+			},
+			Logger: reloadLogger(),
+		})
+
+		f.Apply(ctx, cliConfig, chainNames)
+		go watchConfig(ctx, c, f, names, nil)
+		if err := f.Wait(); err != nil {
 			return core.ErrExit(err)
 		} else {
 			return nil
@@ -0,0 +1,116 @@
+package run
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"slices"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/gateway"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+	"github.com/urfave/cli/v3"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+var gatewayCmd = &cli.Command{
+	Name:  "gateway",
+	Usage: "Run chains from a config file behind a single gRPC endpoint that routes calls by chain name",
+	Flags: append([]cli.Flag{
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: true},
+		&cli.StringSliceFlag{Name: "name", Usage: "The name of a chain to run (defaults to all chains)", Aliases: []string{"n"}, Required: false},
+		&cli.StringFlag{Name: "host", Usage: "The gateway host", Sources: cli.EnvVars("GATEWAY_HOST"), Required: false, Value: "0.0.0.0"},
+		&cli.IntFlag{Name: "port", Usage: "The gateway port", Sources: cli.EnvVars("GATEWAY_PORT"), Required: false, Value: 8080},
+		&cli.BoolFlag{Name: "fail-fast", Usage: "If specified, stop all chains as soon as one of them fails permanently", Required: false, Value: false},
+	}, slices.Concat(reloadFlags, installFlags, supervisorFlags)...),
+	Action: func(ctx context.Context, c *cli.Command) error {
+		server := &config.ServerConfig{Host: c.String("host"), Port: c.Int("port")}
+		check := func(cliConfig *config.CliConfig, chainNames []string) error {
+			for _, chainName := range chainNames {
+				if chainServer := cliConfig.Chains[chainName].Server; chainServer != nil && server.Overlaps(chainServer) {
+					return fmt.Errorf("chain '%s' (%s) conflicts with the gateway address (%s)", chainName, chainServer.Url(), server.Url())
+				}
+			}
+			return nil
+		}
+
+		names := c.StringSlice("name")
+		cliConfig, chainNames, err := loadConfig(ctx, c, names, check)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		if err := runGateway(ctx, c, cliConfig, chainNames, server, check); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// runGateway supervises the given chains and serves a gateway that forwards calls
+// to whichever plugin process is currently serving the requested chain. Chains that
+// are added or removed by a config reload are added to or removed from the gateway.
+func runGateway(ctx context.Context, c *cli.Command, cliConfig *config.CliConfig, chainNames []string, server *config.ServerConfig, check func(cliConfig *config.CliConfig, chainNames []string) error) error {
+	logger := log.New(os.Stderr, "[gateway] ", log.LstdFlags)
+
+	gw := gateway.New()
+	defer gw.Close()
+
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", server.Url())
+	if err != nil {
+		return err
+	} else {
+		defer lis.Close()
+	}
+
+	grpcServer := grpc.NewServer()
+	gw.Register(grpcServer)
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	f := fleet.New(egCtx, fleet.Options{
+		FailFast: c.Bool("fail-fast"),
+		Output:   prefixedOutput,
+		Configure: func(conf *config.ChainConfig) {
+			applySupervisorFlags(c, conf)
+		},
+		Hooks: func(chainName string, conf *config.ChainConfig) supervisor.Hooks {
+			gw.AddChain(chainName, conf.Plugin.ID)
+			return supervisor.Hooks{
+				OnReady: func(proc *plgn.Process) {
+					if err := gw.SetReady(chainName, proc.Handshake.Address); err != nil {
+						logger.Printf("Failed to route chain '%s': %v", chainName, err)
+					}
+				},
+				OnExit: func(exitCode int, err error) {
+					gw.SetNotReady(chainName)
+				},
+			}
+		},
+		Removed: gw.RemoveChain,
+		Logger:  reloadLogger(),
+	})
+	f.Apply(egCtx, cliConfig, chainNames)
+
+	eg.Go(func() error {
+		return grpcServer.Serve(lis)
+	})
+	eg.Go(func() error {
+		// NOTE: the gateway has nothing left to route once every chain has stopped
+		defer grpcServer.GracefulStop()
+		return f.Wait()
+	})
+
+	watchCtx, cancel := context.WithCancel(egCtx)
+	defer cancel()
+	go watchConfig(watchCtx, c, f, c.StringSlice("name"), check)
+
+	logger.Printf("Listening on %s\n", lis.Addr().String())
+	return eg.Wait()
+}
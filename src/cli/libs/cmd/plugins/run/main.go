@@ -8,5 +8,8 @@ var Commands = &cli.Command{
 	Commands: []*cli.Command{
 		fromConfig,
 		fromCLI,
+		all,
+		gatewayCmd,
+		daemon,
 	},
 }
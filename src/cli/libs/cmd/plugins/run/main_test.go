@@ -0,0 +1,82 @@
+package run
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/urfave/cli/v3"
+)
+
+func TestApplySupervisorFlags(t *testing.T) {
+	// NOTE: chains can share a restart config (e.g. when it comes from the defaults)
+	shared := &config.RestartConfig{Policy: "always", MaxRestarts: 3}
+	eth := &config.ChainConfig{Restart: shared}
+	flow := &config.ChainConfig{Restart: shared}
+	solana := &config.ChainConfig{}
+
+	cmd := &cli.Command{
+		Flags: supervisorFlags,
+		Action: func(ctx context.Context, c *cli.Command) error {
+			for _, conf := range []*config.ChainConfig{eth, flow, solana} {
+				applySupervisorFlags(c, conf)
+			}
+			return nil
+		},
+	}
+	if err := cmd.Run(context.Background(), []string{"cc", "--restart", "never", "--backoff", "2s"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, conf := range []*config.ChainConfig{eth, flow, solana} {
+		if conf.Restart.Policy != "never" || conf.Restart.Backoff != config.Duration(time.Second*2) {
+			t.Fatalf("expected the flags to be applied but got: %+v", conf.Restart)
+		}
+	}
+	if eth.Restart.MaxRestarts != 3 {
+		t.Fatalf("expected the settings from the config to be kept but got: %+v", eth.Restart)
+	}
+	if eth.Restart == shared || eth.Restart == flow.Restart {
+		t.Fatal("expected the restart config to be copied")
+	}
+	if shared.Policy != "always" || shared.Backoff != 0 {
+		t.Fatalf("expected the shared restart config to be unchanged but got: %+v", shared)
+	}
+}
+
+func TestSelectChains(t *testing.T) {
+	cliConfig := &config.CliConfig{Chains: map[string]config.ChainConfig{"eth": {}, "flow": {}, "solana": {}}}
+
+	testCases := []struct {
+		name     string
+		names    []string
+		expected []string
+		err      string
+	}{
+		{name: "all chains", names: []string{}, expected: cliConfig.ChainNames()},
+		{name: "subset", names: []string{"solana", "eth"}, expected: []string{"solana", "eth"}},
+		{name: "duplicates", names: []string{"eth", "flow", "eth", "flow"}, expected: []string{"eth", "flow"}},
+		{name: "unknown chain", names: []string{"eth", "btc"}, err: "chain with name 'btc' does not exist"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			chainNames, err := selectChains(cliConfig, tc.names)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(chainNames, tc.expected) {
+				t.Fatalf("expected chains %v but got %v", tc.expected, chainNames)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package run
+
+import (
+	"context"
+	"crypto/sha256"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/urfave/cli/v3"
+)
+
+// WATCH_INTERVAL is how often the config file is checked for changes with --watch
+const WATCH_INTERVAL = time.Second
+
+var reloadFlags = []cli.Flag{
+	&cli.BoolFlag{Name: "watch", Usage: "If specified, reload the config file whenever it changes (the config is also reloaded on SIGHUP)", Sources: cli.EnvVars("WATCH_CONFIG"), Required: false, Value: false},
+}
+
+// loadConfig parses the config file, selects the chains to run, checks them for
+// conflicts (along with any extra checks), and installs their plugins.
+func loadConfig(ctx context.Context, c *cli.Command, names []string, check func(cliConfig *config.CliConfig, chainNames []string) error) (*config.CliConfig, []string, error) {
+	cliConfig, err := config.ParseCliConfig(c.String("config"))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	chainNames, err := selectChains(cliConfig, names)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := cliConfig.PortConflicts(chainNames); err != nil {
+		return nil, nil, err
+	}
+
+	if check != nil {
+		if err := check(cliConfig, chainNames); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := installAll(ctx, c, cliConfig, chainNames); err != nil {
+		return nil, nil, err
+	} else {
+		return cliConfig, chainNames, nil
+	}
+}
+
+// watchConfig reloads the config file whenever the process receives SIGHUP or, if
+// --watch is set, whenever the contents of the config file change. If the new config
+// is invalid, then the chains keep running with the current config.
+func watchConfig(ctx context.Context, c *cli.Command, f *fleet.Fleet, names []string, check func(cliConfig *config.CliConfig, chainNames []string) error) {
+	configPath := c.String("config")
+	logger := reloadLogger()
+
+	hangups := make(chan os.Signal, 1)
+	signal.Notify(hangups, syscall.SIGHUP)
+	defer signal.Stop(hangups)
+
+	var ticks <-chan time.Time
+	if c.Bool("watch") {
+		ticker := time.NewTicker(WATCH_INTERVAL)
+		defer ticker.Stop()
+		ticks = ticker.C
+	}
+
+	digest, _ := fileDigest(configPath)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangups:
+			logger.Printf("Received SIGHUP - reloading %s", configPath)
+			if next, err := fileDigest(configPath); err == nil {
+				digest = next
+			}
+		case <-ticks:
+			// NOTE: errors (e.g. while an editor replaces the file) are treated as "no
+			// change" so that the file is read again on the next tick
+			next, err := fileDigest(configPath)
+			if err != nil || next == digest {
+				continue
+			} else {
+				digest = next
+				logger.Printf("Detected a change to %s - reloading", configPath)
+			}
+		}
+
+		cliConfig, chainNames, err := loadConfig(ctx, c, names, check)
+		if err != nil {
+			logger.Printf("Failed to reload the config - keeping the current config: %v", err)
+		} else {
+			f.Apply(ctx, cliConfig, chainNames)
+		}
+	}
+}
+
+// reloadLogger returns the logger that config reloads and the resulting changes to
+// the running chains are reported with
+func reloadLogger() *log.Logger {
+	return log.New(core.NewRedactWriter(os.Stderr, config.Redact), "[config] ", log.LstdFlags)
+}
+
+func fileDigest(filePath string) ([sha256.Size]byte, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return [sha256.Size]byte{}, err
+	} else {
+		return sha256.Sum256(data), nil
+	}
+}
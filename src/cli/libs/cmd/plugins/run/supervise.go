@@ -0,0 +1,100 @@
+package run
+
+import (
+	"context"
+	"os"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/urfave/cli/v3"
+)
+
+var supervisorFlags = []cli.Flag{
+	&cli.StringFlag{Name: "restart", Usage: "The restart policy (always, on-failure, never)", Sources: cli.EnvVars("RESTART_POLICY"), Required: false},
+	&cli.IntFlag{Name: "max-restarts", Usage: "The maximum number of restarts within the restart window (negative for unlimited)", Sources: cli.EnvVars("MAX_RESTARTS"), Required: false},
+	&cli.DurationFlag{Name: "restart-window", Usage: "The time window in which restarts are counted", Sources: cli.EnvVars("RESTART_WINDOW"), Required: false},
+	&cli.DurationFlag{Name: "backoff", Usage: "The initial delay between restarts (doubles after every restart)", Sources: cli.EnvVars("RESTART_BACKOFF"), Required: false},
+	&cli.DurationFlag{Name: "max-backoff", Usage: "The maximum delay between restarts", Sources: cli.EnvVars("RESTART_MAX_BACKOFF"), Required: false},
+	&cli.DurationFlag{Name: "kill-timeout", Usage: "How long to wait for the plugin to exit after SIGTERM before killing it", Sources: cli.EnvVars("KILL_TIMEOUT"), Required: false},
+}
+
+var installFlags = []cli.Flag{
+	&cli.StringFlag{Name: "public-key", Usage: "A minisign public key (or the path to one) used to verify release signatures", Sources: cli.EnvVars("PLUGIN_PUBLIC_KEY"), Required: false},
+	&cli.BoolFlag{Name: "allow-unverified", Usage: "Allow installing plugins from releases that do not have a checksums file (e.g. older releases)", Sources: cli.EnvVars("PLUGIN_ALLOW_UNVERIFIED"), Required: false},
+}
+
+// applySupervisorFlags overrides the restart settings of the chain config with any
+// supervisor flags that were explicitly set on the command line.
+func applySupervisorFlags(c *cli.Command, conf *config.ChainConfig) {
+	// NOTE: the restart config can be shared with other chains and with the config that
+	// a chain is running with, so the flags are applied to a copy
+	restart := config.RestartConfig{}
+	if conf.Restart != nil {
+		restart = *conf.Restart
+	}
+	conf.Restart = &restart
+
+	if c.IsSet("restart") {
+		conf.Restart.Policy = c.String("restart")
+	}
+	if c.IsSet("max-restarts") {
+		conf.Restart.MaxRestarts = c.Int("max-restarts")
+	}
+	if c.IsSet("restart-window") {
+		conf.Restart.Window = config.Duration(c.Duration("restart-window"))
+	}
+	if c.IsSet("backoff") {
+		conf.Restart.Backoff = config.Duration(c.Duration("backoff"))
+	}
+	if c.IsSet("max-backoff") {
+		conf.Restart.MaxBackoff = config.Duration(c.Duration("max-backoff"))
+	}
+	if c.IsSet("kill-timeout") {
+		conf.Restart.KillTimeout = config.Duration(c.Duration("kill-timeout"))
+	}
+}
+
+// prepare validates the chain config, downloads and installs the chain's plugin
+// unless it is already installed, and then validates the chain config against the
+// plugin's manifest. If the config pins a version, then that exact version must be
+// installed.
+func prepare(ctx context.Context, c *cli.Command, registries []*registry.Entry, conf *config.ChainConfig) error {
+	if err := conf.Validate(); err != nil {
+		return err
+	}
+
+	ref, err := plgn.RefFromConfig(conf.Plugin)
+	if err != nil {
+		return err
+	}
+
+	isInstalled, err := plgn.Store.IsInstalled(ref)
+	if err != nil {
+		return err
+	}
+
+	if !isInstalled {
+		opts, err := plgn.NewDownloadOptions(c.String("public-key"), registries)
+		if err != nil {
+			return err
+		}
+		opts.AllowUnverified = c.Bool("allow-unverified")
+
+		ref = ref.WithDefaultVersion()
+		pluginPaths, source, err := plgn.Cache.Download(ctx, ref, opts)
+		if err != nil {
+			return err
+		}
+
+		if err := plgn.Store.Install(ctx, ref.Version, source, pluginPaths); err != nil {
+			return err
+		}
+	}
+
+	return plgn.Store.Validate(conf)
+}
+
+func defaultOutput() plgn.ProcessOptions {
+	return plgn.ProcessOptions{Stdout: os.Stdout, Stderr: os.Stderr}
+}
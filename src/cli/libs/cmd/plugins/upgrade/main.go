@@ -0,0 +1,130 @@
+package upgrade
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/urfave/cli/v3"
+)
+
+const (
+	STATUS_UP_TO_DATE = "up-to-date"
+	STATUS_AVAILABLE  = "available"
+	STATUS_INSTALLED  = "installed"
+)
+
+// UpgradeResult reports the newest version of a plugin that the registries offer and
+// whether it was installed
+type UpgradeResult struct {
+	ID       string `json:"id"`
+	Current  string `json:"current"`
+	Latest   string `json:"latest"`
+	Registry string `json:"registry"`
+	Status   string `json:"status"`
+}
+
+var Commands = &cli.Command{
+	Name:  "upgrade",
+	Usage: "Shows the available updates of the installed plugins and optionally installs them",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to upgrade (defaults to all plugins)", Required: false},
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file whose registries are searched for updates", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
+		&cli.BoolFlag{Name: "apply", Usage: "If specified, install the updates next to the versions that are currently installed", Required: false, Value: false},
+		&cli.StringFlag{Name: "public-key", Usage: "A minisign public key (or the path to one) used to verify release signatures", Sources: cli.EnvVars("PLUGIN_PUBLIC_KEY"), Required: false},
+		&cli.BoolFlag{Name: "allow-unverified", Usage: "Allow installing plugins from releases that do not have a checksums file (e.g. older releases)", Sources: cli.EnvVars("PLUGIN_ALLOW_UNVERIFIED"), Required: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		registries, err := registry.FromConfigFile(c.String("config"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		opts, err := plgn.NewDownloadOptions(c.String("public-key"), registries)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+		opts.AllowUnverified = c.Bool("allow-unverified")
+
+		pluginIDs, err := installedIDs(c.StringSlice("plugin-id"))
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		results := make([]*UpgradeResult, len(pluginIDs))
+		for i, pluginID := range pluginIDs {
+			versions, err := plgn.Store.Versions(pluginID)
+			if err != nil {
+				return core.ErrExit(err)
+			}
+			if len(versions) == 0 {
+				return core.ErrExit(fmt.Errorf("plugin '%s' is not installed", pluginID))
+			}
+
+			current := versions[len(versions)-1]
+			latest, err := plgn.Latest(ctx, pluginID, registries)
+			if err != nil {
+				return core.ErrExit(err)
+			}
+
+			results[i] = &UpgradeResult{ID: pluginID, Current: current, Latest: latest.Version, Registry: latest.Registry}
+			if plgn.CompareVersions(latest.Version, current) <= 0 {
+				results[i].Status = STATUS_UP_TO_DATE
+				continue
+			}
+
+			if c.Bool("apply") {
+				pluginPaths, source, err := plgn.Cache.Download(ctx, latest.Ref, opts)
+				if err != nil {
+					return core.ErrExit(err)
+				}
+
+				if err := plgn.Store.Install(ctx, latest.Version, source, pluginPaths); err != nil {
+					return core.ErrExit(err)
+				}
+
+				results[i].Status = STATUS_INSTALLED
+			} else {
+				results[i].Status = STATUS_AVAILABLE
+			}
+		}
+
+		if err := core.PrintResult(c, results); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// installedIDs returns the given plugin IDs or, if none were given, the IDs of all
+// installed plugins. Versions are not allowed since the newest version is upgraded.
+func installedIDs(pluginIDs []string) ([]string, error) {
+	if len(pluginIDs) != 0 {
+		for _, pluginID := range pluginIDs {
+			if ref, err := plgn.ParseRef(pluginID); err != nil {
+				return nil, err
+			} else if ref.Version != "" {
+				return nil, fmt.Errorf("plugin '%s' must not include a version", pluginID)
+			}
+		}
+		return pluginIDs, nil
+	}
+
+	refs, err := plgn.Store.Refs()
+	if err != nil {
+		return nil, err
+	}
+
+	ids := []string{}
+	for _, ref := range refs {
+		if !slices.Contains(ids, ref.ID) {
+			ids = append(ids, ref.ID)
+		}
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,105 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"github.com/urfave/cli/v3"
+)
+
+// VerifyResult reports whether a plugin still matches the checksum that was recorded
+// when it was installed and, unless verification is offline, the release that it was
+// installed from
+type VerifyResult struct {
+	ID       string `json:"id"`
+	Verified bool   `json:"verified"`
+	Release  bool   `json:"release"`
+	Registry string `json:"registry,omitempty"`
+	Signed   bool   `json:"signed"`
+	Error    string `json:"error,omitempty"`
+}
+
+var Commands = &cli.Command{
+	Name:  "verify",
+	Usage: "Re-checks the installed plugin binaries against the signed checksums of their releases and the checksums recorded at install time",
+	Flags: []cli.Flag{
+		&cli.StringSliceFlag{Name: "plugin-id", Usage: "The ID of the plugin to verify, optionally with a version (defaults to all plugins)", Required: false},
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file whose registries the plugins were installed from", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
+		&cli.StringFlag{Name: "public-key", Usage: "A minisign public key (or the path to one) used to verify release signatures", Sources: cli.EnvVars("PLUGIN_PUBLIC_KEY"), Required: false},
+		&cli.BoolFlag{Name: "offline", Usage: "If specified, only check the binaries against the checksums recorded at install time", Required: false, Value: false},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		var opts *plgn.DownloadOptions
+		if !c.Bool("offline") {
+			registries, err := registry.FromConfigFile(c.String("config"))
+			if err != nil {
+				return core.ErrExit(err)
+			}
+
+			downloadOpts, err := plgn.NewDownloadOptions(c.String("public-key"), registries)
+			if err != nil {
+				return core.ErrExit(err)
+			} else {
+				opts = &downloadOpts
+			}
+		}
+
+		pluginIDs := c.StringSlice("plugin-id")
+		if len(pluginIDs) == 0 {
+			if ids, err := plgn.IDs(); err != nil {
+				return core.ErrExit(err)
+			} else {
+				pluginIDs = ids
+			}
+		}
+
+		failures := 0
+		results := make([]*VerifyResult, len(pluginIDs))
+		for i, pluginID := range pluginIDs {
+			results[i] = verify(ctx, pluginID, opts)
+			if !results[i].Verified {
+				failures += 1
+			}
+		}
+
+		if err := core.PrintResult(c, results); err != nil {
+			return core.ErrExit(err)
+		}
+
+		if failures != 0 {
+			return core.ErrExitWithCode(fmt.Errorf("%d plugin(s) failed verification", failures), core.CODE_INTEGRITY, core.EXIT_INTEGRITY)
+		} else {
+			return nil
+		}
+	},
+}
+
+// verify checks a plugin against its release, or only against the checksum that was
+// recorded at install time if the options are nil. Plugins that were not installed
+// from a registry (e.g. from a file) have no release, so they are verified offline.
+func verify(ctx context.Context, pluginID string, opts *plgn.DownloadOptions) *VerifyResult {
+	ref, err := plgn.ParseRef(pluginID)
+	if err != nil {
+		return &VerifyResult{ID: pluginID, Error: err.Error()}
+	}
+
+	if opts != nil {
+		verification, err := plgn.Cache.VerifyRelease(ctx, &plgn.Store, ref, *opts)
+		if err == nil {
+			return &VerifyResult{ID: pluginID, Verified: true, Release: true, Registry: verification.Registry, Signed: verification.Signed}
+		}
+		if !errors.Is(err, &plgn.NotFromRegistryError{}) {
+			return &VerifyResult{ID: pluginID, Error: err.Error()}
+		}
+	}
+
+	if err := plgn.Store.Verify(ref); err != nil {
+		return &VerifyResult{ID: pluginID, Error: err.Error()}
+	} else {
+		return &VerifyResult{ID: pluginID, Verified: true}
+	}
+}
@@ -0,0 +1,136 @@
+package tail
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/tail"
+	"github.com/urfave/cli/v3"
+)
+
+// Line is a single line of JSON output
+type Line struct {
+	Chain  string    `json:"chain,omitempty"`
+	Cursor string    `json:"cursor"`
+	Time   time.Time `json:"time"`
+}
+
+var Commands = &cli.Command{
+	Name:  "tail",
+	Usage: "Streams cursors from a running plugin (or gateway) to the terminal",
+	Description: "The plugin's address is either given with --address or resolved from the server block of a chain in " +
+		"a config file. The chain name is also sent with each call so that a gateway can route it. If the stream fails " +
+		"(e.g. while the plugin restarts), then the command reconnects and resumes right after the last cursor.",
+	Flags: []cli.Flag{
+		&cli.StringFlag{Name: "address", Usage: "The address of the plugin or gateway (e.g. localhost:3000)", Aliases: []string{"a"}, Sources: cli.EnvVars("TAIL_ADDRESS"), Required: false},
+		&cli.StringFlag{Name: "config", Usage: "The path to the CLI config file that the plugin's address is resolved from", Aliases: []string{"c"}, Sources: cli.EnvVars("CONFIG"), Required: false},
+		&cli.StringFlag{Name: "name", Usage: "The name of the chain", Aliases: []string{"n"}, Sources: cli.EnvVars("CHAIN"), Required: false},
+		&cli.StringFlag{Name: "start", Usage: "The first cursor to stream (defaults to the latest cursor)", Required: false},
+		&cli.StringFlag{Name: "end", Usage: "The last cursor to stream (defaults to streaming forever)", Required: false},
+		&cli.BoolFlag{Name: "no-reconnect", Usage: "If specified, exit as soon as the stream fails instead of reconnecting", Required: false, Value: false},
+		&cli.DurationFlag{Name: "max-backoff", Usage: "The maximum delay between reconnection attempts", Required: false, Value: tail.DEFAULT_MAX_BACKOFF},
+	},
+	Action: func(ctx context.Context, c *cli.Command) error {
+		// NOTE: cursors are printed as plain text unless a format is requested, and only
+		// formats that can be streamed line by line are supported
+		format := core.OUTPUT_TEXT
+		if c.Root().IsSet(core.OUTPUT_FLAG) {
+			format = core.OutputFormat(c)
+		}
+		if format != core.OUTPUT_TEXT && format != core.OUTPUT_JSON {
+			return core.ErrExitWithCode(fmt.Errorf("tail does not support the '%s' output format - must be one of: [ %s, %s ]", format, core.OUTPUT_TEXT, core.OUTPUT_JSON), core.CODE_USAGE, core.EXIT_USAGE)
+		}
+
+		address, err := resolveAddress(c)
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		start, err := parseCursor(c, "start")
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		end, err := parseCursor(c, "end")
+		if err != nil {
+			return core.ErrExit(err)
+		}
+
+		opts := tail.Options{
+			Address:    address,
+			Chain:      c.String("name"),
+			Start:      start,
+			End:        end,
+			Reconnect:  !c.Bool("no-reconnect"),
+			Backoff:    tail.DEFAULT_BACKOFF,
+			MaxBackoff: c.Duration("max-backoff"),
+			Logger:     log.New(os.Stderr, "[tail] ", log.LstdFlags),
+		}
+
+		encoder := json.NewEncoder(c.Root().Writer)
+		if err := tail.Tail(ctx, opts, func(cursor *big.Int) error {
+			if format == core.OUTPUT_JSON {
+				return encoder.Encode(&Line{Chain: opts.Chain, Cursor: cursor.String(), Time: time.Now().UTC()})
+			} else {
+				_, err := fmt.Fprintln(c.Root().Writer, cursor.String())
+				return err
+			}
+		}); err != nil {
+			return core.ErrExit(err)
+		} else {
+			return nil
+		}
+	},
+}
+
+// resolveAddress returns the --address flag or the address of the chain's server in
+// the config file.
+func resolveAddress(c *cli.Command) (string, error) {
+	if address := c.String("address"); address != "" {
+		return address, nil
+	}
+
+	if c.String("config") == "" || c.String("name") == "" {
+		return "", errors.New("either --address or both --config and --name must be specified")
+	}
+
+	cliConfig, err := config.ParseCliConfig(c.String("config"))
+	if err != nil {
+		return "", err
+	}
+
+	chainConfig, err := cliConfig.Chain(c.String("name"))
+	if err != nil {
+		return "", err
+	}
+
+	// NOTE: a port of zero means that the plugin picks a random port, which can only
+	// be found in the plugin's logs
+	if chainConfig.Server == nil || chainConfig.Server.Port == 0 {
+		return "", fmt.Errorf("chain '%s' listens on a random port - use --address instead", c.String("name"))
+	} else {
+		return chainConfig.Server.DialUrl(), nil
+	}
+}
+
+func parseCursor(c *cli.Command, flag string) (*big.Int, error) {
+	value := c.String(flag)
+	if value == "" {
+		return nil, nil
+	}
+
+	cursor, ok := new(big.Int).SetString(value, 10)
+	if !ok || cursor.Sign() < 0 {
+		return nil, fmt.Errorf("invalid --%s cursor '%s' - must be a non-negative integer", flag, value)
+	} else {
+		return cursor, nil
+	}
+}
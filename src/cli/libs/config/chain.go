@@ -2,8 +2,15 @@ package config
 
 type (
 	ChainConfig struct {
-		Server *ServerConfig    `json:"server"`
-		Conn   *ConnectionConfg `json:"conn"`
-		Plugin *PluginConfig    `json:"plugin"`
+		// Extends names the profile that this chain (or profile) inherits its settings
+		// from. It is always empty once the config has been resolved.
+		Extends string `json:"extends,omitempty"`
+
+		Server    *ServerConfig    `json:"server,omitempty"`
+		Conn      *ConnectionConfg `json:"conn,omitempty"`
+		Plugin    *PluginConfig    `json:"plugin,omitempty"`
+		Parachain *ParachainConfig `json:"parachain,omitempty"`
+		Restart   *RestartConfig   `json:"restart,omitempty"`
+		Finality  string           `json:"finality,omitempty"`
 	}
 )
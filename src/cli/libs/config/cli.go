@@ -1,8 +1,27 @@
 package config
 
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
 type (
 	CliConfig struct {
-		Chains map[string]ChainConfig `json:"chains"`
+		// Schema optionally points editors to the JSON Schema of the config file
+		Schema string `json:"$schema,omitempty"`
+
+		// Defaults apply to every chain, and Profiles are named sets of settings that
+		// chains can extend. Chain-level fields override profile fields, which in turn
+		// override the defaults.
+		Defaults *ChainConfig           `json:"defaults,omitempty"`
+		Profiles map[string]ChainConfig `json:"profiles,omitempty"`
+
+		// Chains holds the resolved chain configs (i.e. with the defaults and profiles
+		// applied), while declared holds them as they were written
+		Chains     map[string]ChainConfig `json:"chains"`
+		Registries []RegistryConfig       `json:"registries,omitempty"`
+		declared   map[string]ChainConfig
 	}
 )
 
@@ -11,5 +30,62 @@ func (c *CliConfig) ChainNames() []string {
 	for k := range c.Chains {
 		chains = append(chains, k)
 	}
+	slices.Sort(chains)
 	return chains
 }
+
+func (c *CliConfig) Chain(chainName string) (*ChainConfig, error) {
+	chainConfig, exists := c.Chains[chainName]
+	if !exists {
+		return nil, fmt.Errorf(
+			"chain with name '%s' does not exist in config - must be one of: [ %s ]",
+			chainName,
+			strings.Join(c.ChainNames(), ", "),
+		)
+	} else {
+		return &chainConfig, nil
+	}
+}
+
+// PortConflicts returns an error describing every pair of chains (among the given
+// chain names) whose servers would try to listen on the same address.
+func (c *CliConfig) PortConflicts(chainNames []string) error {
+	conflicts := []string{}
+	for i, a := range chainNames {
+		for _, b := range chainNames[i+1:] {
+			serverA := c.Chains[a].Server
+			serverB := c.Chains[b].Server
+			if serverA == nil || serverB == nil {
+				continue
+			}
+			if serverA.Overlaps(serverB) {
+				conflicts = append(conflicts, fmt.Sprintf("'%s' (%s) and '%s' (%s)", a, serverA.Url(), b, serverB.Url()))
+			}
+		}
+	}
+
+	if len(conflicts) == 0 {
+		return nil
+	} else {
+		return fmt.Errorf("the following chains have conflicting server addresses: [ %s ]", strings.Join(conflicts, ", "))
+	}
+}
+
+// Declared returns the config as it was written (i.e. with the chains as they were
+// declared rather than resolved).
+func (c *CliConfig) Declared() *CliConfig {
+	declared := *c
+	if c.declared != nil {
+		declared.Chains = c.declared
+	}
+	return &declared
+}
+
+// Resolved returns the config with the defaults and profiles applied to the chains
+// and then removed.
+func (c *CliConfig) Resolved() *CliConfig {
+	resolved := *c
+	resolved.Defaults = nil
+	resolved.Profiles = nil
+	return &resolved
+}
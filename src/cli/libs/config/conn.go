@@ -2,7 +2,8 @@ package config
 
 type (
 	ConnectionConfg struct {
-		Wss string `json:"wss"`
-		Rpc string `json:"rpc"`
+		Wss    string        `json:"wss,omitempty" secret:"true"`
+		Rpc    string        `json:"rpc,omitempty" secret:"true"`
+		Sporks []SporkConfig `json:"sporks,omitempty"`
 	}
 )
@@ -0,0 +1,228 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// AddChains adds chains to an encoded config file and returns the encoded result. An
+// empty file is treated as a config without chains. The file is not interpolated, so
+// environment variables and file references in the existing config are kept as they
+// are. Adding a chain whose name is already taken is an error.
+//
+// NOTE: YAML files are edited in place, so their comments and key order are kept.
+// JSON files (which cannot have comments) are encoded again with sorted keys. TOML
+// files would lose their comments and layout when they are encoded again, so they are
+// only rewritten if force is true.
+func AddChains(data []byte, format string, chains map[string]ChainConfig, force bool) ([]byte, error) {
+	if len(bytes.TrimSpace(data)) != 0 {
+		switch format {
+		case FORMAT_YAML:
+			return addYamlChains(data, chains)
+		case FORMAT_TOML:
+			if !force {
+				return nil, fmt.Errorf("adding chains to an existing %s config would drop its comments and reorder its keys - use --force to rewrite it anyway", strings.ToUpper(format))
+			}
+		}
+	}
+
+	root := map[string]any{}
+	if len(bytes.TrimSpace(data)) != 0 {
+		raw, err := decodeRaw(data, format)
+		if err != nil {
+			return nil, err
+		}
+
+		obj, ok := raw.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("the config must be an object")
+		} else {
+			root = obj
+		}
+	}
+
+	existing := map[string]any{}
+	if value, exists := root["chains"]; exists && value != nil {
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("%s must be an object", JoinPath(ROOT_PATH, "chains"))
+		} else {
+			existing = obj
+		}
+	}
+
+	for _, chainName := range slices.Sorted(maps.Keys(chains)) {
+		if _, exists := existing[chainName]; exists {
+			return nil, fmt.Errorf("chain '%s' already exists in the config", chainName)
+		}
+
+		value, err := encodeChain(chains[chainName])
+		if err != nil {
+			return nil, err
+		} else {
+			existing[chainName] = value
+		}
+	}
+	root["chains"] = existing
+
+	return encodeRaw(root, format)
+}
+
+// addYamlChains adds chains to the node tree of a YAML file, which keeps everything
+// that a decoded value loses (i.e. comments, key order, quoting styles and anchors)
+func addYamlChains(data []byte, chains map[string]ChainConfig) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("invalid YAML: %w", err)
+	}
+
+	// NOTE: the decoder drops the comments of a file that has no content, so the new
+	// chains are appended to the file as it is
+	var prefix []byte
+	if len(doc.Content) == 0 {
+		prefix = append(slices.Clone(bytes.TrimRight(data, "\n")), '\n')
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("the config must be an object")
+	}
+
+	var existing *yaml.Node
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value == "chains" {
+			existing = root.Content[i+1]
+		}
+	}
+	if existing == nil {
+		existing = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+		root.Content = append(root.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: "chains"}, existing)
+	}
+	if existing.Kind == yaml.ScalarNode && existing.Tag == "!!null" {
+		*existing = yaml.Node{Kind: yaml.MappingNode, Tag: "!!map", LineComment: existing.LineComment}
+	}
+	if existing.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s must be an object", JoinPath(ROOT_PATH, "chains"))
+	}
+
+	// NOTE: an empty flow mapping (i.e. 'chains: {}') is written as a block instead
+	if len(existing.Content) == 0 {
+		existing.Style = 0
+	}
+
+	for _, chainName := range slices.Sorted(maps.Keys(chains)) {
+		for i := 0; i+1 < len(existing.Content); i += 2 {
+			if existing.Content[i].Value == chainName {
+				return nil, fmt.Errorf("chain '%s' already exists in the config", chainName)
+			}
+		}
+
+		value, err := encodeChain(chains[chainName])
+		if err != nil {
+			return nil, err
+		}
+
+		node := new(yaml.Node)
+		if err := node.Encode(plainNumbers(value)); err != nil {
+			return nil, err
+		} else {
+			existing.Content = append(existing.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: chainName}, node)
+		}
+	}
+
+	buf := bytes.NewBuffer(prefix)
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	} else {
+		return buf.Bytes(), nil
+	}
+}
+
+// encodeChain encodes a chain as JSON and decodes it again so that it consists of the
+// same generic values as a decoded config file
+func encodeChain(chainConfig ChainConfig) (any, error) {
+	encoded, err := json.Marshal(chainConfig)
+	if err != nil {
+		return nil, err
+	} else {
+		return decodeRaw(encoded, FORMAT_JSON)
+	}
+}
+
+// encodeRaw is the inverse of decodeRaw
+func encodeRaw(raw any, format string) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	switch format {
+	case FORMAT_JSON:
+		enc := json.NewEncoder(buf)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(raw); err != nil {
+			return nil, err
+		}
+	case FORMAT_YAML:
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(plainNumbers(raw)); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+	case FORMAT_TOML:
+		enc := toml.NewEncoder(buf)
+		enc.Indent = ""
+		if err := enc.Encode(plainNumbers(raw)); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid config format '%s' - must be one of: [ %s, %s, %s ]",
+			format,
+			FORMAT_JSON,
+			FORMAT_YAML,
+			FORMAT_TOML,
+		)
+	}
+	return buf.Bytes(), nil
+}
+
+// plainNumbers converts the JSON numbers of a decoded value into integers or floats,
+// which are the only numbers that the YAML and TOML encoders understand
+func plainNumbers(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = plainNumbers(elem)
+		}
+		return v
+	case []any:
+		for i, elem := range v {
+			v[i] = plainNumbers(elem)
+		}
+		return v
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+		return v.String()
+	default:
+		return v
+	}
+}
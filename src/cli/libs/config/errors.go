@@ -0,0 +1,95 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ValidationError is a problem with a single value of a config file. The path is a
+// JSON path to the value (e.g. $.chains.solana.server.port).
+type ValidationError struct {
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, e.Message)
+}
+
+func (e *ValidationError) Is(target error) bool {
+	_, ok := target.(*ValidationError)
+	return ok
+}
+
+// ValidationErrors holds every problem that was found in a config file.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	lines := make([]string, len(e))
+	for i, err := range e {
+		lines[i] = "  " + err.Error()
+	}
+	return fmt.Sprintf("found %d problem(s) in config:\n%s", len(e), strings.Join(lines, "\n"))
+}
+
+func (e ValidationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+func (e ValidationErrors) Is(target error) bool {
+	_, ok := target.(ValidationErrors)
+	return ok
+}
+
+type ProfileNotFoundError struct {
+	Name    string
+	Choices []string
+}
+
+func (e *ProfileNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"profile '%s' does not exist - must be one of: [ %s ]",
+		e.Name,
+		strings.Join(e.Choices, ", "),
+	)
+}
+
+func (e *ProfileNotFoundError) Is(target error) bool {
+	_, ok := target.(*ProfileNotFoundError)
+	return ok
+}
+
+type ProfileCycleError struct {
+	Profiles []string
+}
+
+func (e *ProfileCycleError) Error() string {
+	return fmt.Sprintf("profiles extend each other in a cycle: %s", strings.Join(e.Profiles, " -> "))
+}
+
+func (e *ProfileCycleError) Is(target error) bool {
+	_, ok := target.(*ProfileCycleError)
+	return ok
+}
+
+type TemplateNotFoundError struct {
+	Name    string
+	Choices []string
+}
+
+func (e *TemplateNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"template '%s' does not exist - must be one of: [ %s ]",
+		e.Name,
+		strings.Join(e.Choices, ", "),
+	)
+}
+
+func (e *TemplateNotFoundError) Is(target error) bool {
+	_, ok := target.(*TemplateNotFoundError)
+	return ok
+}
@@ -0,0 +1,112 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	FORMAT_JSON = "json"
+	FORMAT_YAML = "yaml"
+	FORMAT_TOML = "toml"
+)
+
+// FormatFromPath returns the format of a config file based on its extension. Files
+// with an unknown extension are assumed to be JSON.
+func FormatFromPath(filePath string) string {
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".yaml", ".yml":
+		return FORMAT_YAML
+	case ".toml":
+		return FORMAT_TOML
+	default:
+		return FORMAT_JSON
+	}
+}
+
+// decodeRaw decodes a config file into generic values (i.e. maps, slices, strings,
+// numbers and booleans) so that every format can be checked and interpolated in the
+// same way before it is decoded into a CliConfig
+func decodeRaw(data []byte, format string) (any, error) {
+	var raw any
+	switch format {
+	case FORMAT_JSON:
+		// NOTE: numbers are kept as strings so that large integers do not lose
+		// precision when the config is encoded again
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		if err := dec.Decode(&raw); err != nil {
+			return nil, syntaxError(data, err)
+		}
+		if dec.More() {
+			return nil, fmt.Errorf("invalid JSON: unexpected data after the top-level value")
+		}
+		return raw, nil
+	case FORMAT_YAML:
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("invalid YAML: %w", err)
+		} else {
+			return normalize(raw), nil
+		}
+	case FORMAT_TOML:
+		var table map[string]any
+		if _, err := toml.Decode(string(data), &table); err != nil {
+			return nil, fmt.Errorf("invalid TOML: %w", err)
+		} else {
+			return table, nil
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid config format '%s' - must be one of: [ %s, %s, %s ]",
+			format,
+			FORMAT_JSON,
+			FORMAT_YAML,
+			FORMAT_TOML,
+		)
+	}
+}
+
+// normalize converts YAML mappings with non-string keys (e.g. a chain named 1) into
+// maps with string keys, which is what the JSON encoder expects
+func normalize(value any) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, elem := range v {
+			v[key] = normalize(elem)
+		}
+		return v
+	case map[any]any:
+		obj := make(map[string]any, len(v))
+		for key, elem := range v {
+			obj[fmt.Sprint(key)] = normalize(elem)
+		}
+		return obj
+	case []any:
+		for i, elem := range v {
+			v[i] = normalize(elem)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// syntaxError adds the line and column of a JSON syntax error to its message
+func syntaxError(data []byte, err error) error {
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		return err
+	}
+
+	prefix := data[:min(int(syntaxErr.Offset), len(data))]
+	line := bytes.Count(prefix, []byte("\n")) + 1
+	column := len(prefix) - bytes.LastIndexByte(prefix, '\n') - 1
+	return fmt.Errorf("invalid JSON at line %d, column %d: %w", line, column, err)
+}
@@ -0,0 +1,185 @@
+package config
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const (
+	// FILE_REFERENCE_PREFIX marks a string value that is replaced by the contents of a
+	// file (e.g. file:/run/secrets/rpc-url). Relative paths are resolved against the
+	// directory of the config file.
+	FILE_REFERENCE_PREFIX = "file:"
+)
+
+var (
+	envNameRegex    = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+)
+
+// interpolator replaces environment variable references (i.e. ${NAME} or
+// ${NAME:-default}) and file references in the string values of a decoded config
+type interpolator struct {
+	v        *validator
+	lookup   func(string) (string, bool)
+	readFile func(string) ([]byte, error)
+}
+
+// ResolveFile returns the path of a file reference. Relative paths are resolved
+// against the directory of the config file.
+func ResolveFile(dir string, filePath string) string {
+	if filepath.IsAbs(filePath) {
+		return filePath
+	} else {
+		return filepath.Join(dir, filePath)
+	}
+}
+
+// walk interpolates a decoded value alongside the Go type that it will be decoded
+// into. The type is needed to convert interpolated numbers (e.g. a port) and to find
+// the fields that can hold secrets.
+func (in *interpolator) walk(path string, value any, typ reflect.Type, secret bool) any {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	if s, ok := value.(string); ok {
+		return in.leaf(path, s, typ, secret)
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		if obj, ok := value.(map[string]any); ok {
+			for i := range typ.NumField() {
+				field := typ.Field(i)
+				name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+				if elem, ok := obj[name]; ok && field.IsExported() {
+					obj[name] = in.walk(JoinPath(path, name), elem, field.Type, field.Tag.Get(SECRET_TAG) == "true")
+				}
+			}
+		}
+	case reflect.Map:
+		if obj, ok := value.(map[string]any); ok {
+			for key, elem := range obj {
+				obj[key] = in.walk(JoinPath(path, key), elem, typ.Elem(), secret)
+			}
+		}
+	case reflect.Slice:
+		if arr, ok := value.([]any); ok {
+			for i, elem := range arr {
+				arr[i] = in.walk(IndexPath(path, i), elem, typ.Elem(), secret)
+			}
+		}
+	}
+
+	return value
+}
+
+func (in *interpolator) leaf(path string, s string, typ reflect.Type, secret bool) any {
+	// NOTE: types with a custom JSON encoding (e.g. durations) are encoded as strings
+	isString := typ.Kind() == reflect.String || reflect.PointerTo(typ).Implements(unmarshalerType)
+	if !isString {
+		return in.scalar(path, s, typ)
+	}
+
+	expanded := in.expand(path, s, secret)
+	filePath, isFile := strings.CutPrefix(expanded, FILE_REFERENCE_PREFIX)
+	if !isFile {
+		return expanded
+	}
+
+	data, err := in.readFile(filePath)
+	if err != nil {
+		in.v.fail(path, "failed to read secret file: %v", err)
+		return ""
+	}
+
+	// NOTE: the contents of a file are always treated as a secret
+	contents := strings.TrimSpace(string(data))
+	registerSecret(contents, expanded)
+	return contents
+}
+
+// scalar converts an interpolated string into the number or boolean that the field
+// expects (e.g. port: ${PORT}). Strings without references are left as they are so
+// that the decoder reports them as values of the wrong type.
+func (in *interpolator) scalar(path string, s string, typ reflect.Type) any {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	expanded := in.expand(path, s, false)
+	switch typ.Kind() {
+	case reflect.Bool:
+		if b, err := strconv.ParseBool(expanded); err == nil {
+			return b
+		} else {
+			in.v.fail(path, "'%s' must be a boolean but got '%s'", s, expanded)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(expanded, 64); err == nil {
+			return json.Number(expanded)
+		} else {
+			in.v.fail(path, "'%s' must be a number but got '%s'", s, expanded)
+		}
+	default:
+		return expanded
+	}
+
+	return nil
+}
+
+// expand replaces the environment variable references in a string. A literal '${'
+// can be written as '$${'.
+func (in *interpolator) expand(path string, s string, secret bool) string {
+	if !strings.Contains(s, "${") {
+		return s
+	}
+
+	var out strings.Builder
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "$${") {
+			out.WriteString("${")
+			i += 3
+			continue
+		}
+		if !strings.HasPrefix(s[i:], "${") {
+			out.WriteByte(s[i])
+			i += 1
+			continue
+		}
+
+		end := strings.IndexByte(s[i:], '}')
+		if end == -1 {
+			in.v.fail(path, "unterminated variable reference in '%s'", s)
+			return s
+		}
+
+		expr := s[i+2 : i+end]
+		i += end + 1
+
+		name, fallback, hasFallback := strings.Cut(expr, ":-")
+		if !envNameRegex.MatchString(name) {
+			in.v.fail(path, "invalid environment variable name '%s'", name)
+			continue
+		}
+
+		value, ok := in.lookup(name)
+		if (!ok || value == "") && hasFallback {
+			out.WriteString(fallback)
+		} else if !ok {
+			in.v.fail(path, "environment variable '%s' is not set", name)
+		} else {
+			if secret {
+				registerSecret(value, "${"+name+"}")
+			}
+			out.WriteString(value)
+		}
+	}
+
+	return out.String()
+}
@@ -0,0 +1,170 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const (
+	TEST_YAML_CONFIG = `
+chains:
+  eth:
+    plugin:
+      id: eth
+    server:
+      host: localhost
+      port: ${TEST_PORT}
+    conn:
+      wss: wss://eth.example.com/v2/${TEST_API_KEY}
+      rpc: file:rpc-url.txt
+    restart:
+      backoff: ${TEST_BACKOFF:-2s}
+`
+
+	TEST_TOML_CONFIG = `
+[chains.eth]
+plugin = { id = "eth" }
+server = { host = "localhost", port = "${TEST_PORT}" }
+conn = { wss = "wss://eth.example.com/v2/${TEST_API_KEY}", rpc = "file:rpc-url.txt" }
+restart = { backoff = "${TEST_BACKOFF:-2s}" }
+`
+
+	TEST_JSON_CONFIG = `{
+  "chains": {
+    "eth": {
+      "plugin": { "id": "eth" },
+      "server": { "host": "localhost", "port": "${TEST_PORT}" },
+      "conn": { "wss": "wss://eth.example.com/v2/${TEST_API_KEY}", "rpc": "file:rpc-url.txt" },
+      "restart": { "backoff": "${TEST_BACKOFF:-2s}" }
+    }
+  }
+}`
+)
+
+func TestFormats(t *testing.T) {
+	t.Setenv("TEST_PORT", "3000")
+	t.Setenv("TEST_API_KEY", "key-from-env")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rpc-url.txt"), []byte("https://eth.example.com/v2/key-from-file\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "config.json", data: TEST_JSON_CONFIG},
+		{name: "config.yaml", data: TEST_YAML_CONFIG},
+		{name: "config.toml", data: TEST_TOML_CONFIG},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			configPath := filepath.Join(dir, tc.name)
+			if err := os.WriteFile(configPath, []byte(tc.data), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			conf, err := ParseCliConfig(configPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			chain, err := conf.Chain("eth")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if chain.Server.Port != 3000 {
+				t.Errorf("expected port 3000 but got %d", chain.Server.Port)
+			}
+			if chain.Conn.Wss != "wss://eth.example.com/v2/key-from-env" {
+				t.Errorf("unexpected wss: %s", chain.Conn.Wss)
+			}
+			if chain.Conn.Rpc != "https://eth.example.com/v2/key-from-file" {
+				t.Errorf("unexpected rpc: %s", chain.Conn.Rpc)
+			}
+			if chain.Restart.Backoff.Duration().String() != "2s" {
+				t.Errorf("expected the default backoff but got %s", chain.Restart.Backoff.Duration())
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	// NOTE: secrets are registered globally, so the secrets of the other tests (which
+	// may contain the secrets of this test) are cleared first
+	secrets.mutex.Lock()
+	secrets.values = map[string]string{}
+	secrets.mutex.Unlock()
+
+	t.Setenv("TEST_PORT", "3000")
+	t.Setenv("TEST_API_KEY", "key-from-env")
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "rpc-url.txt"), []byte("https://eth.example.com/v2/key-from-file"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	conf, err := DecodeCliConfig(strings.NewReader(TEST_JSON_CONFIG), DecodeOptions{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chain, err := conf.Chain("eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	output := Redact("connecting to " + chain.Conn.Wss + " and " + chain.Conn.Rpc)
+	if output != "connecting to wss://eth.example.com/v2/${TEST_API_KEY} and file:rpc-url.txt" {
+		t.Fatalf("unexpected redacted output: %s", output)
+	}
+
+	// NOTE: values that are not interpolated into secret fields are left as they are
+	if output := Redact("listening on port 3000"); output != "listening on port 3000" {
+		t.Fatalf("unexpected redacted output: %s", output)
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	t.Setenv("TEST_PORT", "not-a-port")
+
+	data := `{
+  "chains": {
+    "eth": {
+      "plugin": { "id": "eth" },
+      "server": { "port": "${TEST_PORT}" },
+      "conn": { "wss": "${TEST_MISSING_VARIABLE}", "rpc": "file:missing.txt" },
+      "finality": "$${literal} ${1INVALID}"
+    }
+  }
+}`
+
+	_, err := DecodeCliConfig(strings.NewReader(data), DecodeOptions{Dir: t.TempDir()})
+
+	var problems ValidationErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("expected validation errors but got: %v", err)
+	}
+
+	// NOTE: a value that cannot be interpolated is left empty, which can cause further
+	// problems (e.g. a 'conn' block without endpoints)
+	expected := map[string]string{
+		"$.chains.eth.server.port": "must be a number but got 'not-a-port'",
+		"$.chains.eth.conn.wss":    "'TEST_MISSING_VARIABLE' is not set",
+		"$.chains.eth.conn.rpc":    "failed to read secret file",
+		"$.chains.eth.finality":    "invalid environment variable name '1INVALID'",
+	}
+	for path, message := range expected {
+		if !slices.ContainsFunc(problems, func(problem *ValidationError) bool {
+			return problem.Path == path && strings.Contains(problem.Message, message)
+		}) {
+			t.Errorf("expected a problem at %s containing %q but got: %v", path, message, problems)
+		}
+	}
+}
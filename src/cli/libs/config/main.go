@@ -1,41 +1,182 @@
 package config
 
 import (
+	"bytes"
 	"encoding/json"
-	"fmt"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"reflect"
 	"strings"
 )
 
-func ParseCliConfig(filePath string) (*CliConfig, error) {
-	var conf CliConfig
+type DecodeOptions struct {
+	// Format is one of FORMAT_JSON, FORMAT_YAML or FORMAT_TOML (defaults to JSON)
+	Format string
 
-	configFile, err := os.Open(filePath)
+	// Dir is the directory that relative file references are resolved against
+	// (defaults to the working directory)
+	Dir string
+
+	// LookupEnv returns the value of an environment variable (defaults to os.LookupEnv)
+	LookupEnv func(string) (string, bool)
+
+	// ReadFile returns the contents of a file reference, given its path as it is
+	// written in the config (defaults to reading the file relative to Dir)
+	ReadFile func(string) ([]byte, error)
+}
+
+func ParseCliConfig(filePath string) (*CliConfig, error) {
+	conf, problems, err := CheckCliConfigFile(filePath)
 	if err != nil {
 		return nil, err
 	}
 
-	if err := json.NewDecoder(configFile).Decode(&conf); err != nil {
-		return nil, err
+	if len(problems) != 0 {
+		return nil, problems
+	} else {
+		return conf, nil
 	}
-
-	return &conf, err
 }
 
 func ParseChainConfig(filePath string, chainName string) (*ChainConfig, error) {
 	cliConfig, err := ParseCliConfig(filePath)
 	if err != nil {
 		return nil, err
+	} else {
+		return cliConfig.Chain(chainName)
+	}
+}
+
+// CheckCliConfigFile is like CheckCliConfig, but reads the config from a file whose
+// format is determined by its extension.
+func CheckCliConfigFile(filePath string) (*CliConfig, ValidationErrors, error) {
+	configFile, err := os.Open(filePath)
+	if err != nil {
+		return nil, nil, err
+	} else {
+		defer configFile.Close()
 	}
 
-	chainConfig, exists := cliConfig.Chains[chainName]
-	if !exists {
-		return nil, fmt.Errorf(
-			"chain with name '%s' does not exist in config - must be one of: [ %s ]",
-			chainName,
-			strings.Join(cliConfig.ChainNames(), ", "),
-		)
+	return CheckCliConfig(configFile, DecodeOptions{
+		Format: FormatFromPath(filePath),
+		Dir:    filepath.Dir(filePath),
+	})
+}
+
+// DecodeCliConfig strictly decodes and validates a CLI config. Unknown fields, values
+// of the wrong type, and invalid values are all reported together as ValidationErrors.
+func DecodeCliConfig(r io.Reader, opts DecodeOptions) (*CliConfig, error) {
+	conf, problems, err := CheckCliConfig(r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(problems) != 0 {
+		return nil, problems
+	} else {
+		return conf, nil
+	}
+}
+
+// CheckCliConfig decodes a CLI config, interpolates environment variables and file
+// references, and returns the config along with every problem that was found in it.
+// The config is returned even if it has problems (so that callers can perform further
+// checks), unless it could not be decoded at all. Secrets are redacted from the
+// problems.
+func CheckCliConfig(r io.Reader, opts DecodeOptions) (*CliConfig, ValidationErrors, error) {
+	if opts.Format == "" {
+		opts.Format = FORMAT_JSON
+	}
+	if opts.LookupEnv == nil {
+		opts.LookupEnv = os.LookupEnv
+	}
+	if opts.ReadFile == nil {
+		opts.ReadFile = func(filePath string) ([]byte, error) {
+			return os.ReadFile(ResolveFile(opts.Dir, filePath))
+		}
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	return &chainConfig, nil
+	// NOTE: the config is first decoded without a schema so that every unknown field
+	// can be reported with its path (json.Decoder.DisallowUnknownFields only reports
+	// the first one and stops decoding) and so that every format is handled the same
+	raw, err := decodeRaw(data, opts.Format)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := raw.(map[string]any); !ok {
+		return nil, ValidationErrors{{Path: ROOT_PATH, Message: "the config must be an object"}}, nil
+	}
+
+	v := new(validator)
+	unknownFields(v, ROOT_PATH, raw, reflect.TypeFor[CliConfig]())
+
+	in := &interpolator{v: v, lookup: opts.LookupEnv, readFile: opts.ReadFile}
+	raw = in.walk(ROOT_PATH, raw, reflect.TypeFor[CliConfig](), false)
+
+	// NOTE: the interpolated values are encoded as JSON again so that the config is
+	// decoded with the same rules (and type errors) regardless of its format
+	data, err = json.Marshal(raw)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conf CliConfig
+	if err := decodeJSON(v, data, &conf); err != nil {
+		return nil, v.redacted(), nil
+	}
+
+	// NOTE: the declared chains decoded without errors, so the resolved chains (which
+	// only contain values from the declared chains, defaults and profiles) will too
+	data, err = json.Marshal(resolveChains(v, raw.(map[string]any)))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resolved := map[string]ChainConfig{}
+	if err := decodeJSON(v, data, &resolved); err != nil {
+		return nil, v.redacted(), nil
+	} else {
+		conf.declared = conf.Chains
+		conf.Chains = resolved
+	}
+
+	if err := conf.Validate(); err != nil {
+		v.errs = append(v.errs, err.(ValidationErrors)...)
+	}
+
+	return &conf, v.redacted(), nil
+}
+
+// decodeJSON decodes an encoded config and records a type error as a problem
+func decodeJSON(v *validator, data []byte, dst any) error {
+	err := json.NewDecoder(bytes.NewReader(data)).Decode(dst)
+	if err == nil {
+		return nil
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) {
+		v.fail(typePath(typeErr.Field), "expected a value of type %s but got %s", typeErr.Type, typeErr.Value)
+	} else {
+		v.fail(ROOT_PATH, "%v", err)
+	}
+	return err
+}
+
+func typePath(field string) string {
+	path := ROOT_PATH
+	if field == "" {
+		return path
+	}
+	for _, key := range strings.Split(field, ".") {
+		path = JoinPath(path, key)
+	}
+	return path
 }
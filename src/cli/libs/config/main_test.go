@@ -0,0 +1,165 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	embeds "github.com/chris-de-leon/chain-connectors-prototype"
+)
+
+const TEST_VALID_CHAIN = `{ "plugin": { "id": "eth" }, "server": { "host": "localhost", "port": 3000 }, "conn": { "wss": "wss://example.com/ws" } }`
+
+// problemPaths decodes a config and returns the paths of all problems in it
+func problemPaths(t *testing.T, data string) []string {
+	_, err := DecodeCliConfig(strings.NewReader(data), DecodeOptions{})
+	if err == nil {
+		return []string{}
+	}
+
+	var problems ValidationErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("expected validation errors but got: %v", err)
+	}
+
+	paths := make([]string, len(problems))
+	for i, problem := range problems {
+		paths[i] = problem.Path
+	}
+	return paths
+}
+
+func TestDecodeCliConfig(t *testing.T) {
+	testCases := []struct {
+		name  string
+		data  string
+		paths []string
+	}{
+		{
+			name:  "valid",
+			data:  `{ "$schema": "./config.schema.json", "chains": { "eth": ` + TEST_VALID_CHAIN + ` } }`,
+			paths: []string{},
+		},
+		{
+			name:  "unknown fields",
+			data:  `{ "chain": {}, "chains": { "eth": { "plugin": { "id": "eth", "versoin": "1.0.0" }, "Server": { "port": 1 }, "conn": { "wss": "ws://a:1" } } } }`,
+			paths: []string{"$.chain", "$.chains.eth.Server", "$.chains.eth.plugin.versoin"},
+		},
+		{
+			name:  "wrong type",
+			data:  `{ "chains": { "eth": { "plugin": { "id": "eth" }, "server": { "port": "3000" }, "conn": { "wss": "ws://a:1" } } } }`,
+			paths: []string{"$.chains.eth.server.port"},
+		},
+		{
+			name:  "missing blocks",
+			data:  `{ "chains": { "my.chain": { "plugin": {} } } }`,
+			paths: []string{`$.chains["my.chain"].plugin.id`, `$.chains["my.chain"].server`, `$.chains["my.chain"].conn`},
+		},
+		{
+			name:  "invalid values",
+			data:  `{ "chains": { "eth": { "plugin": { "id": "eth" }, "server": { "host": "a b", "port": 70000 }, "conn": { "wss": "https://example.com", "rpc": "ftp://example.com" }, "parachain": { "relay": "wss://" }, "restart": { "maxRestarts": -1, "backoff": "1m", "maxBackoff": "1s" } } } }`,
+			paths: []string{"$.chains.eth.server.port", "$.chains.eth.server.host", "$.chains.eth.conn.wss", "$.chains.eth.conn.rpc", "$.chains.eth.parachain.relay", "$.chains.eth.restart.maxRestarts", "$.chains.eth.restart.maxBackoff"},
+		},
+		{
+			name:  "endpoints",
+			data:  `{ "chains": { "flow": { "plugin": { "id": "flow" }, "server": { "port": 0 }, "conn": { "wss": "access.example.com:9000", "rpc": "/tmp/node.ipc", "sporks": [ { "name": "a", "url": "access.example.com:9000" }, { "name": "a", "url": "access.example.com" } ] } } } }`,
+			paths: []string{"$.chains.flow.conn.sporks[1].name", "$.chains.flow.conn.sporks[1].url"},
+		},
+		{
+			name:  "ipc endpoints",
+			data:  `{ "chains": { "a": { "plugin": { "id": "eth" }, "server": { "port": 0 }, "conn": { "rpc": "ipc:///var/lib/geth/geth.ipc" } }, "b": { "plugin": { "id": "eth" }, "server": { "port": 0 }, "conn": { "rpc": "geth.ipc" } }, "c": { "plugin": { "id": "eth" }, "server": { "port": 0 }, "conn": { "rpc": "ipc://" } } } }`,
+			paths: []string{"$.chains.c.conn.rpc"},
+		},
+		{
+			name:  "registries",
+			data:  `{ "registries": [ { "name": "a", "type": "local", "path": "/tmp" }, { "name": "a", "type": "http" }, { "type": "local" }, { "name": "b" } ] }`,
+			paths: []string{"$.registries[1].name", "$.registries[2].name", "$.registries[3].type"},
+		},
+		{
+			name:  "not an object",
+			data:  `[]`,
+			paths: []string{"$"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if paths := problemPaths(t, tc.data); !slices.Equal(paths, tc.paths) {
+				t.Fatalf("expected problems at %v but got %v", tc.paths, paths)
+			}
+		})
+	}
+}
+
+func TestDecodeCliConfigSyntaxError(t *testing.T) {
+	_, err := DecodeCliConfig(strings.NewReader("{\n  \"chains\": {,}\n}"), DecodeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "line 2, column 14") {
+		t.Fatalf("expected a syntax error with a position but got: %v", err)
+	}
+}
+
+func TestSuggestions(t *testing.T) {
+	_, err := DecodeCliConfig(strings.NewReader(`{ "chains": { "eth": { "plugin": { "id": "eth" }, "server": { "port": 1 }, "conn": { "wss": "ws://a:1" }, "restart": { "backof": "1s" } } } }`), DecodeOptions{})
+	if err == nil || !strings.Contains(err.Error(), "did you mean 'backoff'?") {
+		t.Fatalf("expected a suggestion but got: %v", err)
+	}
+}
+
+func TestExampleConfigs(t *testing.T) {
+	for _, name := range []string{"config.mainnet.json", "config.testnet.json"} {
+		if _, err := ParseCliConfig(filepath.Join("..", "..", "..", "..", name)); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+	}
+}
+
+// TestSchema checks that the published JSON Schema describes exactly the fields of
+// the config structs, so that the two cannot drift apart
+func TestSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal(embeds.ConfigSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+
+	var compare func(path string, node map[string]any, typ reflect.Type)
+	compare = func(path string, node map[string]any, typ reflect.Type) {
+		if ref, ok := node["$ref"].(string); ok {
+			node = schema["$defs"].(map[string]any)[strings.TrimPrefix(ref, "#/$defs/")].(map[string]any)
+		}
+		for typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+
+		switch typ.Kind() {
+		case reflect.Struct:
+			if node["additionalProperties"] != false {
+				t.Errorf("%s: expected additionalProperties to be false", path)
+			}
+
+			properties, _ := node["properties"].(map[string]any)
+			fields := jsonFields(typ)
+			for name, field := range fields {
+				if property, ok := properties[name].(map[string]any); !ok {
+					t.Errorf("%s: field '%s' is missing from the schema", path, name)
+				} else {
+					compare(JoinPath(path, name), property, field)
+				}
+			}
+			for name := range properties {
+				if _, ok := fields[name]; !ok {
+					t.Errorf("%s: schema property '%s' does not exist in the config", path, name)
+				}
+			}
+		case reflect.Map:
+			compare(JoinPath(path, "*"), node["additionalProperties"].(map[string]any), typ.Elem())
+		case reflect.Slice:
+			compare(path+"[*]", node["items"].(map[string]any), typ.Elem())
+		}
+	}
+
+	compare(ROOT_PATH, schema, reflect.TypeFor[CliConfig]())
+}
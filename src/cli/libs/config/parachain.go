@@ -0,0 +1,8 @@
+package config
+
+type (
+	ParachainConfig struct {
+		ID    uint32 `json:"id"`
+		Relay string `json:"relay" secret:"true"`
+	}
+)
@@ -1,7 +1,12 @@
 package config
 
 type (
+	// PluginConfig selects the plugin that serves a chain. The version can be set
+	// either with the 'version' field or as part of the ID (e.g. 'eth@1.2.0'). If it
+	// is omitted, then the newest installed version is used (or, if the plugin is not
+	// installed yet, the version that matches the CLI).
 	PluginConfig struct {
-		ID string `json:"id"`
+		ID      string `json:"id"`
+		Version string `json:"version,omitempty"`
 	}
 )
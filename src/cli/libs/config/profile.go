@@ -0,0 +1,101 @@
+package config
+
+import (
+	"maps"
+	"slices"
+)
+
+// resolveChains applies the defaults and profiles of a decoded config to each of its
+// chains. Objects are merged field by field, while any other value (including an
+// array) in a chain replaces the inherited value. This is done before the config is
+// decoded into a CliConfig so that an explicit zero value (e.g. port 0) still
+// overrides an inherited value.
+func resolveChains(v *validator, raw map[string]any) map[string]any {
+	defaults, _ := raw["defaults"].(map[string]any)
+	profiles, _ := raw["profiles"].(map[string]any)
+	chains, _ := raw["chains"].(map[string]any)
+
+	if _, ok := defaults["extends"]; ok {
+		v.fail(JoinPath(JoinPath(ROOT_PATH, "defaults"), "extends"), "the defaults cannot extend a profile")
+	}
+
+	resolver := &profileResolver{v: v, profiles: profiles, resolved: map[string]map[string]any{}}
+	resolved := make(map[string]any, len(chains))
+	for _, chainName := range slices.Sorted(maps.Keys(chains)) {
+		chain, ok := chains[chainName].(map[string]any)
+		if !ok {
+			resolved[chainName] = chains[chainName]
+			continue
+		}
+
+		path := JoinPath(JoinPath(ROOT_PATH, "chains"), chainName)
+		result := merge(defaults, resolver.extend(path, chain, []string{}))
+		delete(result, "extends")
+		resolved[chainName] = result
+	}
+
+	return resolved
+}
+
+type profileResolver struct {
+	v        *validator
+	profiles map[string]any
+	resolved map[string]map[string]any
+}
+
+// extend merges a chain (or profile) on top of the profile that it extends. The stack
+// holds the profiles that are currently being resolved so that cycles are detected.
+func (r *profileResolver) extend(path string, conf map[string]any, stack []string) map[string]any {
+	name, ok := conf["extends"].(string)
+	if !ok || name == "" {
+		return conf
+	}
+
+	profile, err := r.profile(name, stack)
+	if err != nil {
+		r.v.fail(JoinPath(path, "extends"), "%s", err)
+		return conf
+	} else {
+		return merge(profile, conf)
+	}
+}
+
+func (r *profileResolver) profile(name string, stack []string) (map[string]any, error) {
+	if profile, ok := r.resolved[name]; ok {
+		return profile, nil
+	}
+
+	if slices.Contains(stack, name) {
+		return nil, &ProfileCycleError{Profiles: slices.Concat(stack, []string{name})}
+	}
+
+	profile, ok := r.profiles[name].(map[string]any)
+	if !ok {
+		return nil, &ProfileNotFoundError{Name: name, Choices: slices.Sorted(maps.Keys(r.profiles))}
+	}
+
+	path := JoinPath(JoinPath(ROOT_PATH, "profiles"), name)
+	resolved := r.extend(path, profile, slices.Concat(stack, []string{name}))
+	r.resolved[name] = resolved
+	return resolved, nil
+}
+
+// merge returns a new object with the fields of override merged on top of base
+func merge(base map[string]any, override map[string]any) map[string]any {
+	result := make(map[string]any, len(base)+len(override))
+	for key, value := range base {
+		result[key] = value
+	}
+
+	for key, value := range override {
+		baseObj, baseIsObj := result[key].(map[string]any)
+		overrideObj, overrideIsObj := value.(map[string]any)
+		if baseIsObj && overrideIsObj {
+			result[key] = merge(baseObj, overrideObj)
+		} else {
+			result[key] = value
+		}
+	}
+
+	return result
+}
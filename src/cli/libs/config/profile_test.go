@@ -0,0 +1,130 @@
+package config
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	TEST_PROFILE_CONFIG = `
+defaults:
+  server: { host: 127.0.0.1, port: 3000 }
+  restart: { policy: on-failure, maxRestarts: 5, backoff: 2s }
+profiles:
+  evm:
+    plugin: { id: eth }
+    finality: finalized
+  evm-fast:
+    extends: evm
+    restart: { backoff: 500ms }
+chains:
+  moonbeam:
+    extends: evm-fast
+    server: { port: 0 }
+    conn: { wss: wss://moonbeam.example.com }
+  polygon:
+    extends: evm
+    conn: { wss: wss://polygon.example.com }
+    finality: ""
+  solana:
+    plugin: { id: solana }
+    server: { port: 3001 }
+    conn: { wss: wss://solana.example.com }
+`
+)
+
+func TestProfiles(t *testing.T) {
+	conf, err := DecodeCliConfig(strings.NewReader(TEST_PROFILE_CONFIG), DecodeOptions{Format: FORMAT_YAML})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	moonbeam, err := conf.Chain("moonbeam")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: an explicit zero value in a chain still overrides the defaults
+	if moonbeam.Server.Host != "127.0.0.1" || moonbeam.Server.Port != 0 {
+		t.Fatalf("unexpected server config: %+v", moonbeam.Server)
+	}
+	if moonbeam.Plugin.ID != "eth" || moonbeam.Finality != "finalized" || moonbeam.Extends != "" {
+		t.Fatalf("unexpected chain config: %+v", moonbeam)
+	}
+	if moonbeam.Restart.Policy != "on-failure" || moonbeam.Restart.MaxRestarts != 5 || moonbeam.Restart.Backoff.Duration() != 500*time.Millisecond {
+		t.Fatalf("unexpected restart config: %+v", moonbeam.Restart)
+	}
+
+	polygon, err := conf.Chain("polygon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if polygon.Finality != "" || polygon.Restart.Backoff.Duration() != 2*time.Second || polygon.Server.Port != 3000 {
+		t.Fatalf("unexpected chain config: %+v", polygon)
+	}
+
+	solana, err := conf.Chain("solana")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solana.Plugin.ID != "solana" || solana.Server.Host != "127.0.0.1" || solana.Server.Port != 3001 {
+		t.Fatalf("unexpected chain config: %+v", solana)
+	}
+
+	declared := conf.Declared()
+	if declared.Defaults == nil || len(declared.Profiles) != 2 || declared.Chains["moonbeam"].Extends != "evm-fast" || declared.Chains["moonbeam"].Plugin != nil {
+		t.Fatalf("unexpected declared config: %+v", declared)
+	}
+
+	resolved := conf.Resolved()
+	if resolved.Defaults != nil || resolved.Profiles != nil || resolved.Chains["moonbeam"].Plugin == nil {
+		t.Fatalf("unexpected resolved config: %+v", resolved)
+	}
+}
+
+func TestProfileErrors(t *testing.T) {
+	data := `{
+  "defaults": { "extends": "evm" },
+  "profiles": {
+    "a": { "extends": "b" },
+    "b": { "extends": "a" },
+    "evm": { "plugin": { "id": "eth" } }
+  },
+  "chains": {
+    "eth": {
+      "extends": "evn",
+      "server": { "port": 3000 },
+      "conn": { "wss": "wss://eth.example.com" }
+    },
+    "loop": {
+      "extends": "a",
+      "plugin": { "id": "eth" },
+      "server": { "port": 3001 },
+      "conn": { "wss": "wss://eth.example.com" }
+    }
+  }
+}`
+
+	_, err := DecodeCliConfig(strings.NewReader(data), DecodeOptions{})
+
+	var problems ValidationErrors
+	if !errors.As(err, &problems) {
+		t.Fatalf("expected validation errors but got: %v", err)
+	}
+
+	expected := map[string]string{
+		"$.defaults.extends":   "cannot extend a profile",
+		"$.chains.eth.extends": "profile 'evn' does not exist",
+		"$.profiles.b.extends": "a -> b -> a",
+	}
+	for path, message := range expected {
+		if !slices.ContainsFunc(problems, func(problem *ValidationError) bool {
+			return problem.Path == path && strings.Contains(problem.Message, message)
+		}) {
+			t.Errorf("expected a problem at %s containing %q but got: %v", path, message, problems)
+		}
+	}
+}
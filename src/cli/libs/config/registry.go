@@ -0,0 +1,27 @@
+package config
+
+type (
+	// RegistryConfig describes a source that plugins can be installed from. Which of
+	// the fields are required depends on the registry type.
+	RegistryConfig struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+
+		// Priority controls the order in which registries are searched (highest first)
+		Priority int64 `json:"priority,omitempty"`
+
+		// Url is the base URL of an HTTP registry or the repository of an OCI registry
+		Url string `json:"url,omitempty" secret:"true"`
+
+		// Path is the root directory of a local registry
+		Path string `json:"path,omitempty"`
+
+		// Owner and Repo identify the repository of a GitHub registry
+		Owner string `json:"owner,omitempty"`
+		Repo  string `json:"repo,omitempty"`
+
+		// PublicKey is an optional minisign public key (or the path to one) which is
+		// used to verify the checksums published by this registry
+		PublicKey string `json:"publicKey,omitempty"`
+	}
+)
@@ -0,0 +1,12 @@
+package config
+
+type (
+	RestartConfig struct {
+		Policy      string   `json:"policy,omitempty"`
+		MaxRestarts int64    `json:"maxRestarts,omitempty"`
+		Window      Duration `json:"window,omitempty"`
+		Backoff     Duration `json:"backoff,omitempty"`
+		MaxBackoff  Duration `json:"maxBackoff,omitempty"`
+		KillTimeout Duration `json:"killTimeout,omitempty"`
+	}
+)
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// SECRET_TAG marks the fields of the config that can hold secrets (e.g. RPC URLs that
+// contain an API key). Values that are interpolated into these fields are redacted.
+const SECRET_TAG = "secret"
+
+// secrets maps each secret value to the reference that it was read from (e.g.
+// ${ALCHEMY_KEY} or file:/run/secrets/rpc-url). Redacted output shows the reference
+// instead of the value, which is safe to print and still tells the reader where the
+// value came from.
+var secrets = struct {
+	mutex  sync.RWMutex
+	values map[string]string
+}{values: map[string]string{}}
+
+func registerSecret(value string, reference string) {
+	if strings.TrimSpace(value) == "" {
+		return
+	}
+
+	secrets.mutex.Lock()
+	defer secrets.mutex.Unlock()
+	secrets.values[value] = reference
+}
+
+// Redact replaces every secret that was read from the environment or from a secret
+// file while loading a config with the reference that it was read from. It should
+// be applied to anything that is printed or logged and may contain config values.
+// JSON-encoded secrets (e.g. in command output) are also redacted.
+func Redact(s string) string {
+	secrets.mutex.RLock()
+	defer secrets.mutex.RUnlock()
+	if len(secrets.values) == 0 {
+		return s
+	}
+
+	// NOTE: longer secrets are replaced first in case one secret contains another
+	values := make([]string, 0, len(secrets.values))
+	for value := range secrets.values {
+		values = append(values, value)
+	}
+	slices.SortFunc(values, func(a, b string) int {
+		return len(b) - len(a)
+	})
+
+	for _, value := range values {
+		reference := secrets.values[value]
+		s = strings.ReplaceAll(s, value, reference)
+		if encoded, err := json.Marshal(value); err == nil {
+			s = strings.ReplaceAll(s, strings.Trim(string(encoded), `"`), reference)
+		}
+	}
+
+	return s
+}
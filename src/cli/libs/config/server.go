@@ -1,6 +1,7 @@
 package config
 
 import (
+	"net"
 	"strconv"
 	"strings"
 )
@@ -15,3 +16,43 @@ type (
 func (c *ServerConfig) Url() string {
 	return strings.Join([]string{c.Host, strconv.FormatInt(c.Port, 10)}, ":")
 }
+
+// DialUrl returns the address that clients on the same host can connect to. Servers
+// that listen on all interfaces are reached through localhost.
+func (c *ServerConfig) DialUrl() string {
+	host := strings.Trim(c.Host, "[]")
+	if c.normalizedHost() == "" {
+		host = "localhost"
+	}
+	return net.JoinHostPort(host, strconv.FormatInt(c.Port, 10))
+}
+
+// ListensOnAllInterfaces returns true if the server can be reached from other hosts
+// (e.g. from outside of a container).
+func (c *ServerConfig) ListensOnAllInterfaces() bool {
+	return c.normalizedHost() == ""
+}
+
+func (c *ServerConfig) normalizedHost() string {
+	switch c.Host {
+	case "", "0.0.0.0", "::", "[::]":
+		return ""
+	case "localhost", "::1", "[::1]":
+		return "127.0.0.1"
+	default:
+		return c.Host
+	}
+}
+
+// Overlaps returns true if both servers would try to listen on the same address. A
+// server that listens on all interfaces overlaps with every server on the same port
+// and a port of zero (i.e. a random port) never overlaps with anything.
+func (c *ServerConfig) Overlaps(other *ServerConfig) bool {
+	if c.Port == 0 || c.Port != other.Port {
+		return false
+	}
+
+	a := c.normalizedHost()
+	b := other.normalizedHost()
+	return a == "" || b == "" || a == b
+}
@@ -0,0 +1,9 @@
+package config
+
+type (
+	SporkConfig struct {
+		Name       string `json:"name"`
+		RootHeight uint64 `json:"rootHeight"`
+		Url        string `json:"url" secret:"true"`
+	}
+)
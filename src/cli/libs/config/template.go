@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+const (
+	NETWORK_MAINNET = "mainnet"
+	NETWORK_TESTNET = "testnet"
+
+	DEFAULT_SERVER_HOST = "localhost"
+	DEFAULT_SERVER_PORT = 3000
+)
+
+type (
+	// ChainTemplate holds the settings that configs for a well-known chain are built
+	// from. The name of the template is also the default name of the chain.
+	ChainTemplate struct {
+		Name        string
+		Description string
+		Plugin      string
+		Finality    string
+		Networks    map[string]ConnectionConfg
+	}
+)
+
+// NOTE: these are public endpoints which are rate limited - they are meant to get a
+// config running quickly and should be replaced with dedicated endpoints later
+var Templates = []ChainTemplate{
+	{
+		Name:        "ethereum",
+		Description: "Ethereum execution layer blocks",
+		Plugin:      "eth",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Wss: "wss://ethereum-rpc.publicnode.com"},
+			NETWORK_TESTNET: {Wss: "wss://ethereum-sepolia-rpc.publicnode.com"},
+		},
+	},
+	{
+		Name:        "ethereum-beacon",
+		Description: "Finalized Ethereum beacon chain slots",
+		Plugin:      "beacon",
+		Finality:    "finalized",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Rpc: "https://ethereum-beacon-api.publicnode.com"},
+			NETWORK_TESTNET: {Rpc: "https://ethereum-holesky-beacon-api.publicnode.com"},
+		},
+	},
+	{
+		Name:        "moonbeam",
+		Description: "Moonbeam blocks (through its Ethereum compatible API)",
+		Plugin:      "eth",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Wss: "wss://wss.api.moonbeam.network"},
+			NETWORK_TESTNET: {Wss: "wss://wss.api.moonbase.moonbeam.network"},
+		},
+	},
+	{
+		Name:        "polkadot",
+		Description: "Polkadot relay chain blocks",
+		Plugin:      "substrate",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Wss: "wss://rpc.polkadot.io"},
+			NETWORK_TESTNET: {Wss: "wss://westend-rpc.polkadot.io"},
+		},
+	},
+	{
+		Name:        "solana",
+		Description: "Solana slots",
+		Plugin:      "solana",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Rpc: "https://api.mainnet.solana.com", Wss: "wss://api.mainnet.solana.com"},
+			NETWORK_TESTNET: {Rpc: "https://api.testnet.solana.com", Wss: "wss://api.testnet.solana.com"},
+		},
+	},
+	{
+		Name:        "flow",
+		Description: "Flow blocks (through the access API)",
+		Plugin:      "flow",
+		Networks: map[string]ConnectionConfg{
+			NETWORK_MAINNET: {Wss: "access.mainnet.nodes.onflow.org:9000"},
+			NETWORK_TESTNET: {Wss: "access.devnet.nodes.onflow.org:9000"},
+		},
+	},
+}
+
+func TemplateNames() []string {
+	names := make([]string, len(Templates))
+	for i, template := range Templates {
+		names[i] = template.Name
+	}
+	return names
+}
+
+func FindTemplate(name string) (*ChainTemplate, error) {
+	for _, template := range Templates {
+		if template.Name == name {
+			return &template, nil
+		}
+	}
+	return nil, &TemplateNotFoundError{Name: name, Choices: TemplateNames()}
+}
+
+// Chain builds the config of a chain on the given network which is served at the
+// given address.
+func (t *ChainTemplate) Chain(network string, server ServerConfig) (*ChainConfig, error) {
+	conn, exists := t.Networks[network]
+	if !exists {
+		return nil, fmt.Errorf(
+			"template '%s' does not support network '%s' - must be one of: [ %s ]",
+			t.Name,
+			network,
+			strings.Join(slices.Sorted(maps.Keys(t.Networks)), ", "),
+		)
+	}
+
+	return &ChainConfig{
+		Plugin:   &PluginConfig{ID: t.Plugin},
+		Server:   &server,
+		Conn:     &conn,
+		Finality: t.Finality,
+	}, nil
+}
+
+// FreePort returns the first port (starting at the given port) that a server on the
+// given host can listen on without conflicting with the server of any chain in the
+// config.
+func (c *CliConfig) FreePort(host string, start int64) (int64, error) {
+	for port := max(start, 1); port <= 65535; port++ {
+		server := &ServerConfig{Host: host, Port: port}
+		conflict := false
+		for _, chainConfig := range c.Chains {
+			if chainConfig.Server != nil && server.Overlaps(chainConfig.Server) {
+				conflict = true
+				break
+			}
+		}
+		if !conflict {
+			return port, nil
+		}
+	}
+	return 0, fmt.Errorf("no free port found on host '%s' starting at port %d", host, start)
+}
@@ -0,0 +1,205 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	for _, template := range Templates {
+		for _, network := range []string{NETWORK_MAINNET, NETWORK_TESTNET} {
+			chainConfig, err := template.Chain(network, ServerConfig{Host: DEFAULT_SERVER_HOST, Port: DEFAULT_SERVER_PORT})
+			if err != nil {
+				t.Fatalf("%s (%s): %v", template.Name, network, err)
+			}
+			if err := chainConfig.Validate(); err != nil {
+				t.Fatalf("%s (%s): %v", template.Name, network, err)
+			}
+		}
+	}
+
+	if _, err := FindTemplate("ethereum"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FindTemplate("etherium"); !errors.Is(err, &TemplateNotFoundError{}) {
+		t.Fatalf("expected a TemplateNotFoundError but got: %v", err)
+	}
+}
+
+func TestFreePort(t *testing.T) {
+	conf := &CliConfig{
+		Chains: map[string]ChainConfig{
+			"a": {Server: &ServerConfig{Host: "localhost", Port: 3000}},
+			"b": {Server: &ServerConfig{Host: "0.0.0.0", Port: 3001}},
+			"c": {Server: &ServerConfig{Host: "10.0.0.1", Port: 3002}},
+			"d": {Server: &ServerConfig{Host: "localhost", Port: 0}},
+		},
+	}
+
+	testCases := []struct {
+		host     string
+		start    int64
+		expected int64
+	}{
+		{host: "localhost", start: 3000, expected: 3002},
+		{host: "127.0.0.1", start: 3000, expected: 3002},
+		{host: "10.0.0.1", start: 3000, expected: 3000},
+		{host: "10.0.0.1", start: 3001, expected: 3003},
+		{host: "", start: 3000, expected: 3003},
+		{host: "localhost", start: 0, expected: 1},
+	}
+
+	for _, testCase := range testCases {
+		port, err := conf.FreePort(testCase.host, testCase.start)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if port != testCase.expected {
+			t.Fatalf("expected port %d on host '%s' but got %d", testCase.expected, testCase.host, port)
+		}
+	}
+
+	if _, err := conf.FreePort("localhost", 65536); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestAddChains(t *testing.T) {
+	template, err := FindTemplate("polkadot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainConfig, err := template.Chain(NETWORK_TESTNET, ServerConfig{Host: DEFAULT_SERVER_HOST, Port: 3001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		format string
+		data   string
+	}{
+		{format: FORMAT_JSON, data: ``},
+		{format: FORMAT_JSON, data: `{ "chains": { "eth": { "plugin": { "id": "eth" }, "server": { "port": 3000 }, "conn": { "wss": "${ETH_WSS}" } } } }`},
+		{format: FORMAT_YAML, data: "chains:\n  eth:\n    plugin: { id: eth }\n    server: { port: 3000 }\n    conn: { wss: '${ETH_WSS}' }\n"},
+		{format: FORMAT_TOML, data: "[chains.eth]\nplugin = { id = \"eth\" }\nserver = { port = 3000 }\nconn = { wss = \"${ETH_WSS}\" }\n"},
+	}
+
+	for _, testCase := range testCases {
+		data, err := AddChains([]byte(testCase.data), testCase.format, map[string]ChainConfig{"polkadot": *chainConfig}, true)
+		if err != nil {
+			t.Fatalf("%s: %v", testCase.format, err)
+		}
+
+		// NOTE: references in the existing chains must not be interpolated
+		if testCase.data != "" && !bytes.Contains(data, []byte("${ETH_WSS}")) {
+			t.Fatalf("%s: expected the environment variable reference to be kept:\n%s", testCase.format, data)
+		}
+
+		conf, err := DecodeCliConfig(bytes.NewReader(data), DecodeOptions{
+			Format:    testCase.format,
+			LookupEnv: func(string) (string, bool) { return "wss://eth.example.com", true },
+		})
+		if err != nil {
+			t.Fatalf("%s: %v\n%s", testCase.format, err, data)
+		}
+
+		polkadot, err := conf.Chain("polkadot")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if polkadot.Server.Port != 3001 || polkadot.Conn.Wss != template.Networks[NETWORK_TESTNET].Wss || polkadot.Plugin.ID != "substrate" {
+			t.Fatalf("%s: unexpected chain config: %+v", testCase.format, polkadot)
+		}
+		if testCase.data != "" && len(conf.Chains) != 2 {
+			t.Fatalf("%s: expected the existing chain to be kept but got chains %v", testCase.format, conf.ChainNames())
+		}
+	}
+
+	_, err = AddChains([]byte(`{ "chains": { "polkadot": {} } }`), FORMAT_JSON, map[string]ChainConfig{"polkadot": *chainConfig}, false)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an error but got: %v", err)
+	}
+	_, err = AddChains([]byte("chains:\n  polkadot: {}\n"), FORMAT_YAML, map[string]ChainConfig{"polkadot": *chainConfig}, false)
+	if err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Fatalf("expected an error but got: %v", err)
+	}
+
+	// NOTE: TOML files lose their comments when they are encoded again
+	_, err = AddChains([]byte("# mainnet chains\n[chains.eth]\nplugin = { id = \"eth\" }\n"), FORMAT_TOML, map[string]ChainConfig{"polkadot": *chainConfig}, false)
+	if err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("expected an error but got: %v", err)
+	}
+	if _, err := AddChains([]byte(""), FORMAT_TOML, map[string]ChainConfig{"polkadot": *chainConfig}, false); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAddChainsKeepsYamlComments(t *testing.T) {
+	template, err := FindTemplate("polkadot")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainConfig, err := template.Chain(NETWORK_TESTNET, ServerConfig{Host: DEFAULT_SERVER_HOST, Port: 3001})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name string
+		data string
+	}{
+		{name: "existing chains", data: `# Chains that are run in production
+registries:
+  - name: mirror # the air-gapped mirror
+    type: local
+    path: /srv/plugins
+chains:
+  # Ethereum mainnet
+  eth:
+    server: { port: 3000 }
+    plugin: { id: eth }
+    conn: { wss: '${ETH_WSS}' } # set in cc.env
+`},
+		{name: "no chains", data: "# Chains that are run in production\nchains: {}\n"},
+		{name: "only comments", data: "# Chains that are run in production\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			data, err := AddChains([]byte(tc.data), FORMAT_YAML, map[string]ChainConfig{"polkadot": *chainConfig}, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// NOTE: every comment and the order of the existing keys are kept
+			for _, line := range strings.Split(tc.data, "\n") {
+				if _, comment, ok := strings.Cut(line, "#"); ok && !bytes.Contains(data, []byte("#"+comment)) {
+					t.Fatalf("expected comment '#%s' to be kept:\n%s", comment, data)
+				}
+			}
+			if tc.name == "existing chains" {
+				if bytes.Index(data, []byte("registries:")) > bytes.Index(data, []byte("chains:")) || bytes.Index(data, []byte("server:")) > bytes.Index(data, []byte("plugin:")) {
+					t.Fatalf("expected the key order to be kept:\n%s", data)
+				}
+				if !bytes.Contains(data, []byte("'${ETH_WSS}'")) {
+					t.Fatalf("expected the quoting style to be kept:\n%s", data)
+				}
+			}
+
+			conf, err := DecodeCliConfig(bytes.NewReader(data), DecodeOptions{
+				Format:    FORMAT_YAML,
+				LookupEnv: func(string) (string, bool) { return "wss://eth.example.com", true },
+			})
+			if err != nil {
+				t.Fatalf("%v\n%s", err, data)
+			}
+			if polkadot, err := conf.Chain("polkadot"); err != nil || polkadot.Server.Port != 3001 {
+				t.Fatalf("unexpected chain config: %+v (%v)\n%s", polkadot, err, data)
+			}
+		})
+	}
+}
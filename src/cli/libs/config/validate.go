@@ -0,0 +1,369 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"net"
+	"net/url"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const ROOT_PATH = "$"
+
+// IPC_SCHEME_PREFIX is the prefix of RPC endpoints that are IPC sockets (e.g.
+// 'ipc:///var/lib/geth/geth.ipc'), as accepted by the eth plugin
+const IPC_SCHEME_PREFIX = "ipc://"
+
+var (
+	hostnameRegex   = regexp.MustCompile(`^[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?(\.[A-Za-z0-9]([A-Za-z0-9-]*[A-Za-z0-9])?)*$`)
+	identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+	wssSchemes = []string{"ws", "wss"}
+	rpcSchemes = []string{"http", "https", "ws", "wss"}
+)
+
+// validator collects problems instead of stopping at the first one, so that a
+// config with several mistakes can be fixed in one go
+type validator struct {
+	errs ValidationErrors
+}
+
+func (v *validator) fail(path string, format string, args ...any) {
+	v.errs = append(v.errs, &ValidationError{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// redacted returns the problems with any secrets removed from their messages (e.g.
+// an invalid endpoint that was read from a secret file)
+func (v *validator) redacted() ValidationErrors {
+	for _, err := range v.errs {
+		err.Message = Redact(err.Message)
+	}
+	return v.errs
+}
+
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	} else {
+		return v.errs
+	}
+}
+
+// JoinPath appends an object key to a JSON path. Keys that are not identifiers (e.g.
+// chain names with dots) are quoted.
+func JoinPath(path string, key string) string {
+	if identifierRegex.MatchString(key) {
+		return path + "." + key
+	} else {
+		return fmt.Sprintf("%s[%s]", path, strconv.Quote(key))
+	}
+}
+
+// IndexPath appends an array index to a JSON path.
+func IndexPath(path string, index int) string {
+	return fmt.Sprintf("%s[%d]", path, index)
+}
+
+// Validate checks every value of the config and returns a ValidationErrors error
+// that lists all problems. Checks that depend on installed plugins (e.g. whether a
+// plugin ID exists) are not performed here.
+func (c *CliConfig) Validate() error {
+	v := new(validator)
+
+	for _, chainName := range c.ChainNames() {
+		chainConfig := c.Chains[chainName]
+		path := JoinPath(JoinPath(ROOT_PATH, "chains"), chainName)
+		if strings.TrimSpace(chainName) == "" {
+			v.fail(path, "chain names must not be empty")
+		}
+		chainConfig.validate(v, path)
+	}
+
+	names := map[string]bool{}
+	for i, registryConfig := range c.Registries {
+		path := IndexPath(JoinPath(ROOT_PATH, "registries"), i)
+		if registryConfig.Name == "" {
+			v.fail(JoinPath(path, "name"), "is required")
+		} else if names[registryConfig.Name] {
+			v.fail(JoinPath(path, "name"), "registry '%s' is defined more than once", registryConfig.Name)
+		} else {
+			names[registryConfig.Name] = true
+		}
+		if registryConfig.Type == "" {
+			v.fail(JoinPath(path, "type"), "is required")
+		}
+	}
+
+	return v.err()
+}
+
+// Validate checks a single chain config (e.g. one that was built from CLI flags).
+// The paths of the problems are relative to the chain config.
+func (c *ChainConfig) Validate() error {
+	v := new(validator)
+	c.validate(v, ROOT_PATH)
+	return v.err()
+}
+
+func (c *ChainConfig) validate(v *validator, path string) {
+	if c.Plugin == nil {
+		v.fail(JoinPath(path, "plugin"), "is required")
+	} else if strings.TrimSpace(c.Plugin.ID) == "" {
+		v.fail(JoinPath(JoinPath(path, "plugin"), "id"), "is required")
+	}
+
+	if c.Server == nil {
+		v.fail(JoinPath(path, "server"), "is required")
+	} else {
+		c.Server.validate(v, JoinPath(path, "server"))
+	}
+
+	if c.Conn == nil {
+		v.fail(JoinPath(path, "conn"), "is required")
+	} else {
+		c.Conn.validate(v, JoinPath(path, "conn"))
+	}
+
+	if c.Parachain != nil {
+		relayPath := JoinPath(JoinPath(path, "parachain"), "relay")
+		if c.Parachain.Relay == "" {
+			v.fail(relayPath, "is required")
+		} else {
+			validateEndpoint(v, relayPath, c.Parachain.Relay, wssSchemes, false, false)
+		}
+	}
+
+	if c.Restart != nil {
+		c.Restart.validate(v, JoinPath(path, "restart"))
+	}
+}
+
+func (c *ServerConfig) validate(v *validator, path string) {
+	if c.Port < 0 || c.Port > 65535 {
+		v.fail(JoinPath(path, "port"), "must be between 0 and 65535 but got %d", c.Port)
+	}
+	if c.Host != "" && !isHost(c.Host) {
+		v.fail(JoinPath(path, "host"), "'%s' is not a valid hostname or IP address", c.Host)
+	}
+}
+
+func (c *ConnectionConfg) validate(v *validator, path string) {
+	if c.Wss == "" && c.Rpc == "" && len(c.Sporks) == 0 {
+		v.fail(path, "must contain at least one of 'wss', 'rpc' or 'sporks'")
+	}
+
+	// NOTE: some plugins connect to gRPC endpoints which are given as 'host:port'
+	// without a scheme, and some plugins accept the path of an IPC socket as an RPC
+	// endpoint
+	if c.Wss != "" {
+		validateEndpoint(v, JoinPath(path, "wss"), c.Wss, wssSchemes, true, false)
+	}
+	if c.Rpc != "" {
+		validateEndpoint(v, JoinPath(path, "rpc"), c.Rpc, rpcSchemes, true, true)
+	}
+
+	names := map[string]bool{}
+	for i, spork := range c.Sporks {
+		sporkPath := IndexPath(JoinPath(path, "sporks"), i)
+		if spork.Name == "" {
+			v.fail(JoinPath(sporkPath, "name"), "is required")
+		} else if names[spork.Name] {
+			v.fail(JoinPath(sporkPath, "name"), "spork '%s' is defined more than once", spork.Name)
+		} else {
+			names[spork.Name] = true
+		}
+		if spork.Url == "" {
+			v.fail(JoinPath(sporkPath, "url"), "is required")
+		} else {
+			validateEndpoint(v, JoinPath(sporkPath, "url"), spork.Url, wssSchemes, true, false)
+		}
+	}
+}
+
+func (c *RestartConfig) validate(v *validator, path string) {
+	if c.MaxRestarts < 0 {
+		v.fail(JoinPath(path, "maxRestarts"), "must not be negative")
+	}
+
+	durations := []struct {
+		name  string
+		value Duration
+	}{
+		{"window", c.Window},
+		{"backoff", c.Backoff},
+		{"maxBackoff", c.MaxBackoff},
+		{"killTimeout", c.KillTimeout},
+	}
+	for _, d := range durations {
+		if d.value < 0 {
+			v.fail(JoinPath(path, d.name), "must not be negative")
+		}
+	}
+
+	if c.Backoff > 0 && c.MaxBackoff > 0 && c.MaxBackoff < c.Backoff {
+		v.fail(JoinPath(path, "maxBackoff"), "must not be less than 'backoff' (%s)", c.Backoff.Duration())
+	}
+}
+
+// validateEndpoint checks that an endpoint is a URL with one of the given schemes
+// or, if allowed, a 'host:port' address or a socket path (either as a path or as an
+// 'ipc://' URL)
+func validateEndpoint(v *validator, path string, endpoint string, schemes []string, allowHostPort bool, allowSocket bool) {
+	if allowSocket && strings.HasPrefix(strings.ToLower(endpoint), IPC_SCHEME_PREFIX) {
+		if len(endpoint) == len(IPC_SCHEME_PREFIX) {
+			v.fail(path, "'%s' does not contain a socket path", endpoint)
+		}
+		return
+	}
+
+	if strings.Contains(endpoint, "://") {
+		u, err := url.Parse(endpoint)
+		if err != nil {
+			v.fail(path, "'%s' is not a valid URL: %v", endpoint, err)
+			return
+		}
+		if !slices.Contains(schemes, strings.ToLower(u.Scheme)) {
+			v.fail(path, "'%s' has an unsupported scheme '%s' - must be one of: [ %s ]", endpoint, u.Scheme, strings.Join(schemes, ", "))
+		}
+		if !isHost(u.Hostname()) {
+			v.fail(path, "'%s' does not contain a valid host", endpoint)
+		}
+		if port := u.Port(); port != "" && !isPort(port) {
+			v.fail(path, "'%s' contains an invalid port '%s'", endpoint, port)
+		}
+		return
+	}
+
+	if allowHostPort {
+		if host, port, err := net.SplitHostPort(endpoint); err == nil && isHost(host) && isPort(port) {
+			return
+		}
+	}
+
+	// NOTE: relative socket paths (e.g. 'geth.ipc') are resolved against the working
+	// directory of the plugin, so any endpoint that does not look like an address is
+	// treated as a path
+	if allowSocket && (strings.HasPrefix(endpoint, "/") || strings.HasPrefix(endpoint, ".") || !strings.Contains(endpoint, ":")) {
+		return
+	}
+
+	expected := fmt.Sprintf("a URL with one of the schemes [ %s ]", strings.Join(schemes, ", "))
+	if allowHostPort {
+		expected += " or a 'host:port' address"
+	}
+	if allowSocket {
+		expected += " or a socket path"
+	}
+	v.fail(path, "'%s' is not a valid endpoint - must be %s", endpoint, expected)
+}
+
+func isHost(host string) bool {
+	return net.ParseIP(strings.Trim(host, "[]")) != nil || hostnameRegex.MatchString(host)
+}
+
+func isPort(port string) bool {
+	n, err := strconv.ParseUint(port, 10, 16)
+	return err == nil && n != 0
+}
+
+// unknownFields walks a decoded JSON value alongside the Go type that it will be
+// decoded into and reports every object key that does not match a field. Unlike
+// json.Decoder.DisallowUnknownFields, this reports all unknown fields (with their
+// paths) rather than only the first one. Keys are matched case-sensitively.
+func unknownFields(v *validator, path string, value any, typ reflect.Type) {
+	for typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+
+	switch typ.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return
+		}
+
+		fields := jsonFields(typ)
+		for _, key := range slices.Sorted(maps.Keys(obj)) {
+			if field, ok := fields[key]; ok {
+				unknownFields(v, JoinPath(path, key), obj[key], field)
+			} else if suggestion := suggest(key, fields); suggestion != "" {
+				v.fail(JoinPath(path, key), "unknown field - did you mean '%s'?", suggestion)
+			} else {
+				v.fail(JoinPath(path, key), "unknown field")
+			}
+		}
+	case reflect.Map:
+		if obj, ok := value.(map[string]any); ok {
+			for _, key := range slices.Sorted(maps.Keys(obj)) {
+				unknownFields(v, JoinPath(path, key), obj[key], typ.Elem())
+			}
+		}
+	case reflect.Slice:
+		if arr, ok := value.([]any); ok {
+			for i, elem := range arr {
+				unknownFields(v, IndexPath(path, i), elem, typ.Elem())
+			}
+		}
+	}
+}
+
+func jsonFields(typ reflect.Type) map[string]reflect.Type {
+	fields := map[string]reflect.Type{}
+	for i := range typ.NumField() {
+		field := typ.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" || !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// suggest returns the field whose name is closest to an unknown key (e.g. to catch
+// typos and wrong casing) or an empty string if no field is close enough
+func suggest(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if strings.EqualFold(name, key) {
+			return name
+		}
+		if d := levenshtein(strings.ToLower(key), strings.ToLower(name)); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	if bestDistance > 2 {
+		return ""
+	} else {
+		return best
+	}
+}
+
+func levenshtein(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
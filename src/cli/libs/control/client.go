@@ -0,0 +1,149 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// Client talks to the control API of a daemon over its unix socket.
+type Client struct {
+	socket string
+	http   *http.Client
+}
+
+func NewClient(socket string) *Client {
+	return &Client{
+		socket: socket,
+		http: &http.Client{
+			Transport: &http.Transport{
+				DialContext: func(ctx context.Context, network string, addr string) (net.Conn, error) {
+					return (&net.Dialer{}).DialContext(ctx, "unix", socket)
+				},
+			},
+		},
+	}
+}
+
+// Chains returns the status of every chain that the daemon manages.
+func (c *Client) Chains(ctx context.Context) ([]ChainStatus, error) {
+	statuses := []ChainStatus{}
+	return statuses, c.do(ctx, http.MethodGet, "/chains", &statuses)
+}
+
+// Chain returns the status of a single chain.
+func (c *Client) Chain(ctx context.Context, chainName string) (*ChainStatus, error) {
+	status := &ChainStatus{}
+	return status, c.do(ctx, http.MethodGet, chainPath(chainName, ""), status)
+}
+
+// Start starts a chain that has stopped and returns its new status.
+func (c *Client) Start(ctx context.Context, chainName string) (*ChainStatus, error) {
+	status := &ChainStatus{}
+	return status, c.do(ctx, http.MethodPost, chainPath(chainName, "start"), status)
+}
+
+// Stop stops a chain and returns its new status.
+func (c *Client) Stop(ctx context.Context, chainName string) (*ChainStatus, error) {
+	status := &ChainStatus{}
+	return status, c.do(ctx, http.MethodPost, chainPath(chainName, "stop"), status)
+}
+
+// Restart restarts a chain and returns its new status.
+func (c *Client) Restart(ctx context.Context, chainName string) (*ChainStatus, error) {
+	status := &ChainStatus{}
+	return status, c.do(ctx, http.MethodPost, chainPath(chainName, "restart"), status)
+}
+
+// Cursor returns the latest cursor that the daemon received from a chain.
+func (c *Client) Cursor(ctx context.Context, chainName string) (*CursorStatus, error) {
+	cursor := &CursorStatus{}
+	return cursor, c.do(ctx, http.MethodGet, chainPath(chainName, "cursor"), cursor)
+}
+
+// Health returns the health of a chain or, if no chain name is given, of every chain.
+func (c *Client) Health(ctx context.Context, chainName string) ([]Health, error) {
+	path := "/health"
+	if chainName != "" {
+		path += "?" + url.Values{"name": {chainName}}.Encode()
+	}
+
+	healths := []Health{}
+	return healths, c.do(ctx, http.MethodGet, path, &healths)
+}
+
+// Logs copies the last lines of a chain's output to the writer (or all lines that are
+// kept if lines is not positive). If follow is set, then new lines are copied until
+// the context is cancelled or the daemon stops.
+func (c *Client) Logs(ctx context.Context, chainName string, lines int, follow bool, w io.Writer) error {
+	query := url.Values{"lines": {strconv.Itoa(lines)}, "follow": {strconv.FormatBool(follow)}}
+	res, err := c.request(ctx, http.MethodGet, chainPath(chainName, "logs")+"?"+query.Encode())
+	if err != nil {
+		return err
+	} else {
+		defer res.Body.Close()
+	}
+
+	if _, err := io.Copy(w, res.Body); err != nil && ctx.Err() == nil {
+		return err
+	} else {
+		return nil
+	}
+}
+
+func (c *Client) do(ctx context.Context, method string, path string, result any) error {
+	res, err := c.request(ctx, method, path)
+	if err != nil {
+		return err
+	} else {
+		defer res.Body.Close()
+	}
+
+	return json.NewDecoder(res.Body).Decode(result)
+}
+
+// request sends a request to the daemon and turns error responses into errors
+func (c *Client) request(ctx context.Context, method string, path string) (*http.Response, error) {
+	// NOTE: the host is ignored since every request is sent over the socket
+	req, err := http.NewRequestWithContext(ctx, method, "http://daemon"+path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := c.http.Do(req)
+	if err != nil {
+		var opErr *net.OpError
+		if errors.As(err, &opErr) && opErr.Op == "dial" {
+			return nil, fmt.Errorf("could not connect to the daemon at %s - is it running? (%w)", c.socket, err)
+		} else {
+			return nil, err
+		}
+	}
+
+	if res.StatusCode < 300 {
+		return res, nil
+	} else {
+		defer res.Body.Close()
+	}
+
+	var errRes errorResponse
+	if err := json.NewDecoder(res.Body).Decode(&errRes); err != nil || errRes.Error == "" {
+		return nil, fmt.Errorf("daemon responded with status %s", res.Status)
+	} else {
+		return nil, errors.New(errRes.Error)
+	}
+}
+
+func chainPath(chainName string, action string) string {
+	path := "/chains/" + url.PathEscape(chainName)
+	if action != "" {
+		path += "/" + action
+	}
+	return path
+}
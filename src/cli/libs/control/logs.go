@@ -0,0 +1,81 @@
+package control
+
+import (
+	"strings"
+	"sync"
+)
+
+// LOG_SUBSCRIBER_BUFFER is how many lines a log subscriber can fall behind before
+// lines are dropped
+const LOG_SUBSCRIBER_BUFFER = 256
+
+// LogBuffer keeps the most recent lines of a chain's output and forwards new lines to
+// its subscribers. It expects to be written complete lines (e.g. by a redact writer).
+type LogBuffer struct {
+	mutex       *sync.Mutex
+	lines       []string
+	limit       int
+	subscribers map[chan string]struct{}
+}
+
+func NewLogBuffer(limit int) *LogBuffer {
+	return &LogBuffer{
+		mutex:       &sync.Mutex{},
+		lines:       []string{},
+		limit:       max(limit, 1),
+		subscribers: map[chan string]struct{}{},
+	}
+}
+
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(p), "\n"), "\n") {
+		b.lines = append(b.lines, line)
+
+		// NOTE: a subscriber that cannot keep up misses lines rather than blocking the
+		// plugin's output
+		for subscriber := range b.subscribers {
+			select {
+			case subscriber <- line:
+			default:
+			}
+		}
+	}
+
+	if len(b.lines) > b.limit {
+		b.lines = append([]string{}, b.lines[len(b.lines)-b.limit:]...)
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the last n lines (or all lines if n is not positive).
+func (b *LogBuffer) Lines(n int) []string {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.tail(n)
+}
+
+// Subscribe returns the last n lines along with a channel that receives every line
+// that is written afterwards. The returned function cancels the subscription.
+func (b *LogBuffer) Subscribe(n int) ([]string, <-chan string, func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	subscriber := make(chan string, LOG_SUBSCRIBER_BUFFER)
+	b.subscribers[subscriber] = struct{}{}
+	return b.tail(n), subscriber, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		delete(b.subscribers, subscriber)
+	}
+}
+
+func (b *LogBuffer) tail(n int) []string {
+	if n <= 0 || n > len(b.lines) {
+		n = len(b.lines)
+	}
+	return append([]string{}, b.lines[len(b.lines)-n:]...)
+}
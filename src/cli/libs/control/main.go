@@ -0,0 +1,59 @@
+package control
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/dirs"
+)
+
+const (
+	// SOCKET_FILE_NAME is the name of the daemon's unix socket in the CLI config
+	// directory
+	SOCKET_FILE_NAME = "daemon.sock"
+
+	// DEFAULT_LOG_LINES is how many lines of output are kept for each chain
+	DEFAULT_LOG_LINES = 1000
+
+	STATE_STARTING = "starting"
+	STATE_RUNNING  = "running"
+	STATE_STOPPED  = "stopped"
+	STATE_FAILED   = "failed"
+)
+
+type (
+	// ChainStatus describes a chain that is managed by the daemon. A chain is starting
+	// until its plugin completes the handshake (and again while the plugin restarts),
+	// and it is stopped or failed once its supervisor exits.
+	ChainStatus struct {
+		Name    string        `json:"name"`
+		Plugin  string        `json:"plugin"`
+		State   string        `json:"state"`
+		Pid     int           `json:"pid,omitempty"`
+		Address string        `json:"address,omitempty"`
+		Cursor  *CursorStatus `json:"cursor,omitempty"`
+		Error   string        `json:"error,omitempty"`
+	}
+
+	// CursorStatus is the latest cursor that the daemon received from a chain's plugin.
+	CursorStatus struct {
+		Value      string    `json:"value"`
+		ReceivedAt time.Time `json:"receivedAt"`
+	}
+
+	// Health reports whether a chain's plugin is running and streaming cursors.
+	Health struct {
+		Name    string `json:"name"`
+		Healthy bool   `json:"healthy"`
+		Reason  string `json:"reason,omitempty"`
+	}
+
+	errorResponse struct {
+		Error string `json:"error"`
+	}
+)
+
+// DefaultSocket returns the path of the daemon's socket in the CLI config directory.
+func DefaultSocket() string {
+	return filepath.Join(dirs.Config, SOCKET_FILE_NAME)
+}
@@ -0,0 +1,108 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+)
+
+func TestLogBuffer(t *testing.T) {
+	logs := NewLogBuffer(3)
+	logs.Write([]byte("a\nb\n"))
+
+	backlog, subscriber, cancel := logs.Subscribe(1)
+	defer cancel()
+	if !slices.Equal(backlog, []string{"b"}) {
+		t.Fatalf("unexpected backlog: %v", backlog)
+	}
+
+	logs.Write([]byte("c\nd\n"))
+	for _, expected := range []string{"c", "d"} {
+		if line := <-subscriber; line != expected {
+			t.Fatalf("expected line '%s' but got '%s'", expected, line)
+		}
+	}
+
+	// NOTE: only the most recent lines are kept
+	if lines := logs.Lines(0); !slices.Equal(lines, []string{"b", "c", "d"}) {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+	if lines := logs.Lines(2); !slices.Equal(lines, []string{"c", "d"}) {
+		t.Fatalf("unexpected lines: %v", lines)
+	}
+}
+
+func TestServer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	socket := filepath.Join(t.TempDir(), "run", SOCKET_FILE_NAME)
+	lis, err := Listen(ctx, socket)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: only the current user can access the socket and its directory
+	for path, mode := range map[string]os.FileMode{filepath.Dir(socket): 0700, socket: 0600} {
+		if info, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		} else if info.Mode().Perm() != mode {
+			t.Fatalf("expected '%s' to have mode %o but got %o", path, mode, info.Mode().Perm())
+		}
+	}
+
+	// NOTE: a second daemon must not take over the socket of a running daemon
+	if _, err := Listen(ctx, socket); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	logger := log.New(io.Discard, "", 0)
+	server := NewServer(ctx, logger)
+	f := fleet.New(ctx, fleet.Options{Hooks: server.Hooks, KeepAlive: true, Logger: logger})
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(ctx, lis, f)
+	}()
+
+	client := NewClient(socket)
+	if chains, err := client.Chains(ctx); err != nil {
+		t.Fatal(err)
+	} else if len(chains) != 0 {
+		t.Fatalf("expected no chains but got %v", chains)
+	}
+
+	if _, err := client.Stop(ctx, "eth"); err == nil || !strings.Contains(err.Error(), "chain 'eth' is not managed") {
+		t.Fatalf("expected a chain not found error but got: %v", err)
+	}
+	if err := client.Logs(ctx, "eth", 0, false, new(bytes.Buffer)); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	// NOTE: the fleet keeps running without any chains until it is cancelled
+	cancel()
+	select {
+	case err := <-errs:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("expected the server to shut down")
+	}
+	if err := f.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := NewClient(socket).Chains(context.Background()); err == nil || !strings.Contains(err.Error(), "is it running?") {
+		t.Fatalf("expected a connection error but got: %v", err)
+	}
+}
@@ -0,0 +1,364 @@
+package control
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/fleet"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/gateway"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/tail"
+)
+
+// Server serves the control API of a fleet over HTTP. Before the fleet is created,
+// its Output and Hooks options are pointed at the server so that it can keep each
+// chain's logs and follow each chain's cursors.
+type Server struct {
+	ctx    context.Context
+	fleet  *fleet.Fleet
+	chains map[string]*chainState
+	mutex  *sync.Mutex
+	logger *log.Logger
+}
+
+// chainState is what the server knows about a chain beyond its fleet state. It is
+// kept when the chain's plugin restarts.
+type chainState struct {
+	logs      *LogBuffer
+	cursor    *CursorStatus
+	streaming bool
+	cancel    context.CancelFunc
+}
+
+func NewServer(ctx context.Context, logger *log.Logger) *Server {
+	return &Server{
+		ctx:    ctx,
+		chains: map[string]*chainState{},
+		mutex:  &sync.Mutex{},
+		logger: logger,
+	}
+}
+
+// Output copies a chain's output into the chain's log buffer.
+func (s *Server) Output(chainName string, out plgn.ProcessOptions) plgn.ProcessOptions {
+	logs := s.state(chainName).logs
+	out.Stdout = io.MultiWriter(out.Stdout, logs)
+	out.Stderr = io.MultiWriter(out.Stderr, logs)
+	return out
+}
+
+// Hooks follows the cursors of a chain's plugin while it is ready.
+func (s *Server) Hooks(chainName string, conf *config.ChainConfig) supervisor.Hooks {
+	return supervisor.Hooks{
+		OnReady: func(proc *plgn.Process) {
+			s.follow(chainName, proc.Handshake.Address)
+		},
+		OnExit: func(exitCode int, err error) {
+			s.unfollow(chainName)
+		},
+	}
+}
+
+// Serve serves the control API on the listener until the context is cancelled.
+func (s *Server) Serve(ctx context.Context, lis net.Listener, f *fleet.Fleet) error {
+	s.fleet = f
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /chains", s.handleChains)
+	mux.HandleFunc("GET /chains/{name}", s.handleChain)
+	mux.HandleFunc("POST /chains/{name}/start", s.handleAction(f.Start))
+	mux.HandleFunc("POST /chains/{name}/stop", s.handleAction(f.Stop))
+	mux.HandleFunc("POST /chains/{name}/restart", s.handleAction(f.Restart))
+	mux.HandleFunc("GET /chains/{name}/cursor", s.handleCursor)
+	mux.HandleFunc("GET /chains/{name}/logs", s.handleLogs)
+	mux.HandleFunc("GET /health", s.handleHealth)
+
+	// NOTE: requests inherit the context so that streams (e.g. followed logs) end
+	// when the server shuts down
+	server := &http.Server{
+		Handler:     mux,
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	errs := make(chan error, 1)
+	go func() {
+		errs <- server.Serve(lis)
+	}()
+
+	select {
+	case err := <-errs:
+		return err
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second*5)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	}
+}
+
+// Listen listens on a unix socket that only the current user can access. The
+// socket's directory is created (accessible only by the current user) if it does not
+// exist. A socket that was left behind by a daemon that did not shut down cleanly is
+// replaced, but a socket that another daemon is still listening on is not.
+func Listen(ctx context.Context, socket string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socket), 0700); err != nil {
+		return nil, err
+	}
+	if conn, err := net.Dial("unix", socket); err == nil {
+		conn.Close()
+		return nil, fmt.Errorf("another daemon is already listening on %s", socket)
+	}
+	if err := os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	// NOTE: the umask is restricted while the socket is created so that other users
+	// cannot connect to it before its permissions are set below
+	umask := syscall.Umask(0077)
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "unix", socket)
+	syscall.Umask(umask)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.Chmod(socket, 0600); err != nil {
+		return nil, errors.Join(err, lis.Close())
+	} else {
+		return lis, nil
+	}
+}
+
+func (s *Server) state(chainName string) *chainState {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	state, exists := s.chains[chainName]
+	if !exists {
+		state = &chainState{logs: NewLogBuffer(DEFAULT_LOG_LINES), cancel: func() {}}
+		s.chains[chainName] = state
+	}
+	return state
+}
+
+// follow streams cursors from a plugin that has become ready and records the latest
+// one until the plugin exits.
+func (s *Server) follow(chainName string, address string) {
+	ctx, cancel := context.WithCancel(s.ctx)
+
+	state := s.state(chainName)
+	s.mutex.Lock()
+	state.cancel()
+	state.cancel = cancel
+	state.streaming = false
+	s.mutex.Unlock()
+
+	opts := tail.Options{Address: gateway.DialAddress(address), Reconnect: true}
+	go func() {
+		err := tail.Tail(ctx, opts, func(cursor *big.Int) error {
+			s.mutex.Lock()
+			defer s.mutex.Unlock()
+			state.cursor = &CursorStatus{Value: cursor.String(), ReceivedAt: time.Now().UTC()}
+			state.streaming = true
+			return nil
+		})
+		if err != nil {
+			s.logger.Printf("Stopped following the cursors of chain '%s': %v", chainName, err)
+		}
+	}()
+}
+
+func (s *Server) unfollow(chainName string) {
+	state := s.state(chainName)
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	state.cancel()
+	state.streaming = false
+}
+
+func (s *Server) status(chain *fleet.Chain) *ChainStatus {
+	conf := chain.Config()
+	status := &ChainStatus{Name: chain.Name, Plugin: conf.Plugin.ID}
+	if ref, err := plgn.RefFromConfig(conf.Plugin); err == nil {
+		status.Plugin = ref.String()
+	}
+
+	proc := chain.Process()
+	switch {
+	case chain.Err() != nil:
+		status.State = STATE_FAILED
+		status.Error = config.Redact(chain.Err().Error())
+	case chain.IsStopped():
+		status.State = STATE_STOPPED
+	case proc == nil:
+		status.State = STATE_STARTING
+	default:
+		status.State = STATE_RUNNING
+		status.Pid = proc.Cmd.Process.Pid
+		status.Address = proc.Handshake.Address
+	}
+
+	state := s.state(chain.Name)
+	s.mutex.Lock()
+	status.Cursor = state.cursor
+	s.mutex.Unlock()
+	return status
+}
+
+func (s *Server) health(chain *fleet.Chain) *Health {
+	status := s.status(chain)
+	health := &Health{Name: chain.Name}
+
+	state := s.state(chain.Name)
+	s.mutex.Lock()
+	streaming := state.streaming
+	s.mutex.Unlock()
+
+	switch {
+	case status.State != STATE_RUNNING:
+		health.Reason = fmt.Sprintf("chain is %s", status.State)
+	case !streaming:
+		health.Reason = "no cursor has been received since the plugin started"
+	default:
+		health.Healthy = true
+	}
+	return health
+}
+
+func (s *Server) handleChains(w http.ResponseWriter, r *http.Request) {
+	chains := s.fleet.Chains()
+	statuses := make([]*ChainStatus, len(chains))
+	for i, chain := range chains {
+		statuses[i] = s.status(chain)
+	}
+	writeJSON(w, http.StatusOK, statuses)
+}
+
+func (s *Server) handleChain(w http.ResponseWriter, r *http.Request) {
+	if chain, err := s.fleet.Chain(r.PathValue("name")); err != nil {
+		writeError(w, err)
+	} else {
+		writeJSON(w, http.StatusOK, s.status(chain))
+	}
+}
+
+func (s *Server) handleAction(action func(chainName string) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := action(r.PathValue("name")); err != nil {
+			writeError(w, err)
+		} else {
+			s.handleChain(w, r)
+		}
+	}
+}
+
+func (s *Server) handleCursor(w http.ResponseWriter, r *http.Request) {
+	chain, err := s.fleet.Chain(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	if status := s.status(chain); status.Cursor == nil {
+		writeJSON(w, http.StatusServiceUnavailable, &errorResponse{Error: fmt.Sprintf("no cursor has been received from chain '%s' yet", chain.Name)})
+	} else {
+		writeJSON(w, http.StatusOK, status.Cursor)
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	chains := s.fleet.Chains()
+	if name := r.URL.Query().Get("name"); name != "" {
+		if chain, err := s.fleet.Chain(name); err != nil {
+			writeError(w, err)
+			return
+		} else {
+			chains = []*fleet.Chain{chain}
+		}
+	}
+
+	healths := make([]*Health, len(chains))
+	for i, chain := range chains {
+		healths[i] = s.health(chain)
+	}
+	writeJSON(w, http.StatusOK, healths)
+}
+
+// handleLogs writes the last lines of a chain's output as plain text and, if follow
+// is set, keeps writing new lines until the client disconnects.
+func (s *Server) handleLogs(w http.ResponseWriter, r *http.Request) {
+	chain, err := s.fleet.Chain(r.PathValue("name"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	lines := 0
+	if value := r.URL.Query().Get("lines"); value != "" {
+		if lines, err = strconv.Atoi(value); err != nil {
+			writeJSON(w, http.StatusBadRequest, &errorResponse{Error: fmt.Sprintf("invalid number of lines '%s'", value)})
+			return
+		}
+	}
+
+	logs := s.state(chain.Name).logs
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if r.URL.Query().Get("follow") != "true" {
+		for _, line := range logs.Lines(lines) {
+			fmt.Fprintln(w, line)
+		}
+		return
+	}
+
+	backlog, subscriber, cancel := logs.Subscribe(lines)
+	defer cancel()
+
+	flusher, _ := w.(http.Flusher)
+	for _, line := range backlog {
+		fmt.Fprintln(w, line)
+	}
+	if flusher != nil {
+		flusher.Flush()
+	}
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case line := <-subscriber:
+			if _, err := fmt.Fprintln(w, line); err != nil {
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(value)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	code := http.StatusInternalServerError
+	if errors.Is(err, &fleet.ChainNotFoundError{}) {
+		code = http.StatusNotFound
+	}
+	writeJSON(w, code, &errorResponse{Error: config.Redact(err.Error())})
+}
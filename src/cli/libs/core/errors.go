@@ -0,0 +1,87 @@
+package core
+
+import (
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/urfave/cli/v3"
+)
+
+// Exit codes of the CLI. The run commands exit with the plugin's exit code when a
+// plugin fails permanently.
+const (
+	EXIT_OK          = 0
+	EXIT_ERROR       = 1
+	EXIT_USAGE       = 2
+	EXIT_CONFIG      = 3
+	EXIT_NOT_FOUND   = 4
+	EXIT_INTEGRITY   = 5
+	EXIT_UNAVAILABLE = 6
+)
+
+// Error codes are stable names for the kinds of errors that commands fail with.
+const (
+	CODE_ERROR         = "ERROR"
+	CODE_USAGE         = "USAGE"
+	CODE_CONFIG        = "CONFIG_INVALID"
+	CODE_NOT_FOUND     = "NOT_FOUND"
+	CODE_INTEGRITY     = "INTEGRITY"
+	CODE_INCOMPATIBLE  = "INCOMPATIBLE"
+	CODE_UNAVAILABLE   = "UNAVAILABLE"
+	CODE_PLUGIN_EXITED = "PLUGIN_EXITED"
+	CODE_CHECK_FAILED  = "CHECK_FAILED"
+)
+
+type (
+	// CommandError is the error that a command fails with. If the code is empty, then
+	// the code and exit status are derived from the wrapped error when it is printed.
+	CommandError struct {
+		Err    error
+		Code   string
+		Status int
+	}
+
+	// ErrorResult is how errors are printed in the JSON and YAML output formats.
+	ErrorResult struct {
+		Code     string `json:"code"`
+		Message  string `json:"message"`
+		ExitCode int    `json:"exitCode"`
+	}
+)
+
+func (e *CommandError) Error() string {
+	return config.Redact(e.Err.Error())
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+func (e *CommandError) ExitCode() int {
+	if e.Status == 0 {
+		return EXIT_ERROR
+	} else {
+		return e.Status
+	}
+}
+
+// ErrExit wraps an error that a command fails with. Secrets from the config are
+// redacted from its message.
+func ErrExit(err error) error {
+	return &CommandError{Err: err}
+}
+
+// ErrExitWithCode is like ErrExit, but with an explicit error code and exit status.
+func ErrExitWithCode(err error, code string, status int) error {
+	return &CommandError{Err: err, Code: code, Status: status}
+}
+
+// PrintError prints an error in the selected output format to the command's error
+// writer. JSON and YAML errors are printed as {"Error": {"code": ..., "message": ...,
+// "exitCode": ...}}, while the other formats only print the message.
+func PrintError(cmd *cli.Command, result *ErrorResult) error {
+	switch format := OutputFormat(cmd); format {
+	case OUTPUT_JSON, OUTPUT_YAML:
+		return Render(cmd.Root().ErrWriter, format, "Error", result)
+	default:
+		return Render(cmd.Root().ErrWriter, OUTPUT_TEXT, "Error", result.Message)
+	}
+}
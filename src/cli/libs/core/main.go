@@ -1,7 +1,6 @@
 package core
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -23,39 +22,36 @@ func VersionWithPrefix() string {
 	return fmt.Sprintf("v%s", VersionWithoutPrefix())
 }
 
-func PrintResults(cmd *cli.Command, results []string) error {
-	output, err := json.MarshalIndent(map[string][]string{"Result": results}, "", " ")
-	if err != nil {
-		return err
-	} else {
-		fmt.Fprintln(cmd.Root().Writer, string(output))
-	}
-	return nil
+// PrintResult prints a structured result (e.g. a report) in the output format that was
+// selected with the global flag. Secrets from the config are redacted.
+func PrintResult(cmd *cli.Command, result any) error {
+	return Render(cmd.Root().Writer, OutputFormat(cmd), "Result", result)
 }
 
-func CleanDir(c *cli.Command, dir string, force bool) error {
+// CleanResult reports whether a directory was removed
+type CleanResult struct {
+	Path    string `json:"path"`
+	Removed bool   `json:"removed"`
+}
+
+func CleanDir(c *cli.Command, dir string, force bool) (*CleanResult, error) {
 	response := "y"
 
 	if !force {
-		fmt.Fprintf(c.Root().Writer, "Remove '%s' (y/n): ", dir)
+		// NOTE: the prompt is written to stderr so that it does not mix with the result
+		fmt.Fprintf(c.Root().ErrWriter, "Remove '%s' (y/n): ", dir)
 		if _, err := fmt.Scanf("%s", &response); err != nil {
-			return err
+			return nil, err
 		}
 	}
 
-	if response == "y" {
-		if err := os.RemoveAll(dir); err != nil {
-			return err
-		} else {
-			fmt.Fprintf(c.Root().Writer, "Removed '%s'\n", dir)
-		}
-	} else {
-		fmt.Fprintf(c.Root().Writer, "Skipping '%s'\n", dir)
+	if response != "y" {
+		return &CleanResult{Path: dir, Removed: false}, nil
 	}
 
-	return nil
-}
-
-func ErrExit(err error) error {
-	return cli.Exit(err, 1)
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	} else {
+		return &CleanResult{Path: dir, Removed: true}, nil
+	}
 }
@@ -0,0 +1,335 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	OUTPUT_JSON  = "json"
+	OUTPUT_YAML  = "yaml"
+	OUTPUT_TABLE = "table"
+	OUTPUT_TEXT  = "text"
+
+	// OUTPUT_FLAG is the name of the global flag that selects the output format
+	OUTPUT_FLAG = "output"
+)
+
+var OutputFormats = []string{OUTPUT_JSON, OUTPUT_YAML, OUTPUT_TABLE, OUTPUT_TEXT}
+
+// object is a decoded JSON object that keeps its keys in the order that they were
+// encoded in (i.e. the order of the struct fields), so that every output format lists
+// fields in the same order
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+// OutputFormat returns the output format that was selected with the global flag.
+func OutputFormat(cmd *cli.Command) string {
+	if format := cmd.Root().String(OUTPUT_FLAG); format != "" {
+		return format
+	} else {
+		return OUTPUT_JSON
+	}
+}
+
+// CheckOutputFormat returns an error if the output format is not supported.
+func CheckOutputFormat(format string) error {
+	if slices.Contains(OutputFormats, format) {
+		return nil
+	} else {
+		return fmt.Errorf("invalid output format '%s' - must be one of: [ %s ]", format, strings.Join(OutputFormats, ", "))
+	}
+}
+
+// Render writes a result in the given format. JSON and YAML results are wrapped in an
+// object with a single key (e.g. {"Result": ...}), while tables and text only contain
+// the result itself.
+func Render(w io.Writer, format string, key string, result any) error {
+	if format == OUTPUT_JSON || format == "" {
+		output, err := json.MarshalIndent(map[string]any{key: result}, "", " ")
+		if err != nil {
+			return err
+		} else {
+			_, err := fmt.Fprintln(w, config.Redact(string(output)))
+			return err
+		}
+	}
+
+	// NOTE: results are converted through JSON so that every format uses the same
+	// field names, omits the same fields and encodes values (e.g. durations) the same
+	data, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(strings.NewReader(config.Redact(string(data))))
+	dec.UseNumber()
+	value, err := decodeOrdered(dec)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case OUTPUT_YAML:
+		return renderYAML(w, &object{keys: []string{key}, values: map[string]any{key: value}})
+	case OUTPUT_TABLE:
+		return renderTable(w, value)
+	case OUTPUT_TEXT:
+		return renderText(w, value)
+	default:
+		return CheckOutputFormat(format)
+	}
+}
+
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	delim, ok := token.(json.Delim)
+	if !ok {
+		return token, nil
+	}
+
+	switch delim {
+	case '{':
+		obj := &object{keys: []string{}, values: map[string]any{}}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			}
+
+			obj.keys = append(obj.keys, key.(string))
+			obj.values[key.(string)] = value
+		}
+		_, err := dec.Token()
+		return obj, err
+	case '[':
+		arr := []any{}
+		for dec.More() {
+			value, err := decodeOrdered(dec)
+			if err != nil {
+				return nil, err
+			} else {
+				arr = append(arr, value)
+			}
+		}
+		_, err := dec.Token()
+		return arr, err
+	default:
+		return nil, fmt.Errorf("unexpected JSON delimiter '%s'", delim)
+	}
+}
+
+func renderYAML(w io.Writer, value any) error {
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(yamlNode(value)); err != nil {
+		return err
+	} else {
+		return enc.Close()
+	}
+}
+
+func yamlNode(value any) *yaml.Node {
+	switch v := value.(type) {
+	case *object:
+		node := &yaml.Node{Kind: yaml.MappingNode}
+		for _, key := range v.keys {
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: key}, yamlNode(v.values[key]))
+		}
+		return node
+	case []any:
+		node := &yaml.Node{Kind: yaml.SequenceNode}
+		for _, elem := range v {
+			node.Content = append(node.Content, yamlNode(elem))
+		}
+		return node
+	case json.Number:
+		if strings.ContainsAny(v.String(), ".eE") {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: v.String()}
+		} else {
+			return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: v.String()}
+		}
+	case bool:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(v)}
+	case string:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: v}
+	default:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+}
+
+// renderTable prints a list of objects as a table with a column per field, a single
+// object as a table with a row per field, and anything else as text
+func renderTable(w io.Writer, value any) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	switch v := value.(type) {
+	case *object:
+		fmt.Fprintln(tw, "FIELD\tVALUE")
+		for _, key := range v.keys {
+			fmt.Fprintf(tw, "%s\t%s\n", key, cell(v.values[key]))
+		}
+	case []any:
+		columns := []string{}
+		for _, elem := range v {
+			obj, ok := elem.(*object)
+			if !ok {
+				return renderText(w, value)
+			}
+			for _, key := range obj.keys {
+				if !slices.Contains(columns, key) {
+					columns = append(columns, key)
+				}
+			}
+		}
+
+		header := make([]string, len(columns))
+		for i, column := range columns {
+			header[i] = strings.ToUpper(column)
+		}
+		if len(columns) != 0 {
+			fmt.Fprintln(tw, strings.Join(header, "\t"))
+		}
+
+		for _, elem := range v {
+			row := make([]string, len(columns))
+			for i, column := range columns {
+				row[i] = cell(elem.(*object).values[column])
+			}
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+	default:
+		return renderText(w, value)
+	}
+	return tw.Flush()
+}
+
+// renderText prints a value for shell scripts: scalars as they are, the elements of
+// a list on separate lines (objects as key=value pairs), and the fields of an object
+// on separate lines
+func renderText(w io.Writer, value any) error {
+	var out strings.Builder
+	switch v := value.(type) {
+	case *object:
+		for _, key := range v.keys {
+			if elems, ok := v.values[key].([]any); ok && len(elems) != 0 {
+				fmt.Fprintf(&out, "%s:\n", key)
+				for _, elem := range elems {
+					fmt.Fprintf(&out, "  %s\n", line(elem))
+				}
+			} else {
+				fmt.Fprintf(&out, "%s: %s\n", key, cell(v.values[key]))
+			}
+		}
+	case []any:
+		for _, elem := range v {
+			fmt.Fprintln(&out, line(elem))
+		}
+	default:
+		fmt.Fprintln(&out, cell(v))
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// line formats a list element as a single line of text
+func line(value any) string {
+	obj, ok := value.(*object)
+	if !ok {
+		return cell(value)
+	}
+
+	pairs := make([]string, len(obj.keys))
+	for i, key := range obj.keys {
+		s := cell(obj.values[key])
+		if s == "" || strings.ContainsAny(s, " =\"") {
+			s = strconv.Quote(s)
+		}
+		pairs[i] = key + "=" + s
+	}
+	return strings.Join(pairs, " ")
+}
+
+// cell formats a value so that it fits on a single line
+func cell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.Join(strings.Fields(v), " ")
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		scalars := make([]string, len(v))
+		for i, elem := range v {
+			switch elem.(type) {
+			case *object, []any:
+				return compact(v)
+			default:
+				scalars[i] = cell(elem)
+			}
+		}
+		return strings.Join(scalars, ", ")
+	default:
+		return compact(v)
+	}
+}
+
+// compact encodes a decoded value as single-line JSON (with its keys in order)
+func compact(value any) string {
+	var out bytes.Buffer
+	writeCompact(&out, value)
+	return out.String()
+}
+
+func writeCompact(out *bytes.Buffer, value any) {
+	switch v := value.(type) {
+	case *object:
+		out.WriteByte('{')
+		for i, key := range v.keys {
+			if i != 0 {
+				out.WriteByte(',')
+			}
+			writeCompact(out, key)
+			out.WriteByte(':')
+			writeCompact(out, v.values[key])
+		}
+		out.WriteByte('}')
+	case []any:
+		out.WriteByte('[')
+		for i, elem := range v {
+			if i != 0 {
+				out.WriteByte(',')
+			}
+			writeCompact(out, elem)
+		}
+		out.WriteByte(']')
+	default:
+		// NOTE: scalars always encode successfully
+		data, _ := json.Marshal(v)
+		out.Write(data)
+	}
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+)
+
+type testResult struct {
+	Name    string   `json:"name"`
+	Version string   `json:"version"`
+	Count   int      `json:"count"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+func TestRender(t *testing.T) {
+	results := []*testResult{
+		{Name: "eth", Version: "1.0.0", Count: 2, Tags: []string{"a", "b"}},
+		{Name: "flow", Version: "1.10.0", Count: 10},
+	}
+
+	testCases := []struct {
+		format   string
+		result   any
+		expected string
+	}{
+		{
+			format:   OUTPUT_JSON,
+			result:   results[1],
+			expected: "{\n \"Result\": {\n  \"name\": \"flow\",\n  \"version\": \"1.10.0\",\n  \"count\": 10\n }\n}\n",
+		},
+		{
+			format:   OUTPUT_YAML,
+			result:   results,
+			expected: "Result:\n  - name: eth\n    version: 1.0.0\n    count: 2\n    tags:\n      - a\n      - b\n  - name: flow\n    version: 1.10.0\n    count: 10\n",
+		},
+		{
+			format:   OUTPUT_TABLE,
+			result:   results,
+			expected: "NAME  VERSION  COUNT  TAGS\neth   1.0.0    2      a, b\nflow  1.10.0   10     \n",
+		},
+		{
+			format:   OUTPUT_TABLE,
+			result:   results[1],
+			expected: "FIELD    VALUE\nname     flow\nversion  1.10.0\ncount    10\n",
+		},
+		{
+			format:   OUTPUT_TEXT,
+			result:   results,
+			expected: "name=eth version=1.0.0 count=2 tags=\"a, b\"\nname=flow version=1.10.0 count=10\n",
+		},
+		{
+			format:   OUTPUT_TEXT,
+			result:   results[0],
+			expected: "name: eth\nversion: 1.0.0\ncount: 2\ntags:\n  a\n  b\n",
+		},
+		{
+			format:   OUTPUT_TEXT,
+			result:   "v1.0.0",
+			expected: "v1.0.0\n",
+		},
+	}
+
+	for _, testCase := range testCases {
+		var out bytes.Buffer
+		if err := Render(&out, testCase.format, "Result", testCase.result); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != testCase.expected {
+			t.Fatalf("unexpected %s output:\n%s\nexpected:\n%s", testCase.format, out.String(), testCase.expected)
+		}
+	}
+
+	if err := Render(&bytes.Buffer{}, "xml", "Result", results); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := CheckOutputFormat("xml"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
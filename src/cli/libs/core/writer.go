@@ -0,0 +1,56 @@
+package core
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// PrefixWriter prepends a prefix to every line that is written to it. Partial lines
+// are buffered until they are completed so that output from several writers which
+// share the same destination is never interleaved mid-line.
+type PrefixWriter struct {
+	dst    io.Writer
+	prefix []byte
+	buffer []byte
+	mutex  *sync.Mutex
+	redact func(string) string
+}
+
+func NewPrefixWriter(dst io.Writer, prefix string) *PrefixWriter {
+	return &PrefixWriter{dst: dst, prefix: []byte(prefix), buffer: []byte{}, mutex: &sync.Mutex{}}
+}
+
+// NewRedactWriter returns a writer that passes every complete line through redact
+// before it is written to dst (e.g. to remove secrets from a plugin's logs).
+func NewRedactWriter(dst io.Writer, redact func(string) string) *PrefixWriter {
+	return &PrefixWriter{dst: dst, prefix: []byte{}, buffer: []byte{}, mutex: &sync.Mutex{}, redact: redact}
+}
+
+func (w *PrefixWriter) Write(p []byte) (int, error) {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+
+	w.buffer = append(w.buffer, p...)
+	for {
+		i := bytes.IndexByte(w.buffer, '\n')
+		if i == -1 {
+			break
+		}
+
+		line := make([]byte, 0, len(w.prefix)+i+1)
+		line = append(line, w.prefix...)
+		if w.redact != nil {
+			line = append(line, w.redact(string(w.buffer[:i+1]))...)
+		} else {
+			line = append(line, w.buffer[:i+1]...)
+		}
+		if _, err := w.dst.Write(line); err != nil {
+			return 0, err
+		} else {
+			w.buffer = w.buffer[i+1:]
+		}
+	}
+
+	return len(p), nil
+}
@@ -0,0 +1,116 @@
+package deploy
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+const (
+	COMPOSE_FILE_NAME   = "compose.yaml"
+	COMPOSE_DATA_VOLUME = "cc-data"
+)
+
+// NOTE: the image has no gRPC health check tool, so the health check only checks that
+// the plugin accepts connections (with bash's /dev/tcp)
+var composeTemplate = template.Must(template.New(TARGET_COMPOSE).Funcs(template.FuncMap{
+	"quote": composeQuote,
+	"ref":   composeRef,
+}).Parse(`# Generated by 'cc deploy generate'
+{{- if .Env }}
+#
+# The config references environment variables, which are passed through from the
+# environment that 'docker compose' runs in (or from a .env file next to this file)
+{{- end }}
+services:
+{{- range .Chains }}
+  {{ .Resource }}:
+    image: {{ quote $.Image }}
+    command: ["plugins", "run", "from-config", "--config", {{ quote $.ConfigFile }}, "--name", {{ quote .Name }}]
+    restart: unless-stopped
+    stop_grace_period: 30s
+    environment:
+      CC_HOME: {{ quote $.DataDir }}
+      {{- range $.Env }}
+      {{ . }}: {{ ref . }}
+      {{- end }}
+    ports:
+      - "{{ .Port }}:{{ .Port }}"
+    volumes:
+      - {{ quote (print $.ConfigSource ":" $.ConfigFile ":ro") }}
+      {{- range $.Files }}
+      - {{ quote (print .Source ":" .Path ":ro") }}
+      {{- end }}
+      - {{ quote (print $.Volume ":" $.DataDir) }}
+    healthcheck:
+      test: ["CMD", "bash", "-c", "exec 3<>/dev/tcp/127.0.0.1/{{ .Port }}"]
+      interval: 10s
+      timeout: 5s
+      retries: 3
+      start_period: 60s
+{{- end }}
+volumes:
+  {{ .Volume }}: {}
+`))
+
+// compose creates a compose file with a service for every chain. The services share
+// a volume that the plugins are installed in, and the config file is mounted from
+// ConfigSource along with the secret files that it reads. Relative secret files are
+// mounted from the directory of ConfigSource.
+func compose(d *data) (*Result, error) {
+	if err := d.conf.PortConflicts(chainNames(d.Chains)); err != nil {
+		return nil, err
+	}
+
+	configSource := composeSource(d.ConfigSource)
+
+	files := []map[string]string{}
+	for _, file := range d.Files {
+		source := filepath.ToSlash(file.Ref)
+		if !path.IsAbs(source) {
+			source = composeSource(path.Join(path.Dir(filepath.ToSlash(d.ConfigSource)), source))
+		}
+		files = append(files, map[string]string{"Source": source, "Path": file.Path})
+	}
+
+	content, err := render(composeTemplate, map[string]any{
+		"Chains":       d.Chains,
+		"Env":          d.Env,
+		"Files":        files,
+		"Image":        d.Image,
+		"ConfigFile":   d.ConfigFile,
+		"ConfigSource": configSource,
+		"DataDir":      DATA_DIR,
+		"Volume":       COMPOSE_DATA_VOLUME,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Result{
+		Files:    []File{{Path: COMPOSE_FILE_NAME, Content: content}},
+		Warnings: containerWarnings(d),
+	}, nil
+}
+
+// composeSource turns a path into a bind mount source. Compose treats a path that
+// does not start with '.' or '/' as a volume name.
+func composeSource(source string) string {
+	if !strings.HasPrefix(source, ".") && !filepath.IsAbs(source) {
+		return "./" + source
+	} else {
+		return source
+	}
+}
+
+// composeQuote quotes a string and escapes the '$' characters in it, which compose
+// would otherwise interpolate
+func composeQuote(s string) string {
+	return quote(strings.ReplaceAll(s, "$", "$$"))
+}
+
+// composeRef references an environment variable that compose passes through
+func composeRef(name string) string {
+	return quote("${" + name + "}")
+}
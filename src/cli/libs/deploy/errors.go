@@ -0,0 +1,24 @@
+package deploy
+
+import (
+	"fmt"
+	"strings"
+)
+
+type TargetNotFoundError struct {
+	Name    string
+	Choices []string
+}
+
+func (e *TargetNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"deployment target '%s' does not exist - must be one of: [ %s ]",
+		e.Name,
+		strings.Join(e.Choices, ", "),
+	)
+}
+
+func (e *TargetNotFoundError) Is(target error) bool {
+	_, ok := target.(*TargetNotFoundError)
+	return ok
+}
@@ -0,0 +1,207 @@
+package deploy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+)
+
+const (
+	KUBERNETES_FILE_NAME  = "kubernetes.yaml"
+	KUBERNETES_CONFIG_MAP = "cc-config"
+)
+
+var namespaceRegex = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`)
+
+// NOTE: the plugins do not implement the gRPC health checking protocol, so the probes
+// only check that the plugin accepts connections on its port. The startup probe gives
+// the CLI time to download the plugin when the pod starts.
+var kubernetesTemplate = template.Must(template.New(TARGET_KUBERNETES).Funcs(template.FuncMap{
+	"quote":   quote,
+	"literal": yamlBlock,
+}).Parse(`# Generated by 'cc deploy generate'
+{{- if .Env }}
+#
+# The config references environment variables, which are read from the '{{ .Secret }}'
+# secret (e.g. kubectl create secret generic {{ .Secret }} --from-env-file=cc.env)
+{{- end }}
+{{- if .Files }}
+#
+# The config reads secret files, which are not part of these manifests - mount them
+# into the containers at these paths:
+{{- range .Files }}
+#   {{ .Path }}
+{{- end }}
+{{- end }}
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .ConfigMap }}
+  {{- template "namespace" . }}
+  labels:
+    app.kubernetes.io/part-of: chain-connectors
+data:
+  {{ quote .ConfigName }}: {{ literal .ConfigData "    " }}
+{{- range .Chains }}
+---
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: {{ .Resource }}
+  {{- template "namespace" $ }}
+  labels:
+    {{- template "labels" . }}
+spec:
+  replicas: 1
+  selector:
+    matchLabels:
+      app.kubernetes.io/name: {{ .Resource }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: {{ .Resource }}
+        app.kubernetes.io/part-of: chain-connectors
+      annotations:
+        chain-connectors/config-checksum: {{ quote $.Checksum }}
+    spec:
+      containers:
+        - name: cc
+          image: {{ quote $.Image }}
+          args: ["plugins", "run", "from-config", "--config", {{ quote $.ConfigFile }}, "--name", {{ quote .Name }}]
+          env:
+            - name: CC_HOME
+              value: {{ quote $.DataDir }}
+            {{- range $.Env }}
+            - name: {{ . }}
+              valueFrom:
+                secretKeyRef:
+                  name: {{ $.Secret }}
+                  key: {{ . }}
+            {{- end }}
+          ports:
+            - name: grpc
+              containerPort: {{ .Port }}
+          startupProbe:
+            tcpSocket:
+              port: grpc
+            periodSeconds: 5
+            failureThreshold: 60
+          readinessProbe:
+            tcpSocket:
+              port: grpc
+            periodSeconds: 10
+          livenessProbe:
+            tcpSocket:
+              port: grpc
+            periodSeconds: 20
+            failureThreshold: 3
+          volumeMounts:
+            - name: config
+              mountPath: {{ quote $.ConfigDir }}
+              readOnly: true
+            - name: data
+              mountPath: {{ quote $.DataDir }}
+      terminationGracePeriodSeconds: 30
+      volumes:
+        - name: config
+          configMap:
+            name: {{ $.ConfigMap }}
+        - name: data
+          emptyDir: {}
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: {{ .Resource }}
+  {{- template "namespace" $ }}
+  labels:
+    {{- template "labels" . }}
+spec:
+  selector:
+    app.kubernetes.io/name: {{ .Resource }}
+  ports:
+    - name: grpc
+      port: {{ .Port }}
+      targetPort: grpc
+      appProtocol: grpc
+{{- end }}
+{{- define "namespace" }}
+{{- if .Namespace }}
+  namespace: {{ .Namespace }}
+{{- end }}
+{{- end }}
+{{- define "labels" }}
+    app.kubernetes.io/name: {{ .Resource }}
+    app.kubernetes.io/part-of: chain-connectors
+{{- end }}
+`))
+
+// kubernetes creates a Deployment and a Service for every chain, along with a
+// ConfigMap that holds the config file. Every chain runs in its own pod, so the ports
+// of different chains never conflict.
+func kubernetes(d *data) (*Result, error) {
+	if d.Namespace != "" && (len(d.Namespace) > MAX_RESOURCE_NAME_LENGTH || !namespaceRegex.MatchString(d.Namespace)) {
+		return nil, fmt.Errorf("namespace '%s' must be a lowercase DNS label (e.g. chain-connectors)", d.Namespace)
+	}
+
+	content, err := render(kubernetesTemplate, map[string]any{
+		"Chains":     d.Chains,
+		"Env":        d.Env,
+		"Files":      d.Files,
+		"Image":      d.Image,
+		"Namespace":  d.Namespace,
+		"Secret":     d.Secret,
+		"ConfigMap":  KUBERNETES_CONFIG_MAP,
+		"ConfigName": d.ConfigName,
+		"ConfigData": d.ConfigData,
+		"ConfigFile": d.ConfigFile,
+		"ConfigDir":  CONFIG_DIR,
+		"DataDir":    DATA_DIR,
+		"Checksum":   d.Checksum,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	warnings := containerWarnings(d)
+	for _, file := range d.Files {
+		warnings = append(warnings, fmt.Sprintf(
+			"the config reads the secret file '%s', which is not part of the manifests - mount it at '%s' (e.g. from a secret)",
+			file.Ref,
+			file.Path,
+		))
+	}
+
+	return &Result{
+		Files:    []File{{Path: KUBERNETES_FILE_NAME, Content: content}},
+		Warnings: warnings,
+	}, nil
+}
+
+// yamlBlock encodes a multi-line string as a YAML literal block whose lines are
+// indented by the given indent
+func yamlBlock(s string, indent string) string {
+	// NOTE: a literal block cannot start with spaces unless it has an indentation
+	// indicator, and it cannot keep carriage returns or more than one trailing newline
+	// without a chomping indicator
+	if s == "" || strings.HasPrefix(s, " ") || strings.HasSuffix(s, "\n\n") || strings.Contains(s, "\r") {
+		return quote(s)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	header := "|"
+	if !strings.HasSuffix(s, "\n") {
+		header = "|-"
+	}
+
+	var out strings.Builder
+	out.WriteString(header)
+	for _, line := range lines {
+		out.WriteString("\n")
+		if line != "" {
+			out.WriteString(indent + line)
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,326 @@
+package deploy
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+)
+
+const (
+	TARGET_SYSTEMD    = "systemd"
+	TARGET_COMPOSE    = "compose"
+	TARGET_KUBERNETES = "kubernetes"
+
+	// DEFAULT_IMAGE_REPOSITORY is the image that containers run (tagged with the CLI
+	// version unless another image is given)
+	DEFAULT_IMAGE_REPOSITORY = "docker.io/caffeineaddict333/chain-connectors-prototype"
+	DEFAULT_BINARY           = "/usr/local/bin/cc"
+	DEFAULT_SECRET           = "cc-env"
+
+	// CONFIG_DIR is where the config file is installed (or mounted) and DATA_DIR is
+	// the CLI home directory inside of containers (the plugins are installed there)
+	CONFIG_DIR = "/etc/chain-connectors"
+	DATA_DIR   = "/var/lib/chain-connectors"
+
+	// RESOURCE_PREFIX is prepended to the names of the units, services and Kubernetes
+	// resources that are generated for each chain
+	RESOURCE_PREFIX = "cc-"
+
+	// NOTE: Kubernetes resource names must be DNS labels
+	MAX_RESOURCE_NAME_LENGTH = 63
+)
+
+var (
+	invalidNameCharsRegex = regexp.MustCompile(`[^a-z0-9]+`)
+	generators            = map[string]generator{
+		TARGET_SYSTEMD:    systemd,
+		TARGET_COMPOSE:    compose,
+		TARGET_KUBERNETES: kubernetes,
+	}
+)
+
+type (
+	// Source is a config file that deployment artifacts are generated for.
+	Source struct {
+		Path   string
+		Data   []byte
+		Config *config.CliConfig
+
+		// Env lists the environment variables that the config references. Their
+		// values are never written to the artifacts.
+		Env []string
+
+		// Files lists the paths of the secret files that the config references (as
+		// they are written in the config). Their contents are never written to the
+		// artifacts.
+		Files []string
+	}
+
+	Options struct {
+		// Chains are the names of the chains to deploy (defaults to all chains)
+		Chains []string
+
+		// Image is the container image for compose files and Kubernetes manifests
+		Image string
+
+		// Binary is the path of the CLI binary that systemd units run
+		Binary string
+
+		// ConfigSource is the path that compose files mount the config file from,
+		// relative to the compose file (defaults to the config file's base name)
+		ConfigSource string
+
+		// Namespace is the namespace of the Kubernetes resources (optional), and
+		// Secret names the Kubernetes secret that holds the environment variables
+		Namespace string
+		Secret    string
+	}
+
+	// File is a generated artifact. Its path is relative to the output directory.
+	File struct {
+		Path    string
+		Content string
+	}
+
+	Result struct {
+		Files    []File
+		Warnings []string
+	}
+
+	// secretFile is a file reference of the config. Relative references are resolved
+	// against the directory of the config file, which is CONFIG_DIR once the config
+	// is deployed.
+	secretFile struct {
+		Ref  string
+		Path string
+	}
+
+	// chain holds everything that the templates need to know about a chain
+	chain struct {
+		Name     string
+		Resource string
+		Plugin   string
+		Port     int64
+	}
+
+	// data is passed to every template
+	data struct {
+		Options
+		Chains     []chain
+		Env        []string
+		Files      []secretFile
+		ConfigName string
+		ConfigFile string
+		ConfigData string
+		Checksum   string
+		conf       *config.CliConfig
+	}
+
+	generator func(d *data) (*Result, error)
+)
+
+// Targets returns the names of the supported deployment targets.
+func Targets() []string {
+	return slices.Sorted(maps.Keys(generators))
+}
+
+// ReadSource reads a config file and records the environment variables and secret
+// files that it references. The chains' ports and plugins are all that is needed
+// from the config, so other problems (e.g. environment variables or files that only
+// exist on the target hosts) are ignored unless the config cannot be decoded at all.
+func ReadSource(filePath string) (*Source, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]bool{}
+	files := map[string]bool{}
+	dir := filepath.Dir(filePath)
+	conf, problems, err := config.CheckCliConfig(bytes.NewReader(data), config.DecodeOptions{
+		Format: config.FormatFromPath(filePath),
+		Dir:    dir,
+		LookupEnv: func(name string) (string, bool) {
+			env[name] = true
+			return os.LookupEnv(name)
+		},
+		ReadFile: func(name string) ([]byte, error) {
+			files[name] = true
+			return os.ReadFile(config.ResolveFile(dir, name))
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if conf == nil {
+		return nil, problems
+	}
+
+	return &Source{
+		Path:   filePath,
+		Data:   data,
+		Config: conf,
+		Env:    slices.Sorted(maps.Keys(env)),
+		Files:  slices.Sorted(maps.Keys(files)),
+	}, nil
+}
+
+// Generate creates the artifacts that run the selected chains of a config file on a
+// deployment target (one of Targets()). Each chain runs as its own 'plugins run
+// from-config' process.
+func Generate(target string, src *Source, opts Options) (*Result, error) {
+	generate, exists := generators[target]
+	if !exists {
+		return nil, &TargetNotFoundError{Name: target, Choices: Targets()}
+	}
+
+	if len(opts.Chains) == 0 {
+		opts.Chains = src.Config.ChainNames()
+	}
+	if opts.Image == "" {
+		opts.Image = DEFAULT_IMAGE_REPOSITORY + ":" + core.VersionWithoutPrefix()
+	}
+	if opts.Binary == "" {
+		opts.Binary = DEFAULT_BINARY
+	}
+	if opts.Secret == "" {
+		opts.Secret = DEFAULT_SECRET
+	}
+
+	configName := filepath.Base(src.Path)
+	if opts.ConfigSource == "" {
+		opts.ConfigSource = configName
+	}
+
+	chains := []chain{}
+	resources := map[string]string{}
+	for _, name := range opts.Chains {
+		chainConfig, err := src.Config.Chain(name)
+		if err != nil {
+			return nil, err
+		}
+		if chainConfig.Plugin == nil || chainConfig.Plugin.ID == "" {
+			return nil, fmt.Errorf("chain '%s' does not have a plugin", name)
+		}
+		if chainConfig.Server == nil || chainConfig.Server.Port == 0 {
+			return nil, fmt.Errorf("chain '%s' must have a fixed server port to be deployed", name)
+		}
+
+		resource, err := resourceName(name)
+		if err != nil {
+			return nil, err
+		}
+		if other, exists := resources[resource]; exists {
+			return nil, fmt.Errorf("chains '%s' and '%s' would both be deployed as '%s' - rename one of them", other, name, resource)
+		} else {
+			resources[resource] = name
+		}
+
+		chains = append(chains, chain{
+			Name:     name,
+			Resource: resource,
+			Plugin:   chainConfig.Plugin.ID,
+			Port:     chainConfig.Server.Port,
+		})
+	}
+
+	files := []secretFile{}
+	for _, ref := range src.Files {
+		if path.IsAbs(filepath.ToSlash(ref)) {
+			files = append(files, secretFile{Ref: ref, Path: filepath.ToSlash(ref)})
+		} else {
+			files = append(files, secretFile{Ref: ref, Path: path.Join(CONFIG_DIR, filepath.ToSlash(ref))})
+		}
+	}
+
+	checksum := sha256.Sum256(src.Data)
+	return generate(&data{
+		Options:    opts,
+		Chains:     chains,
+		Env:        src.Env,
+		Files:      files,
+		ConfigName: configName,
+		ConfigFile: path.Join(CONFIG_DIR, configName),
+		ConfigData: string(src.Data),
+		Checksum:   hex.EncodeToString(checksum[:]),
+		conf:       src.Config,
+	})
+}
+
+// Render joins the files into a single document, with a comment line before each
+// file that names it.
+func Render(files []File) string {
+	var out strings.Builder
+	for i, file := range files {
+		if i != 0 {
+			out.WriteString("\n")
+		}
+		fmt.Fprintf(&out, "# %s\n", file.Path)
+		out.WriteString(file.Content)
+	}
+	return out.String()
+}
+
+// containerWarnings reports the chains whose servers cannot be reached from outside
+// of their containers
+func containerWarnings(d *data) []string {
+	warnings := []string{}
+	for _, c := range d.Chains {
+		if server := d.conf.Chains[c.Name].Server; !server.ListensOnAllInterfaces() {
+			warnings = append(warnings, fmt.Sprintf(
+				"chain '%s' listens on '%s', so it cannot be reached from outside of its container - set its server host to '0.0.0.0'",
+				c.Name,
+				server.Host,
+			))
+		}
+	}
+	return warnings
+}
+
+// resourceName turns a chain name into a name that is valid for systemd units,
+// compose services and Kubernetes resources
+func resourceName(chainName string) (string, error) {
+	name := strings.Trim(invalidNameCharsRegex.ReplaceAllString(strings.ToLower(chainName), "-"), "-")
+	if len(name) > MAX_RESOURCE_NAME_LENGTH-len(RESOURCE_PREFIX) {
+		name = strings.TrimRight(name[:MAX_RESOURCE_NAME_LENGTH-len(RESOURCE_PREFIX)], "-")
+	}
+
+	if name == "" {
+		return "", fmt.Errorf("chain '%s' must have at least one letter or digit in its name to be deployed", chainName)
+	} else {
+		return RESOURCE_PREFIX + name, nil
+	}
+}
+
+func render(tmpl *template.Template, d any) (string, error) {
+	var out strings.Builder
+	if err := tmpl.Execute(&out, d); err != nil {
+		return "", err
+	} else {
+		return out.String(), nil
+	}
+}
+
+// quote encodes a string as a double-quoted YAML scalar
+func quote(s string) string {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+
+	// NOTE: encoding a string never fails
+	enc.Encode(s)
+	return strings.TrimSuffix(out.String(), "\n")
+}
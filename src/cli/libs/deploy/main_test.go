@@ -0,0 +1,171 @@
+package deploy
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NOTE: run 'go test ./src/cli/libs/deploy -update' to rewrite the golden files after
+// changing a template
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+func TestGenerate(t *testing.T) {
+	src, err := ReadSource(filepath.Join("testdata", "config.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(src.Env, []string{"INFURA_KEY"}) {
+		t.Fatalf("unexpected environment variables: %v", src.Env)
+	}
+
+	opts := Options{
+		Image:     "docker.io/caffeineaddict333/chain-connectors-prototype:1.1.0",
+		Namespace: "chain-connectors",
+	}
+
+	for _, target := range Targets() {
+		t.Run(target, func(t *testing.T) {
+			result, err := Generate(target, src, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			checkGolden(t, filepath.Join("testdata", target+".golden"), Render(result.Files))
+
+			if target != TARGET_SYSTEMD {
+				for _, file := range result.Files {
+					dec := yaml.NewDecoder(strings.NewReader(file.Content))
+					for {
+						var doc any
+						if err := dec.Decode(&doc); errors.Is(err, io.EOF) {
+							break
+						} else if err != nil {
+							t.Fatalf("%s is not valid YAML: %v", file.Path, err)
+						}
+					}
+				}
+			}
+
+			// NOTE: only the chain that listens on localhost is unreachable in a container
+			if target != TARGET_SYSTEMD && len(result.Warnings) != 1 {
+				t.Fatalf("expected a warning for chain 'flow' but got: %v", result.Warnings)
+			}
+		})
+	}
+
+	if _, err := Generate("nomad", src, opts); !errors.Is(err, &TargetNotFoundError{}) {
+		t.Fatalf("expected a TargetNotFoundError but got: %v", err)
+	}
+	if _, err := Generate(TARGET_SYSTEMD, src, Options{Chains: []string{"solana"}}); err == nil {
+		t.Fatal("expected an error for a chain that is not in the config")
+	}
+	if _, err := Generate(TARGET_KUBERNETES, src, Options{Namespace: "Chain_Connectors"}); err == nil {
+		t.Fatal("expected an error for an invalid namespace")
+	}
+}
+
+func TestGenerateSecretFiles(t *testing.T) {
+	src, err := ReadSource(filepath.Join("testdata", "secrets.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: files that only exist on the target hosts are recorded as well
+	if !slices.Equal(src.Files, []string{"/run/secrets/flow-rpc", "secrets/eth-wss"}) {
+		t.Fatalf("unexpected secret files: %v", src.Files)
+	}
+
+	opts := Options{Image: "docker.io/caffeineaddict333/chain-connectors-prototype:1.1.0"}
+	for _, target := range Targets() {
+		t.Run(target, func(t *testing.T) {
+			result, err := Generate(target, src, opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			actual := Render(result.Files)
+			checkGolden(t, filepath.Join("testdata", target+"-secrets.golden"), actual)
+			if strings.Contains(actual, "wss://mainnet.infura.io") {
+				t.Fatal("expected the contents of the secret files to be left out")
+			}
+
+			// NOTE: compose mounts the files, while the other targets cannot
+			if target == TARGET_COMPOSE && len(result.Warnings) != 0 {
+				t.Fatalf("expected no warnings but got: %v", result.Warnings)
+			}
+			if target != TARGET_COMPOSE && len(result.Warnings) != 2 {
+				t.Fatalf("expected a warning for each secret file but got: %v", result.Warnings)
+			}
+		})
+	}
+}
+
+func TestResourceName(t *testing.T) {
+	testCases := []struct {
+		chainName string
+		expected  string
+	}{
+		{chainName: "flow", expected: "cc-flow"},
+		{chainName: "eth_mainnet", expected: "cc-eth-mainnet"},
+		{chainName: "Polkadot (Westend)", expected: "cc-polkadot-westend"},
+		{chainName: "--a--", expected: "cc-a"},
+	}
+
+	for _, testCase := range testCases {
+		name, err := resourceName(testCase.chainName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if name != testCase.expected {
+			t.Fatalf("expected '%s' for chain '%s' but got '%s'", testCase.expected, testCase.chainName, name)
+		}
+	}
+
+	if _, err := resourceName("__"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSystemdArg(t *testing.T) {
+	testCases := map[string]string{
+		"flow":           "flow",
+		"/etc/cc/c.json": "/etc/cc/c.json",
+		"my chain":       `"my chain"`,
+		"100%":           "100%%",
+		"$HOME":          "$$HOME",
+		`say "hi"`:       `"say \"hi\""`,
+		"":               `""`,
+	}
+
+	for arg, expected := range testCases {
+		if actual := systemdArg(arg); actual != expected {
+			t.Fatalf("expected %s for '%s' but got %s", expected, arg, actual)
+		}
+	}
+}
+
+// checkGolden compares generated output with a golden file (or rewrites the golden
+// file when -update is given)
+func checkGolden(t *testing.T, goldenFile string, actual string) {
+	if *update {
+		if err := os.WriteFile(goldenFile, []byte(actual), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if actual != string(expected) {
+		t.Fatalf("output does not match %s (rerun with -update if the change is intended):\n%s", goldenFile, actual)
+	}
+}
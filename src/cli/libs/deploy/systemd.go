@@ -0,0 +1,110 @@
+package deploy
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+const (
+	// SYSTEMD_ENV_FILE holds the environment variables that the config references
+	SYSTEMD_ENV_FILE = CONFIG_DIR + "/cc.env"
+)
+
+// NOTE: each unit gets its own state directory (and plugin store) since units with
+// dynamic users cannot share one
+var systemdTemplate = template.Must(template.New(TARGET_SYSTEMD).Funcs(template.FuncMap{
+	"arg":  systemdArg,
+	"text": systemdText,
+	"join": strings.Join,
+}).Parse(`# Generated by 'cc deploy generate' for chain '{{ text .Chain.Name }}'
+{{- if .Env }}
+# The config references these environment variables: {{ join .Env ", " }}
+# Set them in {{ .EnvFile }} (e.g. NAME=value) and keep that file readable only by root.
+{{- end }}
+{{- range .Files }}
+# The config reads the secret file {{ text .Path }}, which must exist on this host.
+{{- end }}
+[Unit]
+Description=Chain connector for '{{ text .Chain.Name }}' ({{ text .Chain.Plugin }} plugin)
+Documentation=https://github.com/chris-de-leon/chain-connectors-prototype
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=simple
+Environment=CC_HOME=%S/chain-connectors/{{ .Chain.Resource }}
+EnvironmentFile=-{{ .EnvFile }}
+ExecStart={{ arg .Binary }} plugins run from-config --config {{ arg .ConfigFile }} --name {{ arg .Chain.Name }}
+ExecReload=/bin/kill -HUP $MAINPID
+Restart=on-failure
+RestartSec=5
+TimeoutStopSec=30
+DynamicUser=yes
+StateDirectory=chain-connectors/{{ .Chain.Resource }}
+NoNewPrivileges=yes
+
+[Install]
+WantedBy=multi-user.target
+`))
+
+// systemd creates a unit file for every chain. The config file is expected to be
+// installed in CONFIG_DIR.
+func systemd(d *data) (*Result, error) {
+	if err := d.conf.PortConflicts(chainNames(d.Chains)); err != nil {
+		return nil, err
+	}
+
+	files := []File{}
+	for _, c := range d.Chains {
+		content, err := render(systemdTemplate, map[string]any{
+			"Chain":      c,
+			"Env":        d.Env,
+			"Files":      d.Files,
+			"EnvFile":    SYSTEMD_ENV_FILE,
+			"Binary":     d.Binary,
+			"ConfigFile": d.ConfigFile,
+		})
+		if err != nil {
+			return nil, err
+		} else {
+			files = append(files, File{Path: c.Resource + ".service", Content: content})
+		}
+	}
+
+	warnings := []string{}
+	for _, file := range d.Files {
+		warnings = append(warnings, fmt.Sprintf(
+			"the config reads the secret file '%s', which must exist at '%s' on the target hosts and be readable by the units' dynamic users",
+			file.Ref,
+			file.Path,
+		))
+	}
+
+	return &Result{Files: files, Warnings: warnings}, nil
+}
+
+// systemdText escapes the specifiers (e.g. %n) in a unit file value
+func systemdText(s string) string {
+	return strings.ReplaceAll(s, "%", "%%")
+}
+
+// systemdArg escapes a command line argument so that systemd passes it to the
+// process as is
+func systemdArg(s string) string {
+	s = strings.ReplaceAll(systemdText(s), "$", "$$")
+	if s != "" && !strings.ContainsAny(s, " \t\n\"'\\;") {
+		return s
+	} else {
+		return strconv.Quote(s)
+	}
+}
+
+func chainNames(chains []chain) []string {
+	names := []string{}
+	for _, c := range chains {
+		names = append(names, c.Name)
+	}
+	return names
+}
@@ -1,6 +1,7 @@
 package dirs
 
 import (
+	"fmt"
 	"os"
 	"path/filepath"
 
@@ -10,8 +11,38 @@ import (
 const (
 	APPLICATION_DIR = "chain-connectors-prototype"
 	PLUGINS_DIR     = "plugins"
+
+	// CONFIG_DIR and CACHE_DIR are the directories that the config and cache data are
+	// kept in under a home directory
+	CONFIG_DIR = "config"
+	CACHE_DIR  = "cache"
+
+	// PORTABLE_DIR is the home directory (next to the CLI binary) that is used in
+	// portable mode. If it exists, then portable mode is turned on automatically.
+	PORTABLE_DIR = "cc-home"
 )
 
+// Options overrides where the CLI keeps its data. A more specific option takes
+// precedence over a less specific one (i.e. ConfigDir, CacheDir and PluginsDir take
+// precedence over Home, which takes precedence over Portable), and any directory that
+// is not overridden falls back to the user's config or cache directory.
+type Options struct {
+	// Home holds everything in its config and cache subdirectories
+	Home string
+
+	// Portable uses PORTABLE_DIR next to the CLI binary as the home directory
+	Portable bool
+
+	// ConfigDir and CacheDir replace the application directory under the user's
+	// config and cache directories, so they still hold a directory per CLI version
+	// and a shared plugins directory
+	ConfigDir string
+	CacheDir  string
+
+	// PluginsDir holds the installed plugins
+	PluginsDir string
+}
+
 var (
 	PluginsConfig string
 	PluginsCache  string
@@ -19,67 +50,105 @@ var (
 	Cache         string
 )
 
+// NOTE: the directories are resolved without any overrides when the package is
+// loaded so that they are usable before the CLI flags are parsed. If the user's
+// directories cannot be determined, then they are left empty until Init succeeds.
 func init() {
-	var dir string
-	var err error
+	_ = Init(Options{})
+}
 
-	if dir, err = getConfigDir(); err != nil {
-		panic(err)
-	} else {
-		Config = dir
+// Init resolves the CLI directories with the given overrides.
+func Init(opts Options) error {
+	configRoot, cacheRoot, err := roots(opts)
+	if err != nil {
+		return err
 	}
 
-	if dir, err = getCacheDir(); err != nil {
-		panic(err)
-	} else {
-		Cache = dir
+	Config = filepath.Join(configRoot, core.VersionWithPrefix())
+	Cache = filepath.Join(cacheRoot, core.VersionWithPrefix())
+
+	// NOTE: plugins are versioned independently of the CLI, so the plugin directories
+	// are shared by all CLI versions and are not nested under the CLI version
+	PluginsConfig = filepath.Join(configRoot, PLUGINS_DIR)
+	PluginsCache = filepath.Join(cacheRoot, PLUGINS_DIR)
+	if opts.PluginsDir != "" {
+		if PluginsConfig, err = filepath.Abs(opts.PluginsDir); err != nil {
+			return err
+		}
 	}
 
-	if dir, err = getPluginsConfigDir(); err != nil {
-		panic(err)
-	} else {
-		PluginsConfig = dir
+	return nil
+}
+
+// ConfigRoot returns the directory that holds the config directories of every CLI
+// version (along with the shared plugins directory unless it was overridden).
+func ConfigRoot() string {
+	return filepath.Dir(Config)
+}
+
+// roots returns the directories that hold the config and cache data of every CLI
+// version
+func roots(opts Options) (string, string, error) {
+	home := opts.Home
+	if home == "" && opts.Portable {
+		dir, err := PortableHome()
+		if err != nil {
+			return "", "", err
+		} else {
+			home = dir
+		}
+	}
+	if home == "" && opts.ConfigDir == "" && opts.CacheDir == "" {
+		if dir, err := PortableHome(); err == nil && isDir(dir) {
+			home = dir
+		}
 	}
 
-	if dir, err = getPluginsCacheDir(); err != nil {
-		panic(err)
-	} else {
-		PluginsCache = dir
+	configRoot, err := root(opts.ConfigDir, home, CONFIG_DIR, os.UserConfigDir)
+	if err != nil {
+		return "", "", err
 	}
-}
 
-func getConfigDir() (string, error) {
-	dir, err := os.UserConfigDir()
+	cacheRoot, err := root(opts.CacheDir, home, CACHE_DIR, os.UserCacheDir)
 	if err != nil {
-		return "", err
+		return "", "", err
 	} else {
-		return filepath.Join(dir, APPLICATION_DIR, core.VersionWithPrefix()), nil
+		return configRoot, cacheRoot, nil
 	}
 }
 
-func getCacheDir() (string, error) {
-	dir, err := os.UserCacheDir()
+func root(dir string, home string, homeDir string, userDir func() (string, error)) (string, error) {
+	if dir != "" {
+		return filepath.Abs(dir)
+	}
+	if home != "" {
+		return filepath.Abs(filepath.Join(home, homeDir))
+	}
+
+	dir, err := userDir()
 	if err != nil {
-		return "", err
+		return "", fmt.Errorf("could not determine where to keep CLI data (%w) - use --home or CC_HOME", err)
 	} else {
-		return filepath.Join(dir, APPLICATION_DIR, core.VersionWithPrefix()), nil
+		return filepath.Join(dir, APPLICATION_DIR), nil
 	}
 }
 
-func getPluginsConfigDir() (string, error) {
-	dir, err := getConfigDir()
+// PortableHome returns the home directory that is used in portable mode.
+func PortableHome() (string, error) {
+	exe, err := os.Executable()
 	if err != nil {
 		return "", err
-	} else {
-		return filepath.Join(dir, PLUGINS_DIR), nil
 	}
-}
 
-func getPluginsCacheDir() (string, error) {
-	dir, err := getCacheDir()
+	exe, err = filepath.EvalSymlinks(exe)
 	if err != nil {
 		return "", err
 	} else {
-		return filepath.Join(dir, PLUGINS_DIR), nil
+		return filepath.Join(filepath.Dir(exe), PORTABLE_DIR), nil
 	}
 }
+
+func isDir(dir string) bool {
+	info, err := os.Stat(dir)
+	return err == nil && info.IsDir()
+}
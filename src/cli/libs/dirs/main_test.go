@@ -0,0 +1,65 @@
+package dirs
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+)
+
+func TestInit(t *testing.T) {
+	t.Cleanup(func() { Init(Options{}) })
+
+	home := t.TempDir()
+	version := core.VersionWithPrefix()
+	testCases := []struct {
+		name     string
+		opts     Options
+		expected []string
+	}{
+		{
+			name: "home",
+			opts: Options{Home: home},
+			expected: []string{
+				filepath.Join(home, CONFIG_DIR, version),
+				filepath.Join(home, CACHE_DIR, version),
+				filepath.Join(home, CONFIG_DIR, PLUGINS_DIR),
+				filepath.Join(home, CACHE_DIR, PLUGINS_DIR),
+			},
+		},
+		{
+			name: "overrides",
+			opts: Options{Home: home, CacheDir: filepath.Join(home, "c"), PluginsDir: filepath.Join(home, "p")},
+			expected: []string{
+				filepath.Join(home, CONFIG_DIR, version),
+				filepath.Join(home, "c", version),
+				filepath.Join(home, "p"),
+				filepath.Join(home, "c", PLUGINS_DIR),
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		if err := Init(testCase.opts); err != nil {
+			t.Fatal(err)
+		}
+
+		actual := []string{Config, Cache, PluginsConfig, PluginsCache}
+		for i := range actual {
+			if actual[i] != testCase.expected[i] {
+				t.Fatalf("%s: expected %v but got %v", testCase.name, testCase.expected, actual)
+			}
+		}
+	}
+
+	// NOTE: the user's directories are not needed if everything is overridden
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("XDG_CACHE_HOME", "")
+	if err := Init(Options{}); err == nil {
+		t.Fatal("expected an error")
+	}
+	if err := Init(Options{Home: home}); err != nil {
+		t.Fatal(err)
+	}
+}
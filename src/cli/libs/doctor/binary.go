@@ -0,0 +1,102 @@
+package doctor
+
+import (
+	"bytes"
+	"debug/elf"
+	"debug/macho"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+)
+
+var (
+	elfMachines = map[string]elf.Machine{
+		"386":   elf.EM_386,
+		"amd64": elf.EM_X86_64,
+		"arm":   elf.EM_ARM,
+		"arm64": elf.EM_AARCH64,
+	}
+
+	machoCpus = map[string]macho.Cpu{
+		"amd64": macho.CpuAmd64,
+		"arm64": macho.CpuArm64,
+	}
+)
+
+// CheckBinary checks that the file at the given path can be executed on the current
+// platform (i.e. that it is executable and was built for the current GOOS/GOARCH).
+// Scripts are accepted as long as they are executable.
+func CheckBinary(path string) error {
+	stat, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	if stat.Mode()&0111 == 0 {
+		return fmt.Errorf("'%s' is not executable", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	} else {
+		defer file.Close()
+	}
+
+	prefix := make([]byte, 2)
+	if _, err := io.ReadFull(file, prefix); err != nil {
+		return fmt.Errorf("'%s' is not a valid executable: %w", path, err)
+	}
+	if bytes.Equal(prefix, []byte("#!")) {
+		return nil
+	}
+
+	switch runtime.GOOS {
+	case "linux":
+		return checkElf(file)
+	case "darwin":
+		return checkMacho(file)
+	default:
+		// NOTE: the executable format is only checked on platforms that plugins are
+		// released for
+		return nil
+	}
+}
+
+func checkElf(file *os.File) error {
+	bin, err := elf.NewFile(file)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid executable for %s/%s: %w", file.Name(), runtime.GOOS, runtime.GOARCH, err)
+	}
+
+	machine, ok := elfMachines[runtime.GOARCH]
+	if ok && bin.Machine != machine {
+		return fmt.Errorf("'%s' was built for %s but the current architecture is %s", file.Name(), bin.Machine, runtime.GOARCH)
+	} else {
+		return nil
+	}
+}
+
+func checkMacho(file *os.File) error {
+	// NOTE: universal binaries contain an executable for each architecture
+	if fat, err := macho.NewFatFile(file); err == nil {
+		for _, arch := range fat.Arches {
+			if cpu, ok := machoCpus[runtime.GOARCH]; !ok || arch.Cpu == cpu {
+				return nil
+			}
+		}
+		return fmt.Errorf("'%s' does not contain an executable for %s", file.Name(), runtime.GOARCH)
+	}
+
+	bin, err := macho.NewFile(file)
+	if err != nil {
+		return fmt.Errorf("'%s' is not a valid executable for %s/%s: %w", file.Name(), runtime.GOOS, runtime.GOARCH, err)
+	}
+
+	cpu, ok := machoCpus[runtime.GOARCH]
+	if ok && bin.Cpu != cpu {
+		return fmt.Errorf("'%s' was built for %s but the current architecture is %s", file.Name(), bin.Cpu, runtime.GOARCH)
+	} else {
+		return nil
+	}
+}
@@ -0,0 +1,114 @@
+package doctor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+)
+
+var defaultPorts = map[string]string{
+	"ws":    "80",
+	"http":  "80",
+	"wss":   "443",
+	"https": "443",
+}
+
+// Endpoint is a URL from a chain config along with the field that it was read from.
+type Endpoint struct {
+	Field string
+	Url   string
+}
+
+// Endpoints returns every URL in the chain config that the plugin will connect to.
+func Endpoints(conf *config.ChainConfig) []Endpoint {
+	endpoints := []Endpoint{}
+	if conf.Conn != nil {
+		if conf.Conn.Wss != "" {
+			endpoints = append(endpoints, Endpoint{Field: handshake.FIELD_CONN_WSS, Url: conf.Conn.Wss})
+		}
+		if conf.Conn.Rpc != "" {
+			endpoints = append(endpoints, Endpoint{Field: handshake.FIELD_CONN_RPC, Url: conf.Conn.Rpc})
+		}
+		for _, spork := range conf.Conn.Sporks {
+			endpoints = append(endpoints, Endpoint{Field: fmt.Sprintf("%s[%s]", handshake.FIELD_CONN_SPORKS, spork.Name), Url: spork.Url})
+		}
+	}
+	if conf.Parachain != nil && conf.Parachain.Relay != "" {
+		endpoints = append(endpoints, Endpoint{Field: handshake.FIELD_PARACHAIN_RELAY, Url: conf.Parachain.Relay})
+	}
+	return endpoints
+}
+
+// CheckEndpoint checks that a connection can be opened to an endpoint. Depending on
+// the plugin, an endpoint is either a URL (e.g. wss://host/path), a host and port
+// (e.g. a Flow access node), or the path of a unix socket (e.g. a local IPC endpoint).
+// For TLS endpoints, the TLS handshake is also checked.
+func CheckEndpoint(ctx context.Context, endpoint string, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	network, address, useTLS, err := parseEndpoint(endpoint)
+	if err != nil {
+		return err
+	}
+
+	dialer := new(net.Dialer)
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return err
+	}
+
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: host})
+		err = tlsConn.HandshakeContext(ctx)
+		return errors.Join(err, tlsConn.Close())
+	} else {
+		return conn.Close()
+	}
+}
+
+func parseEndpoint(endpoint string) (network string, address string, useTLS bool, err error) {
+	if strings.HasPrefix(endpoint, "/") || strings.HasPrefix(endpoint, "./") {
+		return "unix", endpoint, false, nil
+	}
+	if socketPath, isSocket := strings.CutPrefix(endpoint, config.IPC_SCHEME_PREFIX); isSocket {
+		if socketPath == "" {
+			return "", "", false, fmt.Errorf("invalid endpoint '%s': missing socket path", endpoint)
+		} else {
+			return "unix", socketPath, false, nil
+		}
+	}
+
+	if !strings.Contains(endpoint, "://") {
+		if _, _, err := net.SplitHostPort(endpoint); err != nil {
+			return "", "", false, fmt.Errorf("invalid endpoint '%s': %w", endpoint, err)
+		} else {
+			return "tcp", endpoint, false, nil
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", "", false, err
+	}
+
+	scheme := strings.ToLower(u.Scheme)
+	port, ok := defaultPorts[scheme]
+	if !ok {
+		return "", "", false, fmt.Errorf("invalid endpoint '%s': unsupported scheme '%s'", endpoint, u.Scheme)
+	}
+	if u.Port() != "" {
+		port = u.Port()
+	}
+
+	return "tcp", net.JoinHostPort(u.Hostname(), port), scheme == "wss" || scheme == "https", nil
+}
@@ -0,0 +1,178 @@
+package doctor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+)
+
+const (
+	STATUS_OK   = "ok"
+	STATUS_WARN = "warn"
+	STATUS_FAIL = "fail"
+	STATUS_SKIP = "skip"
+)
+
+const DEFAULT_TIMEOUT = time.Second * 30
+
+type (
+	Check struct {
+		Name   string `json:"name"`
+		Status string `json:"status"`
+		Detail string `json:"detail,omitempty"`
+	}
+
+	// Report holds the results of all checks that were run for a chain (or, if no
+	// chain config was given, for a plugin on its own).
+	Report struct {
+		Chain  string  `json:"chain,omitempty"`
+		Plugin string  `json:"plugin"`
+		Ok     bool    `json:"ok"`
+		Checks []Check `json:"checks"`
+	}
+)
+
+func (r *Report) add(name string, status string, detail string) {
+	r.Checks = append(r.Checks, Check{Name: name, Status: status, Detail: detail})
+	if status == STATUS_FAIL {
+		r.Ok = false
+	}
+}
+
+// check records a failure if err is not nil and a success with the given detail
+// otherwise. The return value reports whether the check passed.
+func (r *Report) check(name string, err error, detail string) bool {
+	if err != nil {
+		r.add(name, STATUS_FAIL, err.Error())
+		return false
+	} else {
+		r.add(name, STATUS_OK, detail)
+		return true
+	}
+}
+
+// DiagnosePlugin checks that a plugin is installed correctly (i.e. that its binary
+// exists, can run on this platform, and matches the checksum recorded at install
+// time) and that its manifest can be read. The plugin is not started since that
+// requires a chain config.
+func DiagnosePlugin(store *plgn.PluginStore, ref plgn.Ref) *Report {
+	report := &Report{Plugin: ref.String(), Ok: true, Checks: []Check{}}
+	if diagnoseInstall(report, store, ref) {
+		diagnoseManifest(report, store, ref, nil)
+	}
+	return report
+}
+
+// Diagnose runs every check for a chain: the plugin checks, the validation of the
+// chain config against the plugin's manifest, a connectivity check for each
+// endpoint in the chain config, and finally a handshake with the plugin.
+func Diagnose(ctx context.Context, store *plgn.PluginStore, chainName string, conf *config.ChainConfig, timeout time.Duration) *Report {
+	report := &Report{Chain: chainName, Ok: true, Checks: []Check{}}
+
+	ref, err := plgn.RefFromConfig(conf.Plugin)
+	if err != nil {
+		report.check("config", err, "")
+		return report
+	} else {
+		report.Plugin = ref.String()
+	}
+
+	installed := diagnoseInstall(report, store, ref)
+	if installed {
+		diagnoseManifest(report, store, ref, conf)
+	}
+
+	endpoints := Endpoints(conf)
+	if len(endpoints) == 0 {
+		report.add("endpoints", STATUS_WARN, "the chain config does not contain any endpoints")
+	}
+	for _, endpoint := range endpoints {
+		report.check("endpoint "+endpoint.Field, CheckEndpoint(ctx, endpoint.Url, timeout), endpoint.Url)
+	}
+
+	if installed {
+		diagnoseHandshake(ctx, report, store, conf, timeout)
+	} else {
+		report.add("handshake", STATUS_SKIP, "the plugin binary is not usable")
+	}
+
+	return report
+}
+
+// diagnoseInstall reports whether the plugin binary is usable
+func diagnoseInstall(report *Report, store *plgn.PluginStore, ref plgn.Ref) bool {
+	pluginPath, err := store.GetPath(ref)
+	if !report.check("installed", err, pluginPath) {
+		return false
+	}
+
+	if !report.check("binary", CheckBinary(pluginPath), "") {
+		return false
+	}
+
+	if err := store.Verify(ref); err != nil {
+		report.add("checksum", STATUS_WARN, err.Error())
+	} else {
+		report.add("checksum", STATUS_OK, "")
+	}
+
+	return true
+}
+
+// diagnoseManifest checks that the plugin's manifest can be read and, if a chain
+// config is given, that the chain config satisfies it
+func diagnoseManifest(report *Report, store *plgn.PluginStore, ref plgn.Ref, conf *config.ChainConfig) {
+	manifest, err := store.Manifest(ref)
+	if err != nil {
+		report.check("manifest", err, "")
+	} else if manifest == nil {
+		report.add("manifest", STATUS_WARN, "the plugin did not report a manifest, so chain configs cannot be validated against it")
+	} else if conf == nil {
+		report.add("manifest", STATUS_OK, manifest.String())
+	} else {
+		report.check("manifest", manifest.Validate(conf), manifest.String())
+	}
+}
+
+// diagnoseHandshake starts the plugin on a random local port, waits for it to
+// complete the handshake, and then stops it again. The port is changed so that the
+// check does not conflict with an instance of the chain that is already running.
+func diagnoseHandshake(ctx context.Context, report *Report, store *plgn.PluginStore, conf *config.ChainConfig, timeout time.Duration) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	local := *conf
+	local.Server = &config.ServerConfig{Host: "127.0.0.1", Port: 0}
+
+	stderr := new(bytes.Buffer)
+	proc, err := store.Start(ctx, &local, plgn.ProcessOptions{Stdout: io.Discard, Stderr: stderr})
+	if err != nil {
+		report.check("handshake", fmt.Errorf("%w%s", err, lastLines(stderr.String())), "")
+		return
+	}
+
+	report.check("handshake", nil, proc.String())
+	cancel()
+	_ = proc.Wait()
+}
+
+// lastLines returns the end of the plugin's output, which usually explains why the
+// handshake failed
+func lastLines(output string) string {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) > 5 {
+		lines = lines[len(lines)-5:]
+	}
+
+	if len(lines) == 1 && lines[0] == "" {
+		return ""
+	} else {
+		return ": " + strings.Join(lines, " | ")
+	}
+}
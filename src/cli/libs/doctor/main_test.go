@@ -0,0 +1,136 @@
+package doctor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+const TEST_TIMEOUT = time.Second * 5
+
+func TestCheckBinary(t *testing.T) {
+	dir := t.TempDir()
+
+	script := filepath.Join(dir, "script")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nexit 0\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	notExecutable := filepath.Join(dir, "not-executable")
+	if err := os.WriteFile(notExecutable, []byte("#!/bin/sh\nexit 0\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	garbage := filepath.Join(dir, "garbage")
+	if err := os.WriteFile(garbage, []byte("not a binary"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	testCases := []struct {
+		name  string
+		path  string
+		valid bool
+	}{
+		{name: "test binary", path: os.Args[0], valid: true},
+		{name: "script", path: script, valid: true},
+		{name: "not executable", path: notExecutable, valid: false},
+		{name: "not a binary", path: garbage, valid: false},
+		{name: "missing", path: filepath.Join(dir, "missing"), valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckBinary(tc.path)
+			if tc.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	defer tlsServer.Close()
+
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	closedAddr := closed.Addr().String()
+	closed.Close()
+
+	socketPath := filepath.Join(t.TempDir(), "node.ipc")
+	socket, err := net.Listen("unix", socketPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer socket.Close()
+
+	testCases := []struct {
+		name     string
+		endpoint string
+		valid    bool
+	}{
+		{name: "http", endpoint: server.URL, valid: true},
+		{name: "ws", endpoint: strings.Replace(server.URL, "http://", "ws://", 1), valid: true},
+		{name: "host and port", endpoint: strings.TrimPrefix(server.URL, "http://"), valid: true},
+		{name: "unix socket", endpoint: socketPath, valid: true},
+		{name: "ipc url", endpoint: "ipc://" + socketPath, valid: true},
+		{name: "ipc url without a path", endpoint: "ipc://", valid: false},
+		{name: "closed port", endpoint: "ws://" + closedAddr, valid: false},
+		{name: "untrusted certificate", endpoint: strings.Replace(tlsServer.URL, "https://", "wss://", 1), valid: false},
+		{name: "unsupported scheme", endpoint: "ftp://" + closedAddr, valid: false},
+		{name: "missing port", endpoint: "localhost", valid: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := CheckEndpoint(context.Background(), tc.endpoint, TEST_TIMEOUT)
+			if tc.valid && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.valid && err == nil {
+				t.Fatal("expected an error")
+			}
+		})
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	conf := &config.ChainConfig{
+		Conn: &config.ConnectionConfg{
+			Wss:    "wss://example.com",
+			Sporks: []config.SporkConfig{{Name: "s1", Url: "access.example.com:9000"}},
+		},
+		Parachain: &config.ParachainConfig{ID: 2004, Relay: "wss://relay.example.com"},
+	}
+
+	endpoints := Endpoints(conf)
+	expected := []Endpoint{
+		{Field: "conn.wss", Url: "wss://example.com"},
+		{Field: "conn.sporks[s1]", Url: "access.example.com:9000"},
+		{Field: "parachain.relay", Url: "wss://relay.example.com"},
+	}
+	if len(endpoints) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, endpoints)
+	}
+	for i := range expected {
+		if endpoints[i] != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, endpoints)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package fleet
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+)
+
+// Chain is a chain whose plugin runs under a supervisor. The chain's config can be
+// replaced while the plugin is running.
+type Chain struct {
+	Name     string
+	conf     *config.ChainConfig
+	proc     *plgn.Process
+	mutex    *sync.Mutex
+	starting *sync.Mutex
+	cancel   context.CancelFunc
+	stopped  chan struct{}
+	err      error
+}
+
+func NewChain(name string, conf *config.ChainConfig) *Chain {
+	return &Chain{
+		Name:     name,
+		conf:     conf,
+		mutex:    &sync.Mutex{},
+		starting: &sync.Mutex{},
+		cancel:   func() {},
+		stopped:  make(chan struct{}),
+	}
+}
+
+// Config returns the config that the chain's plugin is (or will be) started with.
+func (c *Chain) Config() *config.ChainConfig {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.conf
+}
+
+// Process returns the chain's plugin process or nil if the plugin is not ready (e.g.
+// while it is being restarted).
+func (c *Chain) Process() *plgn.Process {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.proc
+}
+
+// IsStopped returns true once the chain's supervisor has exited.
+func (c *Chain) IsStopped() bool {
+	select {
+	case <-c.stopped:
+		return true
+	default:
+		return false
+	}
+}
+
+// Err returns the error that the chain stopped with (if any).
+func (c *Chain) Err() error {
+	if c.IsStopped() {
+		return c.err
+	} else {
+		return nil
+	}
+}
+
+// Reload replaces the chain's config. If the plugin is running, then it is asked to
+// apply the new config in place. Otherwise, the new config is used the next time the
+// plugin is started.
+func (c *Chain) Reload(ctx context.Context, conf *config.ChainConfig) error {
+	// NOTE: a plugin that is starting up has been sent the previous config, so we need
+	// to wait for the handshake to complete before it can be reloaded
+	c.starting.Lock()
+	defer c.starting.Unlock()
+
+	c.mutex.Lock()
+	c.conf = conf
+	proc := c.proc
+	c.mutex.Unlock()
+
+	if proc == nil {
+		return nil
+	} else {
+		return proc.Reload(ctx, conf)
+	}
+}
+
+func (c *Chain) start(ctx context.Context, out plgn.ProcessOptions) (*plgn.Process, error) {
+	c.starting.Lock()
+	defer c.starting.Unlock()
+
+	proc, err := plgn.Store.Start(ctx, c.Config(), out)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mutex.Lock()
+	c.proc = proc
+	c.mutex.Unlock()
+	return proc, nil
+}
+
+func (c *Chain) exited() {
+	c.mutex.Lock()
+	c.proc = nil
+	c.mutex.Unlock()
+}
+
+func (c *Chain) stop() {
+	c.cancel()
+	<-c.stopped
+}
+
+// Supervise runs the chain's plugin under a supervisor until the context is cancelled
+// or the supervisor gives up. Secrets from the config are redacted from the output of
+// the plugin and the supervisor.
+func Supervise(ctx context.Context, chain *Chain, out plgn.ProcessOptions, hooks supervisor.Hooks) error {
+	opts, err := supervisor.NewOptions(chain.Config().Restart)
+	if err != nil {
+		return err
+	}
+
+	out.Stdout = core.NewRedactWriter(out.Stdout, config.Redact)
+	out.Stderr = core.NewRedactWriter(out.Stderr, config.Redact)
+
+	onExit := hooks.OnExit
+	hooks.OnExit = func(exitCode int, err error) {
+		chain.exited()
+		if onExit != nil {
+			onExit(exitCode, err)
+		}
+	}
+
+	logger := log.New(out.Stderr, "[supervisor] ", log.LstdFlags)
+	return supervisor.New(opts, func(ctx context.Context, killTimeout time.Duration) (*plgn.Process, error) {
+		out.KillTimeout = killTimeout
+		return chain.start(ctx, out)
+	}, logger).WithHooks(hooks).Run(ctx)
+}
@@ -0,0 +1,24 @@
+package fleet
+
+import (
+	"fmt"
+	"strings"
+)
+
+type ChainNotFoundError struct {
+	Choices []string
+	Name    string
+}
+
+func (e *ChainNotFoundError) Error() string {
+	return fmt.Sprintf(
+		"chain '%s' is not managed by this process - must be one of: [ %s ]",
+		e.Name,
+		strings.Join(e.Choices, ", "),
+	)
+}
+
+func (e *ChainNotFoundError) Is(target error) bool {
+	_, ok := target.(*ChainNotFoundError)
+	return ok
+}
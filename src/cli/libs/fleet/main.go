@@ -0,0 +1,331 @@
+package fleet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/supervisor"
+)
+
+// Change describes what needs to happen to a running chain when its config changes.
+type Change string
+
+const (
+	ChangeNone    Change = "none"
+	ChangeReload  Change = "reload"
+	ChangeRestart Change = "restart"
+)
+
+type (
+	Options struct {
+		// FailFast stops all chains as soon as any of them fails permanently.
+		FailFast bool
+
+		// Output returns the writers that a chain's plugin output is forwarded to.
+		Output func(chainName string) plgn.ProcessOptions
+
+		// Configure can modify each chain's config before it is compared with the
+		// running config (e.g. to apply command line overrides).
+		Configure func(conf *config.ChainConfig)
+
+		// Hooks can attach supervisor hooks to a chain every time it is started, and
+		// Removed is called every time a chain is stopped because of a config change.
+		Hooks   func(chainName string, conf *config.ChainConfig) supervisor.Hooks
+		Removed func(chainName string)
+
+		// KeepAlive keeps the fleet open while no chains are running (e.g. when chains
+		// are started and stopped on demand), so it only stops once its context is
+		// cancelled.
+		KeepAlive bool
+
+		Logger *log.Logger
+	}
+
+	// Fleet runs several chains, each under its own supervisor, and starts, stops,
+	// reloads or restarts them whenever a new config is applied.
+	Fleet struct {
+		ctx     context.Context
+		cancel  context.CancelFunc
+		opts    Options
+		chains  map[string]*Chain
+		mutex   *sync.Mutex
+		running int
+		applied bool
+		closed  bool
+		done    chan struct{}
+	}
+)
+
+// Diff compares the running config of a chain with its new config. Changes to the
+// upstream endpoints and the finality level can be applied by the plugin in place,
+// while any other change requires the plugin to be restarted.
+func Diff(prev *config.ChainConfig, next *config.ChainConfig) Change {
+	if reflect.DeepEqual(prev, next) {
+		return ChangeNone
+	}
+
+	a, b := *prev, *next
+	a.Conn, b.Conn = nil, nil
+	a.Parachain, b.Parachain = nil, nil
+	a.Finality, b.Finality = "", ""
+	if reflect.DeepEqual(a, b) {
+		return ChangeReload
+	} else {
+		return ChangeRestart
+	}
+}
+
+func New(ctx context.Context, opts Options) *Fleet {
+	ctx, cancel := context.WithCancel(ctx)
+	f := &Fleet{
+		ctx:    ctx,
+		cancel: cancel,
+		opts:   opts,
+		chains: map[string]*Chain{},
+		mutex:  &sync.Mutex{},
+		done:   make(chan struct{}),
+	}
+
+	if opts.KeepAlive {
+		go func() {
+			<-ctx.Done()
+			f.mutex.Lock()
+			defer f.mutex.Unlock()
+			if f.running == 0 {
+				f.close()
+			}
+		}()
+	}
+
+	return f
+}
+
+// Apply makes the given chains of the config the set of running chains. Chains that
+// are new are started, chains that are no longer listed are stopped, and chains whose
+// config changed are reloaded in place or restarted. If a plugin cannot reload its
+// config (e.g. because it does not support reloads), then it is restarted instead. A
+// chain that stopped permanently is started again once its config changes.
+func (f *Fleet) Apply(ctx context.Context, cliConfig *config.CliConfig, chainNames []string) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if f.closed {
+		return
+	}
+
+	isReload := f.applied
+	f.applied = true
+
+	for _, chainName := range slices.Sorted(maps.Keys(f.chains)) {
+		if !slices.Contains(chainNames, chainName) {
+			f.remove(chainName)
+			f.opts.Logger.Printf("Stopped chain '%s'", chainName)
+		}
+	}
+
+	for _, chainName := range chainNames {
+		conf := cliConfig.Chains[chainName]
+		if f.opts.Configure != nil {
+			f.opts.Configure(&conf)
+		}
+
+		chain, exists := f.chains[chainName]
+		if !exists {
+			f.start(chainName, &conf)
+			if isReload {
+				f.opts.Logger.Printf("Started chain '%s'", chainName)
+			}
+			continue
+		}
+
+		change := Diff(chain.Config(), &conf)
+		if change == ChangeNone {
+			continue
+		}
+
+		if change == ChangeReload && !chain.IsStopped() {
+			if err := chain.Reload(ctx, &conf); err != nil {
+				f.opts.Logger.Printf("Failed to reload chain '%s' in place (%v) - restarting it", chainName, err)
+			} else {
+				f.opts.Logger.Printf("Reloaded chain '%s'", chainName)
+				continue
+			}
+		}
+
+		f.remove(chainName)
+		f.start(chainName, &conf)
+		f.opts.Logger.Printf("Restarted chain '%s'", chainName)
+	}
+
+	// NOTE: there is nothing to wait for if no chains were started
+	if f.running == 0 && !f.opts.KeepAlive {
+		f.close()
+	}
+}
+
+// Wait blocks until every chain has stopped (e.g. once the context is cancelled) and
+// returns the errors of the chains that stopped permanently.
+func (f *Fleet) Wait() error {
+	<-f.done
+
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	errs := []error{}
+	for _, chainName := range slices.Sorted(maps.Keys(f.chains)) {
+		errs = append(errs, f.chains[chainName].Err())
+	}
+	return errors.Join(errs...)
+}
+
+// Chains returns the chains that the fleet is currently managing.
+func (f *Fleet) Chains() []*Chain {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	chains := make([]*Chain, 0, len(f.chains))
+	for _, chainName := range slices.Sorted(maps.Keys(f.chains)) {
+		chains = append(chains, f.chains[chainName])
+	}
+	return chains
+}
+
+// Chain returns the chain with the given name.
+func (f *Fleet) Chain(chainName string) (*Chain, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.chain(chainName)
+}
+
+// Start starts a chain that has stopped (e.g. with Stop or because its supervisor
+// gave up) with its current config. Starting a running chain is a no-op.
+func (f *Fleet) Start(chainName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	chain, err := f.chain(chainName)
+	if err != nil {
+		return err
+	}
+
+	if chain.IsStopped() {
+		f.start(chainName, chain.Config())
+		f.opts.Logger.Printf("Started chain '%s'", chainName)
+	}
+	return nil
+}
+
+// Stop stops a chain and waits for its plugin to exit. Unlike a chain that has been
+// removed from the config, the chain can be started again with Start. Stopping a
+// chain that has already stopped is a no-op.
+func (f *Fleet) Stop(chainName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	chain, err := f.chain(chainName)
+	if err != nil {
+		return err
+	}
+
+	if !chain.IsStopped() {
+		chain.stop()
+		f.opts.Logger.Printf("Stopped chain '%s'", chainName)
+	}
+	return nil
+}
+
+// Restart stops a chain (if it is running) and starts it again with its current
+// config.
+func (f *Fleet) Restart(chainName string) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	chain, err := f.chain(chainName)
+	if err != nil {
+		return err
+	}
+
+	chain.stop()
+	f.start(chainName, chain.Config())
+	f.opts.Logger.Printf("Restarted chain '%s'", chainName)
+	return nil
+}
+
+func (f *Fleet) chain(chainName string) (*Chain, error) {
+	if f.closed {
+		return nil, errors.New("the chains have already stopped")
+	}
+
+	chain, exists := f.chains[chainName]
+	if !exists {
+		return nil, &ChainNotFoundError{Name: chainName, Choices: slices.Sorted(maps.Keys(f.chains))}
+	} else {
+		return chain, nil
+	}
+}
+
+func (f *Fleet) start(chainName string, conf *config.ChainConfig) {
+	ctx, cancel := context.WithCancel(f.ctx)
+	chain := NewChain(chainName, conf)
+	chain.cancel = cancel
+	f.chains[chainName] = chain
+	f.running++
+
+	out := f.opts.Output(chainName)
+	hooks := supervisor.Hooks{}
+	if f.opts.Hooks != nil {
+		hooks = f.opts.Hooks(chainName, conf)
+	}
+
+	go func() {
+		defer f.exited()
+		defer close(chain.stopped)
+		defer cancel()
+
+		if err := Supervise(ctx, chain, out, hooks); err != nil {
+			chain.err = fmt.Errorf("chain '%s': %w", chainName, err)
+			fmt.Fprintf(out.Stderr, "Chain stopped permanently: %v\n", err)
+			if f.opts.FailFast {
+				f.cancel()
+			}
+		}
+	}()
+}
+
+// remove stops a chain and waits for its plugin to exit.
+func (f *Fleet) remove(chainName string) {
+	f.chains[chainName].stop()
+	delete(f.chains, chainName)
+	if f.opts.Removed != nil {
+		f.opts.Removed(chainName)
+	}
+}
+
+// exited is called once a chain's supervisor has exited. The chain's stopped channel
+// is closed before the mutex is acquired, so that a chain can be stopped while the
+// mutex is held.
+func (f *Fleet) exited() {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	f.running--
+	if f.running == 0 && f.applied && (!f.opts.KeepAlive || f.ctx.Err() != nil) {
+		f.close()
+	}
+}
+
+func (f *Fleet) close() {
+	if !f.closed {
+		f.closed = true
+		f.cancel()
+		close(f.done)
+	}
+}
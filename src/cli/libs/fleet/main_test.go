@@ -0,0 +1,141 @@
+package fleet
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+)
+
+func newConfig() *config.ChainConfig {
+	return &config.ChainConfig{
+		Plugin:  &config.PluginConfig{ID: "eth"},
+		Server:  &config.ServerConfig{Host: "localhost", Port: 3000},
+		Conn:    &config.ConnectionConfg{Wss: "wss://eth.example.com"},
+		Restart: &config.RestartConfig{Policy: "on-failure"},
+	}
+}
+
+func TestDiff(t *testing.T) {
+	testCases := []struct {
+		name   string
+		modify func(conf *config.ChainConfig)
+		change Change
+	}{
+		{name: "unchanged", modify: func(conf *config.ChainConfig) {}, change: ChangeNone},
+		{name: "wss url", modify: func(conf *config.ChainConfig) { conf.Conn.Wss = "wss://other.example.com" }, change: ChangeReload},
+		{name: "rpc url", modify: func(conf *config.ChainConfig) { conf.Conn.Rpc = "https://eth.example.com" }, change: ChangeReload},
+		{name: "finality", modify: func(conf *config.ChainConfig) { conf.Finality = "finalized" }, change: ChangeReload},
+		{name: "parachain", modify: func(conf *config.ChainConfig) {
+			conf.Parachain = &config.ParachainConfig{ID: 2004, Relay: "wss://relay.example.com"}
+		}, change: ChangeReload},
+		{name: "port", modify: func(conf *config.ChainConfig) { conf.Server.Port = 3001 }, change: ChangeRestart},
+		{name: "plugin version", modify: func(conf *config.ChainConfig) { conf.Plugin.Version = "1.2.0" }, change: ChangeRestart},
+		{name: "restart policy", modify: func(conf *config.ChainConfig) { conf.Restart.Policy = "always" }, change: ChangeRestart},
+		{name: "url and port", modify: func(conf *config.ChainConfig) {
+			conf.Conn.Wss = "wss://other.example.com"
+			conf.Server.Port = 3001
+		}, change: ChangeRestart},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			next := newConfig()
+			tc.modify(next)
+			if change := Diff(newConfig(), next); change != tc.change {
+				t.Fatalf("expected change '%s' but got '%s'", tc.change, change)
+			}
+		})
+	}
+}
+
+// installScript installs a shell script as version 1.0.0 of a plugin in a temporary
+// plugin store, which replaces the default store until the test ends
+func installScript(t *testing.T, pluginID string, script string) {
+	store := plgn.Store
+	t.Cleanup(func() { plgn.Store = store })
+	plgn.Store = plgn.PluginStore{Dir: t.TempDir()}
+
+	pluginPath := filepath.Join(plgn.Store.Dir, pluginID, "1.0.0", "bin")
+	if err := os.MkdirAll(filepath.Dir(pluginPath), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// waitForProcess waits for the chain's plugin to complete the handshake
+func waitForProcess(t *testing.T, f *Fleet, chainName string) *plgn.Process {
+	for range 500 {
+		if chain, err := f.Chain(chainName); err != nil {
+			t.Fatal(err)
+		} else if proc := chain.Process(); proc != nil {
+			return proc
+		}
+		time.Sleep(time.Millisecond * 10)
+	}
+
+	t.Fatalf("chain '%s' did not start", chainName)
+	return nil
+}
+
+func TestApply(t *testing.T) {
+	testCases := []struct {
+		name         string
+		capabilities string
+		restarted    bool
+	}{
+		{name: "reload", capabilities: `"cursors","reload"`, restarted: false},
+		{name: "restart without reload capability", capabilities: `"cursors"`, restarted: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			installScript(t, "eth", `read -r req
+echo 'CC_HANDSHAKE {"protocolVersion":1,"capabilities":[`+tc.capabilities+`],"address":"127.0.0.1:3000"}'
+while read -r req; do echo 'CC_RELOAD {}'; done
+`)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			logs := new(bytes.Buffer)
+			f := New(ctx, Options{
+				Output: func(chainName string) plgn.ProcessOptions {
+					return plgn.ProcessOptions{Stdout: io.Discard, Stderr: io.Discard}
+				},
+				Logger: log.New(logs, "", 0),
+			})
+
+			cliConfig := &config.CliConfig{Chains: map[string]config.ChainConfig{"eth": *newConfig()}}
+			f.Apply(ctx, cliConfig, []string{"eth"})
+			prev := waitForProcess(t, f, "eth")
+
+			next := newConfig()
+			next.Conn.Wss = "wss://other.example.com"
+			cliConfig.Chains["eth"] = *next
+			f.Apply(ctx, cliConfig, []string{"eth"})
+			proc := waitForProcess(t, f, "eth")
+
+			if restarted := proc.Cmd.Process.Pid != prev.Cmd.Process.Pid; restarted != tc.restarted {
+				t.Fatalf("expected restarted = %t but got %t (logs: %s)", tc.restarted, restarted, logs.String())
+			}
+			if chain, _ := f.Chain("eth"); chain.Config().Conn.Wss != next.Conn.Wss {
+				t.Fatalf("expected the new config to be applied but got: %+v", chain.Config().Conn)
+			}
+
+			cancel()
+			if err := f.Wait(); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
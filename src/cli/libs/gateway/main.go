@@ -0,0 +1,234 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// METADATA_KEY_CHAIN is the request metadata key that consumers can use to select
+// a chain. It takes precedence over the chain name in the StartCursor message.
+const METADATA_KEY_CHAIN = "x-chain"
+
+type (
+	route struct {
+		pluginID string
+		address  string
+		conn     *grpc.ClientConn
+		client   pb.ChainCursorClient
+	}
+
+	// Gateway exposes a single gRPC endpoint in front of several plugins. Calls to
+	// ChainCursor are forwarded to the plugin that serves the requested chain.
+	Gateway struct {
+		pb.UnimplementedChainCursorServer
+		pb.UnimplementedGatewayServer
+		routes map[string]*route
+		mutex  *sync.RWMutex
+		opts   []grpc.DialOption
+	}
+)
+
+// New creates a gateway without any chains. The dial options are added to the options
+// that plugins are dialed with (e.g. to dial in-memory listeners).
+func New(opts ...grpc.DialOption) *Gateway {
+	return &Gateway{routes: map[string]*route{}, mutex: &sync.RWMutex{}, opts: opts}
+}
+
+func (g *Gateway) Register(server *grpc.Server) {
+	pb.RegisterChainCursorServer(server, g)
+	pb.RegisterGatewayServer(server, g)
+}
+
+// AddChain makes a chain known to the gateway. Calls for the chain are rejected
+// until the plugin that serves it reports that it is ready.
+func (g *Gateway) AddChain(name string, pluginID string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if _, exists := g.routes[name]; !exists {
+		g.routes[name] = &route{pluginID: pluginID}
+	}
+}
+
+// SetReady routes calls for the chain to the given plugin address.
+func (g *Gateway) SetReady(name string, address string) error {
+	opts := append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, g.opts...)
+	conn, err := grpc.NewClient(DialAddress(address), opts...)
+	if err != nil {
+		return err
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	r, exists := g.routes[name]
+	if !exists {
+		r = &route{}
+		g.routes[name] = r
+	}
+	if r.conn != nil {
+		r.conn.Close()
+	}
+
+	r.address = address
+	r.conn = conn
+	r.client = pb.NewChainCursorClient(conn)
+	return nil
+}
+
+// SetNotReady stops routing calls for the chain (e.g. while its plugin restarts).
+func (g *Gateway) SetNotReady(name string) {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	if r, exists := g.routes[name]; exists {
+		if r.conn != nil {
+			r.conn.Close()
+		}
+		r.address = ""
+		r.conn = nil
+		r.client = nil
+	}
+}
+
+// RemoveChain removes the chain from the gateway entirely.
+func (g *Gateway) RemoveChain(name string) {
+	g.SetNotReady(name)
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+	delete(g.routes, name)
+}
+
+func (g *Gateway) Close() error {
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	errs := []error{}
+	for _, r := range g.routes {
+		if r.conn != nil {
+			errs = append(errs, r.conn.Close())
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (g *Gateway) names() []string {
+	names := []string{}
+	for name := range g.routes {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (g *Gateway) resolve(ctx context.Context, start *pb.StartCursor) (string, pb.ChainCursorClient, error) {
+	name := start.GetChain()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(METADATA_KEY_CHAIN); len(values) != 0 {
+			name = values[0]
+		}
+	}
+
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	if name == "" {
+		return "", nil, status.Errorf(
+			codes.InvalidArgument,
+			"a chain name must be provided in the '%s' metadata or in the start cursor - must be one of: [ %s ]",
+			METADATA_KEY_CHAIN,
+			strings.Join(g.names(), ", "),
+		)
+	}
+
+	r, exists := g.routes[name]
+	if !exists {
+		return "", nil, status.Errorf(
+			codes.NotFound,
+			"chain with name '%s' does not exist - must be one of: [ %s ]",
+			name,
+			strings.Join(g.names(), ", "),
+		)
+	}
+	if r.client == nil {
+		return "", nil, status.Errorf(codes.Unavailable, "chain with name '%s' is not ready", name)
+	}
+
+	return name, r.client, nil
+}
+
+func (g *Gateway) Cursors(start *pb.StartCursor, stream grpc.ServerStreamingServer[pb.Cursor]) error {
+	ctx := stream.Context()
+
+	_, client, err := g.resolve(ctx, start)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: the chain name is only meaningful to the gateway, so it is stripped from
+	// the request before it is forwarded to the plugin
+	upstream, err := client.Cursors(ctx, &pb.StartCursor{Value: start.Value})
+	if err != nil {
+		return err
+	}
+
+	for {
+		cursor, err := upstream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := stream.Send(cursor); err != nil {
+			return err
+		}
+	}
+}
+
+func (g *Gateway) Chains(ctx context.Context, req *pb.ChainsRequest) (*pb.ChainsResponse, error) {
+	g.mutex.RLock()
+	defer g.mutex.RUnlock()
+
+	chains := []*pb.Chain{}
+	for _, name := range g.names() {
+		r := g.routes[name]
+		chains = append(chains, &pb.Chain{
+			Name:     name,
+			PluginId: r.pluginID,
+			Address:  r.address,
+			Ready:    r.client != nil,
+		})
+	}
+
+	return &pb.ChainsResponse{Chains: chains}, nil
+}
+
+// DialAddress converts the address a plugin is listening on into an address that
+// can be dialed (e.g. a plugin listening on all interfaces is dialed via loopback).
+func DialAddress(address string) string {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return address
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		return net.JoinHostPort("localhost", port)
+	} else {
+		return address
+	}
+}
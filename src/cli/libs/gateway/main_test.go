@@ -0,0 +1,215 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+const BUFFER_SIZE = 1024 * 1024
+
+// stubPlugin sends the start cursor (or its default cursor) followed by the next two
+// cursors, so every plugin of a test can be told apart by its cursors
+type stubPlugin struct {
+	pb.UnimplementedChainCursorServer
+	start int
+}
+
+func (p *stubPlugin) Cursors(start *pb.StartCursor, stream grpc.ServerStreamingServer[pb.Cursor]) error {
+	// NOTE: the chain name must not be forwarded to plugins
+	if start.Chain != nil {
+		return status.Errorf(codes.InvalidArgument, "unexpected chain '%s'", start.GetChain())
+	}
+
+	cursor := p.start
+	if start.Value != nil {
+		fmt.Sscan(start.GetValue(), &cursor)
+	}
+
+	for i := range 3 {
+		if err := stream.Send(&pb.Cursor{Value: fmt.Sprint(cursor + i)}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// network holds in-memory listeners that are dialed by name
+type network map[string]*bufconn.Listener
+
+func (n network) listen(t *testing.T, name string, register func(server *grpc.Server)) {
+	lis := bufconn.Listen(BUFFER_SIZE)
+	server := grpc.NewServer()
+	register(server)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+	n[name] = lis
+}
+
+func (n network) dialer() grpc.DialOption {
+	return grpc.WithContextDialer(func(ctx context.Context, address string) (net.Conn, error) {
+		if lis, exists := n[address]; exists {
+			return lis.DialContext(ctx)
+		} else {
+			return nil, fmt.Errorf("unknown address '%s'", address)
+		}
+	})
+}
+
+func (n network) address(name string) string {
+	return "passthrough:///" + name
+}
+
+func receive(stream grpc.ServerStreamingClient[pb.Cursor]) ([]string, error) {
+	cursors := []string{}
+	for {
+		cursor, err := stream.Recv()
+		if err == io.EOF {
+			return cursors, nil
+		}
+		if err != nil {
+			return cursors, err
+		}
+		cursors = append(cursors, cursor.Value)
+	}
+}
+
+func TestGateway(t *testing.T) {
+	ctx := context.Background()
+	nw := network{}
+
+	nw.listen(t, "eth", func(server *grpc.Server) {
+		pb.RegisterChainCursorServer(server, &stubPlugin{start: 100})
+	})
+	nw.listen(t, "solana", func(server *grpc.Server) {
+		pb.RegisterChainCursorServer(server, &stubPlugin{start: 500})
+	})
+
+	gw := New(nw.dialer())
+	defer gw.Close()
+	nw.listen(t, "gateway", gw.Register)
+
+	gw.AddChain("eth", "eth")
+	gw.AddChain("solana", "solana")
+	gw.AddChain("flow", "flow")
+	if err := gw.SetReady("eth", nw.address("eth")); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.SetReady("solana", nw.address("solana")); err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := grpc.NewClient(nw.address("gateway"), nw.dialer(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		defer conn.Close()
+	}
+	client := pb.NewChainCursorClient(conn)
+
+	cursors := func(ctx context.Context, start *pb.StartCursor) ([]string, error) {
+		stream, err := client.Cursors(ctx, start)
+		if err != nil {
+			return nil, err
+		} else {
+			return receive(stream)
+		}
+	}
+
+	chain := func(name string) *string { return &name }
+	value := func(value string) *string { return &value }
+
+	testCases := []struct {
+		name     string
+		metadata string
+		start    *pb.StartCursor
+		cursors  string
+		code     codes.Code
+	}{
+		{name: "metadata", metadata: "eth", start: &pb.StartCursor{}, cursors: "100,101,102"},
+		{name: "start cursor", start: &pb.StartCursor{Chain: chain("solana")}, cursors: "500,501,502"},
+		{name: "start cursor with value", start: &pb.StartCursor{Chain: chain("solana"), Value: value("7")}, cursors: "7,8,9"},
+		{name: "metadata takes precedence", metadata: "eth", start: &pb.StartCursor{Chain: chain("solana")}, cursors: "100,101,102"},
+		{name: "no chain", start: &pb.StartCursor{}, code: codes.InvalidArgument},
+		{name: "unknown chain", metadata: "bitcoin", start: &pb.StartCursor{}, code: codes.NotFound},
+		{name: "chain not ready", start: &pb.StartCursor{Chain: chain("flow")}, code: codes.Unavailable},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			callCtx := ctx
+			if tc.metadata != "" {
+				callCtx = metadata.AppendToOutgoingContext(ctx, METADATA_KEY_CHAIN, tc.metadata)
+			}
+
+			received, err := cursors(callCtx, tc.start)
+			if code := status.Code(err); code != tc.code {
+				t.Fatalf("expected code %s but got: %v", tc.code, err)
+			}
+			if tc.code == codes.OK && strings.Join(received, ",") != tc.cursors {
+				t.Fatalf("expected cursors [%s] but got %v", tc.cursors, received)
+			}
+		})
+	}
+
+	// NOTE: a chain is unavailable while its plugin restarts, and is served by the new
+	// plugin once it is ready again
+	gw.SetNotReady("eth")
+	if _, err := cursors(metadata.AppendToOutgoingContext(ctx, METADATA_KEY_CHAIN, "eth"), &pb.StartCursor{}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected the chain to be unavailable but got: %v", err)
+	}
+
+	nw.listen(t, "eth-restarted", func(server *grpc.Server) {
+		pb.RegisterChainCursorServer(server, &stubPlugin{start: 200})
+	})
+	if err := gw.SetReady("eth", nw.address("eth-restarted")); err != nil {
+		t.Fatal(err)
+	}
+	if received, err := cursors(metadata.AppendToOutgoingContext(ctx, METADATA_KEY_CHAIN, "eth"), &pb.StartCursor{}); err != nil {
+		t.Fatal(err)
+	} else if strings.Join(received, ",") != "200,201,202" {
+		t.Fatalf("expected the restarted plugin's cursors but got %v", received)
+	}
+
+	gw.RemoveChain("solana")
+	res, err := pb.NewGatewayClient(conn).Chains(ctx, &pb.ChainsRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed := []string{}
+	for _, c := range res.Chains {
+		listed = append(listed, fmt.Sprintf("%s:%s:%s:%t", c.Name, c.PluginId, c.Address, c.Ready))
+	}
+	expected := "eth:eth:passthrough:///eth-restarted:true,flow:flow::false"
+	if strings.Join(listed, ",") != expected {
+		t.Fatalf("expected chains [%s] but got %v", expected, listed)
+	}
+}
+
+func TestDialAddress(t *testing.T) {
+	testCases := map[string]string{
+		"0.0.0.0:3000":   "localhost:3000",
+		"[::]:3000":      "localhost:3000",
+		":3000":          "localhost:3000",
+		"127.0.0.1:3000": "127.0.0.1:3000",
+		"example.com:80": "example.com:80",
+	}
+
+	for address, expected := range testCases {
+		if actual := DialAddress(address); actual != expected {
+			t.Fatalf("expected '%s' to be dialed as '%s' but got '%s'", address, expected, actual)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package gh
+
+import "fmt"
+
+type NotFoundError struct {
+	Url string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("resource not found: %s", e.Url)
+}
+
+func (e *NotFoundError) Is(target error) bool {
+	_, ok := target.(*NotFoundError)
+	return ok
+}
@@ -20,7 +20,10 @@ type (
 	}
 
 	Release struct {
-		Assets []ReleaseAsset `json:"assets"`
+		TagName    string         `json:"tag_name"`
+		Draft      bool           `json:"draft"`
+		Prerelease bool           `json:"prerelease"`
+		Assets     []ReleaseAsset `json:"assets"`
 	}
 
 	Repository struct {
@@ -72,7 +75,12 @@ func (api *GithubApi) get(ctx context.Context, baseUrl string, endpoint string)
 		return nil, err
 	}
 
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, &NotFoundError{Url: url}
+	}
 	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
 		return nil, api.handleHttpError(res)
 	} else {
 		return res, nil
@@ -105,6 +113,41 @@ func (api *GithubApi) GetReleaseByTag(ctx context.Context, tag string) (*Release
 	}
 }
 
+// ListReleases returns all published releases of the repository (newest first).
+func (api *GithubApi) ListReleases(ctx context.Context) ([]Release, error) {
+	const perPage = 100
+
+	releases := []Release{}
+	for page := 1; ; page++ {
+		res, err := api.get(
+			ctx,
+			GITHUB_API_URL,
+			fmt.Sprintf(
+				"/repos/%s/%s/releases?per_page=%d&page=%d",
+				api.Repo.Owner,
+				api.Repo.Name,
+				perPage,
+				page,
+			),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		var data []Release
+		err = json.NewDecoder(res.Body).Decode(&data)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		releases = append(releases, data...)
+		if len(data) < perPage {
+			return releases, nil
+		}
+	}
+}
+
 func (api *GithubApi) DownloadReleaseAsset(ctx context.Context, tag string, name string) (*http.Response, error) {
 	return api.get(
 		ctx,
@@ -0,0 +1,21 @@
+package handshake
+
+import "fmt"
+
+type IncompatibleProtocolError struct {
+	Version int
+}
+
+func (e *IncompatibleProtocolError) Error() string {
+	return fmt.Sprintf(
+		"incompatible plugin protocol version %d - must be between %d and %d",
+		e.Version,
+		MIN_PROTOCOL_VERSION,
+		PROTOCOL_VERSION,
+	)
+}
+
+func (e *IncompatibleProtocolError) Is(target error) bool {
+	_, ok := target.(*IncompatibleProtocolError)
+	return ok
+}
@@ -0,0 +1,145 @@
+package handshake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+// The handshake works as follows:
+//
+//  1. The CLI starts the plugin and writes a Request (which includes the chain
+//     config) to the plugin's stdin. Passing the config over stdin rather than as
+//     a command line argument keeps secrets such as RPC URLs out of `ps` output.
+//  2. The plugin validates the request, starts listening, and then writes a single
+//     Response line (prefixed with HANDSHAKE_PREFIX) to its stdout. The response
+//     tells the CLI which protocol version the plugin speaks, which capabilities it
+//     supports, and which address it actually bound to.
+//  3. The CLI verifies that the plugin is compatible before marking it as ready.
+//     All other stdout lines are treated as regular plugin logs.
+const (
+	MIN_PROTOCOL_VERSION = 1
+	PROTOCOL_VERSION     = 1
+	HANDSHAKE_PREFIX     = "CC_HANDSHAKE "
+)
+
+const (
+	CAPABILITY_CURSORS = "cursors"
+)
+
+type (
+	Request struct {
+		ProtocolVersion int                 `json:"protocolVersion"`
+		Config          *config.ChainConfig `json:"config"`
+	}
+
+	Response struct {
+		ProtocolVersion int      `json:"protocolVersion"`
+		Capabilities    []string `json:"capabilities"`
+		Address         string   `json:"address"`
+	}
+)
+
+func NewRequest(conf *config.ChainConfig) *Request {
+	return &Request{ProtocolVersion: PROTOCOL_VERSION, Config: conf}
+}
+
+func NewResponse(address string, capabilities ...string) *Response {
+	return &Response{
+		ProtocolVersion: PROTOCOL_VERSION,
+		Capabilities:    append([]string{CAPABILITY_CURSORS}, capabilities...),
+		Address:         address,
+	}
+}
+
+func IsCompatible(protocolVersion int) bool {
+	return protocolVersion >= MIN_PROTOCOL_VERSION && protocolVersion <= PROTOCOL_VERSION
+}
+
+func WriteRequest(w io.Writer, req *Request) error {
+	return json.NewEncoder(w).Encode(req)
+}
+
+// ReadRequest is called by plugins to read the handshake request from the CLI. It
+// also performs the basic checks that every plugin relies on so that a malformed
+// config results in an error rather than a nil pointer dereference.
+//
+// Plugins that support reloads should use a RequestReader instead, since the CLI
+// writes further requests to the same stream.
+func ReadRequest(r io.Reader) (*Request, error) {
+	var req Request
+	if err := json.NewDecoder(r).Decode(&req); err != nil {
+		return nil, fmt.Errorf("failed to read handshake request: %w", err)
+	}
+
+	if err := checkRequest(&req); err != nil {
+		return nil, err
+	} else {
+		return &req, nil
+	}
+}
+
+func checkRequest(req *Request) error {
+	if !IsCompatible(req.ProtocolVersion) {
+		return &IncompatibleProtocolError{Version: req.ProtocolVersion}
+	}
+	if req.Config == nil {
+		return errors.New("handshake request does not contain a chain config")
+	}
+	if req.Config.Server == nil {
+		return errors.New("chain config is missing the 'server' block")
+	}
+	if req.Config.Conn == nil {
+		return errors.New("chain config is missing the 'conn' block")
+	}
+
+	return nil
+}
+
+func WriteResponse(w io.Writer, res *Response) error {
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: the response must be written with a single call so that it cannot be
+	// interleaved with log lines that are written to stdout by other goroutines
+	_, err = fmt.Fprintf(w, "%s%s\n", HANDSHAKE_PREFIX, string(data))
+	return err
+}
+
+// ParseResponse parses a line of plugin output. The boolean return value is false
+// if the line is not a handshake response (i.e. it is a regular log line).
+func ParseResponse(line string) (*Response, bool, error) {
+	data, found := strings.CutPrefix(line, HANDSHAKE_PREFIX)
+	if !found {
+		return nil, false, nil
+	}
+
+	var res Response
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		return nil, true, fmt.Errorf("failed to parse handshake response: %w", err)
+	}
+
+	if !IsCompatible(res.ProtocolVersion) {
+		return nil, true, &IncompatibleProtocolError{Version: res.ProtocolVersion}
+	}
+	if res.Address == "" {
+		return nil, true, errors.New("handshake response does not contain a listening address")
+	}
+
+	return &res, true, nil
+}
+
+func (res *Response) HasCapability(capability string) bool {
+	for _, c := range res.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,140 @@
+package handshake
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+func newChainConfig() *config.ChainConfig {
+	return &config.ChainConfig{
+		Server: &config.ServerConfig{Host: "localhost", Port: 3000},
+		Conn:   &config.ConnectionConfg{Wss: "wss://eth.example.com"},
+	}
+}
+
+func TestRequest(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteRequest(buf, NewRequest(newChainConfig())); err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := ReadRequest(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if req.ProtocolVersion != PROTOCOL_VERSION || req.Config.Conn.Wss != "wss://eth.example.com" || req.Config.Server.Port != 3000 {
+		t.Fatalf("unexpected request: %+v", req)
+	}
+
+	testCases := []struct {
+		name  string
+		input string
+		err   string
+	}{
+		{name: "malformed", input: `{"protocolVersion":`, err: "failed to read handshake request"},
+		{name: "incompatible version", input: `{"protocolVersion":99,"config":{"server":{},"conn":{}}}`, err: "incompatible plugin protocol version 99"},
+		{name: "no config", input: `{"protocolVersion":1}`, err: "does not contain a chain config"},
+		{name: "no server block", input: `{"protocolVersion":1,"config":{"conn":{}}}`, err: "missing the 'server' block"},
+		{name: "no conn block", input: `{"protocolVersion":1,"config":{"server":{}}}`, err: "missing the 'conn' block"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := ReadRequest(strings.NewReader(tc.input)); err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+			}
+		})
+	}
+
+	if _, err := ReadRequest(strings.NewReader(`{"protocolVersion":0,"config":{"server":{},"conn":{}}}`)); !errors.Is(err, &IncompatibleProtocolError{}) {
+		t.Fatalf("expected an incompatible protocol error but got: %v", err)
+	}
+}
+
+func TestRequestReader(t *testing.T) {
+	buf := new(bytes.Buffer)
+	for _, wss := range []string{"wss://a.example.com", "wss://b.example.com"} {
+		conf := newChainConfig()
+		conf.Conn.Wss = wss
+		if err := WriteRequest(buf, NewRequest(conf)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// NOTE: the handshake request and the reload requests are read from one stream
+	requests := NewRequestReader(buf)
+	for _, wss := range []string{"wss://a.example.com", "wss://b.example.com"} {
+		if req, err := requests.Read(); err != nil {
+			t.Fatal(err)
+		} else if req.Config.Conn.Wss != wss {
+			t.Fatalf("expected '%s' but got '%s'", wss, req.Config.Conn.Wss)
+		}
+	}
+	if _, err := requests.Read(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected EOF but got: %v", err)
+	}
+}
+
+func TestResponse(t *testing.T) {
+	buf := new(bytes.Buffer)
+	if err := WriteResponse(buf, NewResponse("127.0.0.1:3000", CAPABILITY_RELOAD)); err != nil {
+		t.Fatal(err)
+	}
+
+	res, ok, err := ParseResponse(strings.TrimSuffix(buf.String(), "\n"))
+	if err != nil || !ok {
+		t.Fatalf("expected a handshake response but got: %v", err)
+	}
+	if res.Address != "127.0.0.1:3000" || !slices.Equal(res.Capabilities, []string{CAPABILITY_CURSORS, CAPABILITY_RELOAD}) {
+		t.Fatalf("unexpected response: %+v", res)
+	}
+	if !res.HasCapability(CAPABILITY_RELOAD) || res.HasCapability("sporks") {
+		t.Fatalf("unexpected capabilities: %v", res.Capabilities)
+	}
+
+	// NOTE: lines without the prefix are regular log lines
+	if _, ok, err := ParseResponse("Listening on 127.0.0.1:3000"); ok || err != nil {
+		t.Fatalf("expected a log line but got: %t, %v", ok, err)
+	}
+
+	testCases := []struct {
+		name string
+		line string
+		err  string
+	}{
+		{name: "malformed", line: HANDSHAKE_PREFIX + "{", err: "failed to parse handshake response"},
+		{name: "incompatible version", line: HANDSHAKE_PREFIX + `{"protocolVersion":99,"address":"127.0.0.1:3000"}`, err: "incompatible plugin protocol version 99"},
+		{name: "no address", line: HANDSHAKE_PREFIX + `{"protocolVersion":1}`, err: "does not contain a listening address"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, ok, err := ParseResponse(tc.line); !ok || err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestReloadResponse(t *testing.T) {
+	for _, reloadErr := range []error{nil, errors.New("failed to connect")} {
+		buf := new(bytes.Buffer)
+		if err := WriteReloadResponse(buf, reloadErr); err != nil {
+			t.Fatal(err)
+		}
+
+		res, ok, err := ParseReloadResponse(strings.TrimSuffix(buf.String(), "\n"))
+		if err != nil || !ok {
+			t.Fatalf("expected a reload response but got: %v", err)
+		}
+		if (res.Err() == nil) != (reloadErr == nil) || (reloadErr != nil && res.Err().Error() != reloadErr.Error()) {
+			t.Fatalf("expected error %v but got %v", reloadErr, res.Err())
+		}
+	}
+}
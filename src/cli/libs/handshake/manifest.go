@@ -0,0 +1,142 @@
+package handshake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	embeds "github.com/chris-de-leon/chain-connectors-prototype"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+// MANIFEST_ARG is the command line argument that makes a plugin print its manifest
+// to stdout and exit instead of waiting for a handshake request. The CLI records the
+// manifest when the plugin is installed.
+const MANIFEST_ARG = "manifest"
+
+// The chain config fields that a plugin can require
+const (
+	FIELD_CONN_WSS        = "conn.wss"
+	FIELD_CONN_RPC        = "conn.rpc"
+	FIELD_CONN_SPORKS     = "conn.sporks"
+	FIELD_PARACHAIN_RELAY = "parachain.relay"
+)
+
+type (
+	// Manifest describes a plugin and what it expects from a chain config.
+	Manifest struct {
+		ID              string   `json:"id"`
+		Version         string   `json:"version"`
+		ProtocolVersion int      `json:"protocolVersion"`
+		Chains          []string `json:"chains"`
+
+		// Conn lists the chain config fields that must be set. Each entry is a group
+		// of alternatives (e.g. either a WSS or an RPC URL), so at least one field of
+		// every group must be set.
+		Conn [][]string `json:"conn"`
+
+		// Finality lists the supported values of the chain config's 'finality' field.
+		// If empty, then the plugin does not support choosing a finality level.
+		Finality []string `json:"finality,omitempty"`
+
+		// Rpcs lists the gRPC methods that the plugin serves in addition to the
+		// ChainCursor service, in the form '<package>.<service>/<method>'.
+		Rpcs []string `json:"rpcs,omitempty"`
+	}
+)
+
+// ReleaseVersion is the version of the release that a plugin was built for. Plugins
+// in this repository are released together with the CLI, so they share its version.
+func ReleaseVersion() string {
+	return strings.ReplaceAll(embeds.Version, "\n", "")
+}
+
+// IsManifestRequest is called by plugins with their command line arguments to check
+// whether the CLI is asking for the manifest.
+func IsManifestRequest(args []string) bool {
+	return len(args) == 2 && args[1] == MANIFEST_ARG
+}
+
+func WriteManifest(w io.Writer, manifest *Manifest) error {
+	return json.NewEncoder(w).Encode(manifest)
+}
+
+func ReadManifest(r io.Reader) (*Manifest, error) {
+	var manifest Manifest
+	if err := json.NewDecoder(r).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse plugin manifest: %w", err)
+	}
+
+	if manifest.ID == "" {
+		return nil, errors.New("plugin manifest does not contain an ID")
+	}
+	if manifest.Version == "" {
+		return nil, fmt.Errorf("manifest of plugin '%s' does not contain a version", manifest.ID)
+	}
+
+	return &manifest, nil
+}
+
+// Validate checks that a chain config can be served by the plugin. All problems are
+// reported at once.
+func (m *Manifest) Validate(conf *config.ChainConfig) error {
+	errs := []error{}
+
+	if !IsCompatible(m.ProtocolVersion) {
+		errs = append(errs, &IncompatibleProtocolError{Version: m.ProtocolVersion})
+	}
+
+	for _, group := range m.Conn {
+		if !slices.ContainsFunc(group, func(field string) bool { return isSet(conf, field) }) {
+			if len(group) == 1 {
+				errs = append(errs, fmt.Errorf("plugin '%s' requires '%s' to be set", m.ID, group[0]))
+			} else {
+				errs = append(errs, fmt.Errorf("plugin '%s' requires one of [ %s ] to be set", m.ID, strings.Join(group, ", ")))
+			}
+		}
+	}
+
+	if conf.Finality != "" && !slices.Contains(m.Finality, conf.Finality) {
+		if len(m.Finality) == 0 {
+			errs = append(errs, fmt.Errorf("plugin '%s' does not support the 'finality' field", m.ID))
+		} else {
+			errs = append(errs, fmt.Errorf("plugin '%s' does not support finality '%s' - must be one of: [ %s ]", m.ID, conf.Finality, strings.Join(m.Finality, ", ")))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (m *Manifest) String() string {
+	conn := make([]string, len(m.Conn))
+	for i, group := range m.Conn {
+		conn[i] = strings.Join(group, "|")
+	}
+
+	return fmt.Sprintf(
+		"protocol = v%d, chains = [ %s ], conn = [ %s ], finality = [ %s ], rpcs = [ %s ]",
+		m.ProtocolVersion,
+		strings.Join(m.Chains, ", "),
+		strings.Join(conn, ", "),
+		strings.Join(m.Finality, ", "),
+		strings.Join(m.Rpcs, ", "),
+	)
+}
+
+func isSet(conf *config.ChainConfig, field string) bool {
+	switch field {
+	case FIELD_CONN_WSS:
+		return conf.Conn != nil && conf.Conn.Wss != ""
+	case FIELD_CONN_RPC:
+		return conf.Conn != nil && conf.Conn.Rpc != ""
+	case FIELD_CONN_SPORKS:
+		return conf.Conn != nil && len(conf.Conn.Sporks) != 0
+	case FIELD_PARACHAIN_RELAY:
+		return conf.Parachain != nil && conf.Parachain.Relay != ""
+	default:
+		return false
+	}
+}
@@ -0,0 +1,99 @@
+package handshake
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Reloads work as follows:
+//
+//  1. The CLI keeps the plugin's stdin open after the handshake. If the plugin has the
+//     CAPABILITY_RELOAD capability, then the CLI can write another Request (with the
+//     new chain config) to it at any time.
+//  2. The plugin connects to the new upstream endpoints and swaps them in without
+//     stopping its gRPC server, so consumer streams stay open. The server settings
+//     never change during a reload (the CLI restarts the plugin instead).
+//  3. The plugin writes a single ReloadResponse line (prefixed with RELOAD_PREFIX)
+//     to its stdout. If the reload failed, then the plugin keeps using its previous
+//     connection and reports the error.
+const (
+	CAPABILITY_RELOAD = "reload"
+	RELOAD_PREFIX     = "CC_RELOAD "
+)
+
+type (
+	ReloadResponse struct {
+		Error string `json:"error,omitempty"`
+	}
+
+	// RequestReader reads the handshake request and any reload requests that follow
+	// it from the same stream.
+	RequestReader struct {
+		decoder *json.Decoder
+	}
+)
+
+func NewRequestReader(r io.Reader) *RequestReader {
+	return &RequestReader{decoder: json.NewDecoder(r)}
+}
+
+// Read reads the next request. It returns io.EOF once the CLI has closed the stream.
+func (r *RequestReader) Read() (*Request, error) {
+	var req Request
+	if err := r.decoder.Decode(&req); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, err
+		} else {
+			return nil, fmt.Errorf("failed to read handshake request: %w", err)
+		}
+	}
+
+	if err := checkRequest(&req); err != nil {
+		return nil, err
+	} else {
+		return &req, nil
+	}
+}
+
+func WriteReloadResponse(w io.Writer, reloadErr error) error {
+	res := &ReloadResponse{}
+	if reloadErr != nil {
+		res.Error = reloadErr.Error()
+	}
+
+	data, err := json.Marshal(res)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "%s%s\n", RELOAD_PREFIX, string(data))
+	return err
+}
+
+// ParseReloadResponse parses a line of plugin output. The boolean return value is
+// false if the line is not a reload response.
+func ParseReloadResponse(line string) (*ReloadResponse, bool, error) {
+	data, found := strings.CutPrefix(line, RELOAD_PREFIX)
+	if !found {
+		return nil, false, nil
+	}
+
+	var res ReloadResponse
+	if err := json.Unmarshal([]byte(data), &res); err != nil {
+		return nil, true, fmt.Errorf("failed to parse reload response: %w", err)
+	} else {
+		return &res, true, nil
+	}
+}
+
+// Err converts the response into the error that the plugin reported (if any).
+func (res *ReloadResponse) Err() error {
+	if res.Error == "" {
+		return nil
+	} else {
+		return errors.New(res.Error)
+	}
+}
@@ -0,0 +1,83 @@
+package integrity
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Checksums maps file names to their hex-encoded SHA-256 digests.
+type Checksums map[string]string
+
+// ParseChecksums parses a checksums file in the format produced by `sha256sum` (and
+// by goreleaser), where each line contains a digest followed by a file name.
+func ParseChecksums(r io.Reader) (Checksums, error) {
+	checksums := Checksums{}
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("invalid checksums file - line %d does not have the format '<sha256> <name>'", n)
+		}
+
+		digest := strings.ToLower(fields[0])
+		if decoded, err := hex.DecodeString(digest); err != nil || len(decoded) != sha256.Size {
+			return nil, fmt.Errorf("invalid checksums file - line %d does not contain a valid SHA-256 digest", n)
+		}
+
+		// NOTE: sha256sum prefixes the name with '*' when the file was read in binary mode
+		checksums[strings.TrimPrefix(fields[1], "*")] = digest
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	} else {
+		return checksums, nil
+	}
+}
+
+// Verify checks the digest of the named file against the checksums file.
+func (checksums Checksums) Verify(name string, digest string) error {
+	expected, exists := checksums[name]
+	if !exists {
+		return fmt.Errorf("no checksum has been published for '%s'", name)
+	}
+
+	if !strings.EqualFold(expected, digest) {
+		return &ChecksumMismatchError{Name: name, Expected: expected, Actual: digest}
+	} else {
+		return nil
+	}
+}
+
+// Digest returns the hex-encoded SHA-256 digest of the data read from r.
+func Digest(r io.Reader) (string, error) {
+	hash := sha256.New()
+	if _, err := io.Copy(hash, r); err != nil {
+		return "", err
+	} else {
+		return hex.EncodeToString(hash.Sum(nil)), nil
+	}
+}
+
+// DigestFile returns the hex-encoded SHA-256 digest of a file.
+func DigestFile(filePath string) (string, error) {
+	file, err := os.Open(filePath)
+	if err != nil {
+		return "", err
+	} else {
+		defer file.Close()
+	}
+
+	return Digest(file)
+}
@@ -0,0 +1,36 @@
+package integrity
+
+import "fmt"
+
+type ChecksumMismatchError struct {
+	Name     string
+	Expected string
+	Actual   string
+}
+
+func (e *ChecksumMismatchError) Error() string {
+	return fmt.Sprintf(
+		"checksum mismatch for '%s' - expected sha256 %s but got %s",
+		e.Name,
+		e.Expected,
+		e.Actual,
+	)
+}
+
+func (e *ChecksumMismatchError) Is(target error) bool {
+	_, ok := target.(*ChecksumMismatchError)
+	return ok
+}
+
+type SignatureError struct {
+	Reason string
+}
+
+func (e *SignatureError) Error() string {
+	return fmt.Sprintf("signature verification failed: %s", e.Reason)
+}
+
+func (e *SignatureError) Is(target error) bool {
+	_, ok := target.(*SignatureError)
+	return ok
+}
@@ -0,0 +1,126 @@
+package integrity
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+type testKey struct {
+	id  [keyIDSize]byte
+	pub ed25519.PublicKey
+	sec ed25519.PrivateKey
+}
+
+func newTestKey(t *testing.T) *testKey {
+	pub, sec, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key := &testKey{pub: pub, sec: sec}
+	if _, err := rand.Read(key.id[:]); err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func (key *testKey) publicKeyFile() string {
+	data := append([]byte(SIGNATURE_ALGORITHM), key.id[:]...)
+	data = append(data, key.pub...)
+	return fmt.Sprintf("%sminisign public key %X\n%s\n", UNTRUSTED_COMMENT_PREFIX, key.id, base64.StdEncoding.EncodeToString(data))
+}
+
+// sign produces a signature file in the same format as `minisign -S`
+func (key *testKey) sign(message []byte, trustedComment string) string {
+	digest := blake2b.Sum512(message)
+	sig := ed25519.Sign(key.sec, digest[:])
+	globalSig := ed25519.Sign(key.sec, append(append([]byte{}, sig...), []byte(trustedComment)...))
+
+	data := append([]byte(SIGNATURE_ALGORITHM_HASHED), key.id[:]...)
+	data = append(data, sig...)
+	return strings.Join([]string{
+		UNTRUSTED_COMMENT_PREFIX + "signature from minisign secret key",
+		base64.StdEncoding.EncodeToString(data),
+		TRUSTED_COMMENT_PREFIX + trustedComment,
+		base64.StdEncoding.EncodeToString(globalSig),
+	}, "\n") + "\n"
+}
+
+func TestChecksums(t *testing.T) {
+	digest, err := Digest(strings.NewReader("hello"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksums, err := ParseChecksums(strings.NewReader(fmt.Sprintf("%s  eth-plugin.tar.gz\n\n%s *beacon-plugin.tar.gz\n", digest, strings.ToUpper(digest))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := checksums.Verify("eth-plugin.tar.gz", digest); err != nil {
+		t.Fatal(err)
+	}
+	if err := checksums.Verify("beacon-plugin.tar.gz", digest); err != nil {
+		t.Fatal(err)
+	}
+	if err := checksums.Verify("eth-plugin.tar.gz", strings.Repeat("0", len(digest))); !errors.Is(err, &ChecksumMismatchError{}) {
+		t.Fatalf("expected a checksum mismatch but got: %v", err)
+	}
+	if err := checksums.Verify("flow-plugin.tar.gz", digest); err == nil {
+		t.Fatal("expected an error for a file without a published checksum")
+	}
+
+	if _, err := ParseChecksums(strings.NewReader("not-a-digest  eth-plugin.tar.gz\n")); err == nil {
+		t.Fatal("expected an error for an invalid digest")
+	}
+}
+
+func TestSignature(t *testing.T) {
+	key := newTestKey(t)
+	message := []byte("checksums")
+
+	pk, err := ParsePublicKey(key.publicKeyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err := ParseSignature(key.sign(message, "timestamp:1700000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pk.Verify(message, sig); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := pk.Verify([]byte("tampered"), sig); !errors.Is(err, &SignatureError{}) {
+		t.Fatalf("expected a signature error for a tampered message but got: %v", err)
+	}
+
+	sig.TrustedComment = "timestamp:0"
+	if err := pk.Verify(message, sig); !errors.Is(err, &SignatureError{}) {
+		t.Fatalf("expected a signature error for a tampered trusted comment but got: %v", err)
+	}
+
+	otherKey, err := ParsePublicKey(newTestKey(t).publicKeyFile())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sig, err = ParseSignature(key.sign(message, "timestamp:1700000000"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := otherKey.Verify(message, sig); !errors.Is(err, &SignatureError{}) {
+		t.Fatalf("expected a signature error for a different key but got: %v", err)
+	}
+}
@@ -0,0 +1,158 @@
+package integrity
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Signatures use the minisign format (https://jedisct1.github.io/minisign), which is
+// based on Ed25519. Releases publish a signature of the checksums file, so a single
+// signature covers every plugin archive in the release.
+const (
+	SIGNATURE_ALGORITHM        = "Ed"
+	SIGNATURE_ALGORITHM_HASHED = "ED"
+	TRUSTED_COMMENT_PREFIX     = "trusted comment: "
+	UNTRUSTED_COMMENT_PREFIX   = "untrusted comment: "
+)
+
+const (
+	keyIDSize          = 8
+	publicKeyFileSize  = 2 + keyIDSize + ed25519.PublicKeySize
+	signatureFileSize  = 2 + keyIDSize + ed25519.SignatureSize
+	globalSignatureLen = ed25519.SignatureSize
+)
+
+type (
+	PublicKey struct {
+		KeyID [keyIDSize]byte
+		Key   ed25519.PublicKey
+	}
+
+	Signature struct {
+		Algorithm       string
+		KeyID           [keyIDSize]byte
+		Signature       []byte
+		TrustedComment  string
+		GlobalSignature []byte
+	}
+)
+
+// LoadPublicKey accepts either a base64 encoded minisign public key or the path to a
+// minisign public key file.
+func LoadPublicKey(value string) (*PublicKey, error) {
+	if data, err := os.ReadFile(value); err == nil {
+		return ParsePublicKey(string(data))
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	} else {
+		return ParsePublicKey(value)
+	}
+}
+
+// ParsePublicKey parses the contents of a minisign public key file. The untrusted
+// comment line is optional.
+func ParsePublicKey(value string) (*PublicKey, error) {
+	lines := nonEmptyLines(value)
+	if len(lines) != 0 && strings.HasPrefix(lines[0], UNTRUSTED_COMMENT_PREFIX) {
+		lines = lines[1:]
+	}
+	if len(lines) != 1 {
+		return nil, errors.New("invalid public key - expected a single base64 encoded key")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(lines[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key: %w", err)
+	}
+	if len(data) != publicKeyFileSize || string(data[:2]) != SIGNATURE_ALGORITHM {
+		return nil, errors.New("invalid public key - not an Ed25519 minisign key")
+	}
+
+	pk := &PublicKey{Key: ed25519.PublicKey(data[2+keyIDSize:])}
+	copy(pk.KeyID[:], data[2:2+keyIDSize])
+	return pk, nil
+}
+
+// ParseSignature parses the contents of a minisign signature file.
+func ParseSignature(value string) (*Signature, error) {
+	lines := nonEmptyLines(value)
+	if len(lines) != 4 {
+		return nil, errors.New("invalid signature - expected 4 lines")
+	}
+
+	data, err := base64.StdEncoding.DecodeString(lines[1])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(data) != signatureFileSize {
+		return nil, errors.New("invalid signature - unexpected length")
+	}
+
+	trustedComment, found := strings.CutPrefix(lines[2], TRUSTED_COMMENT_PREFIX)
+	if !found {
+		return nil, errors.New("invalid signature - missing trusted comment")
+	}
+
+	globalSignature, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %w", err)
+	}
+	if len(globalSignature) != globalSignatureLen {
+		return nil, errors.New("invalid signature - unexpected global signature length")
+	}
+
+	sig := &Signature{
+		Algorithm:       string(data[:2]),
+		Signature:       data[2+keyIDSize:],
+		TrustedComment:  trustedComment,
+		GlobalSignature: globalSignature,
+	}
+	copy(sig.KeyID[:], data[2:2+keyIDSize])
+	return sig, nil
+}
+
+// Verify checks that the signature was produced for the message by this key. Both
+// the legacy and the pre-hashed (BLAKE2b-512) minisign formats are supported.
+func (pk *PublicKey) Verify(message []byte, sig *Signature) error {
+	if !bytes.Equal(pk.KeyID[:], sig.KeyID[:]) {
+		return &SignatureError{Reason: fmt.Sprintf("signed with key %X but the configured key is %X", sig.KeyID, pk.KeyID)}
+	}
+
+	switch sig.Algorithm {
+	case SIGNATURE_ALGORITHM:
+	case SIGNATURE_ALGORITHM_HASHED:
+		digest := blake2b.Sum512(message)
+		message = digest[:]
+	default:
+		return &SignatureError{Reason: fmt.Sprintf("unsupported signature algorithm '%s'", sig.Algorithm)}
+	}
+
+	if !ed25519.Verify(pk.Key, message, sig.Signature) {
+		return &SignatureError{Reason: "signature does not match"}
+	}
+
+	// NOTE: the global signature covers the trusted comment, which prevents it from
+	// being tampered with
+	if !ed25519.Verify(pk.Key, append(bytes.Clone(sig.Signature), []byte(sig.TrustedComment)...), sig.GlobalSignature) {
+		return &SignatureError{Reason: "trusted comment signature does not match"}
+	}
+
+	return nil
+}
+
+func nonEmptyLines(value string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
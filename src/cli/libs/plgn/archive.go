@@ -0,0 +1,171 @@
+package plgn
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DEFAULT_MAX_ARCHIVE_ENTRIES    = 32
+	DEFAULT_MAX_ARCHIVE_FILE_SIZE  = 512 << 20
+	DEFAULT_MAX_ARCHIVE_TOTAL_SIZE = 1 << 30
+)
+
+// ArchiveLimits bound the resources that can be consumed by extracting a release
+// asset. This protects the cache against archives that decompress to huge files.
+type ArchiveLimits struct {
+	MaxEntries   int
+	MaxFileSize  int64
+	MaxTotalSize int64
+}
+
+func DefaultArchiveLimits() ArchiveLimits {
+	return ArchiveLimits{
+		MaxEntries:   DEFAULT_MAX_ARCHIVE_ENTRIES,
+		MaxFileSize:  DEFAULT_MAX_ARCHIVE_FILE_SIZE,
+		MaxTotalSize: DEFAULT_MAX_ARCHIVE_TOTAL_SIZE,
+	}
+}
+
+// withDefaults replaces any unset limits with their defaults
+func (limits ArchiveLimits) withDefaults() ArchiveLimits {
+	defaults := DefaultArchiveLimits()
+	if limits.MaxEntries <= 0 {
+		limits.MaxEntries = defaults.MaxEntries
+	}
+	if limits.MaxFileSize <= 0 {
+		limits.MaxFileSize = defaults.MaxFileSize
+	}
+	if limits.MaxTotalSize <= 0 {
+		limits.MaxTotalSize = defaults.MaxTotalSize
+	}
+	return limits
+}
+
+// unpack extracts a gzipped tarball into dst. The files are first written to a
+// temporary directory next to dst, which is renamed to dst only once every file has
+// been extracted. If extraction fails, the temporary directory is removed, so dst
+// never ends up half-populated.
+func (cache *PluginCache) unpack(src io.Reader, dst string) ([]string, error) {
+	if err := os.MkdirAll(filepath.Dir(dst), os.ModePerm); err != nil {
+		return []string{}, err
+	}
+
+	tmpDir, err := os.MkdirTemp(filepath.Dir(dst), filepath.Base(dst)+".*.tmp")
+	if err != nil {
+		return []string{}, err
+	}
+
+	if err := cache.extract(src, tmpDir); err != nil {
+		return []string{}, errors.Join(err, os.RemoveAll(tmpDir))
+	}
+
+	if err := os.Rename(tmpDir, dst); err != nil {
+		// NOTE: if another process populated the cache in the meantime, then its
+		// (complete) copy is used instead
+		if pluginPaths, listErr := cache.list(dst); listErr == nil {
+			return pluginPaths, os.RemoveAll(tmpDir)
+		}
+		return []string{}, errors.Join(err, os.RemoveAll(tmpDir))
+	}
+
+	return cache.list(dst)
+}
+
+func (cache *PluginCache) extract(src io.Reader, dst string) error {
+	gzipReader, err := gzip.NewReader(src)
+	if err != nil {
+		return err
+	} else {
+		defer gzipReader.Close()
+	}
+
+	limits := cache.Limits.withDefaults()
+	entries := 0
+	totalSize := int64(0)
+	names := map[string]bool{}
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		name, err := entryName(header)
+		if err != nil {
+			return err
+		}
+		if names[name] {
+			return &UnsafeArchiveError{Name: header.Name, Reason: "duplicate entry"}
+		} else {
+			names[name] = true
+		}
+
+		entries += 1
+		if entries > limits.MaxEntries {
+			return &UnsafeArchiveError{Name: header.Name, Reason: fmt.Sprintf("archive contains more than %d entries", limits.MaxEntries)}
+		}
+
+		if header.Size > limits.MaxFileSize {
+			return &UnsafeArchiveError{Name: header.Name, Reason: fmt.Sprintf("file is larger than %d bytes", limits.MaxFileSize)}
+		}
+
+		totalSize += header.Size
+		if totalSize > limits.MaxTotalSize {
+			return &UnsafeArchiveError{Name: header.Name, Reason: fmt.Sprintf("archive is larger than %d bytes", limits.MaxTotalSize)}
+		}
+
+		if err := writeEntry(filepath.Join(dst, name), tarReader, header.Size); err != nil {
+			return err
+		}
+	}
+
+	if entries == 0 {
+		return errors.New("failed to extract release asset(s) - archive is empty")
+	} else {
+		return nil
+	}
+}
+
+// entryName validates a tar entry and returns the name of the file to create. Only
+// regular files at the root of the archive are allowed, which rules out path
+// traversal (e.g. '../' or absolute paths) as well as symlinks and devices.
+func entryName(header *tar.Header) (string, error) {
+	if header.Typeflag != tar.TypeReg {
+		return "", &UnsafeArchiveError{Name: header.Name, Reason: "not a regular file"}
+	}
+
+	name := filepath.Clean(filepath.FromSlash(header.Name))
+	if !filepath.IsLocal(name) || strings.ContainsRune(name, filepath.Separator) {
+		return "", &UnsafeArchiveError{Name: header.Name, Reason: "path must point to a file at the root of the archive"}
+	} else {
+		return name, nil
+	}
+}
+
+func writeEntry(pth string, src io.Reader, size int64) error {
+	out, err := os.OpenFile(pth, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	n, err := io.Copy(out, src)
+	if err != nil {
+		return errors.Join(err, out.Close())
+	}
+	if n != size {
+		return errors.Join(fmt.Errorf("failed to extract '%s' - expected %d bytes but got %d", filepath.Base(pth), size, n), out.Close())
+	}
+
+	return out.Close()
+}
@@ -0,0 +1,150 @@
+package plgn
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testEntry struct {
+	header *tar.Header
+	data   []byte
+}
+
+func regular(name string, data string) testEntry {
+	return testEntry{
+		header: &tar.Header{Name: name, Typeflag: tar.TypeReg, Mode: 0755, Size: int64(len(data))},
+		data:   []byte(data),
+	}
+}
+
+func makeArchive(t *testing.T, entries ...testEntry) *bytes.Buffer {
+	buf := new(bytes.Buffer)
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+
+	for _, entry := range entries {
+		if err := tarWriter.WriteHeader(entry.header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tarWriter.Write(entry.data); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := tarWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gzipWriter.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf
+}
+
+// assertClean checks that a failed extraction did not leave anything behind (i.e.
+// neither the destination nor any temporary directories)
+func assertClean(t *testing.T, cache *PluginCache) {
+	entries, err := os.ReadDir(cache.Dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		t.Errorf("unexpected entry in cache dir: %s", entry.Name())
+	}
+}
+
+func TestUnpack(t *testing.T) {
+	cache := &PluginCache{Dir: t.TempDir()}
+	dst := filepath.Join(cache.Dir, "eth")
+
+	pluginPaths, err := cache.unpack(makeArchive(t, regular("eth", "binary"), regular("./beacon", "other")), dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(pluginPaths) != 2 {
+		t.Fatalf("expected 2 files but got %d", len(pluginPaths))
+	}
+
+	data, err := os.ReadFile(filepath.Join(dst, "eth"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "binary" {
+		t.Fatalf("unexpected file contents: %s", string(data))
+	}
+
+	// NOTE: the cache is reused on subsequent calls
+	cachedPaths, err := cache.list(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cachedPaths) != len(pluginPaths) {
+		t.Fatalf("expected %d cached files but got %d", len(pluginPaths), len(cachedPaths))
+	}
+}
+
+func TestUnpackRejectsUnsafeArchives(t *testing.T) {
+	symlink := testEntry{header: &tar.Header{Name: "eth", Typeflag: tar.TypeSymlink, Linkname: "/etc/passwd"}}
+	directory := testEntry{header: &tar.Header{Name: "bin/", Typeflag: tar.TypeDir, Mode: 0755}}
+
+	tests := map[string]struct {
+		limits  ArchiveLimits
+		entries []testEntry
+	}{
+		"parent directory":  {entries: []testEntry{regular("../evil", "x")}},
+		"nested traversal":  {entries: []testEntry{regular("a/../../evil", "x")}},
+		"absolute path":     {entries: []testEntry{regular("/tmp/evil", "x")}},
+		"subdirectory":      {entries: []testEntry{regular("bin/eth", "x")}},
+		"symlink":           {entries: []testEntry{symlink}},
+		"directory":         {entries: []testEntry{directory}},
+		"duplicate entries": {entries: []testEntry{regular("eth", "a"), regular("eth", "b")}},
+		"file too large":    {limits: ArchiveLimits{MaxFileSize: 4}, entries: []testEntry{regular("eth", "12345")}},
+		"archive too large": {limits: ArchiveLimits{MaxTotalSize: 8}, entries: []testEntry{regular("eth", "12345"), regular("beacon", "12345")}},
+		"too many entries":  {limits: ArchiveLimits{MaxEntries: 1}, entries: []testEntry{regular("eth", "a"), regular("beacon", "b")}},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := &PluginCache{Dir: t.TempDir(), Limits: test.limits}
+
+			// NOTE: a valid file comes first to make sure that partially extracted
+			// archives are cleaned up
+			entries := append([]testEntry{regular("first", "ok")}, test.entries...)
+
+			_, err := cache.unpack(makeArchive(t, entries...), filepath.Join(cache.Dir, "eth"))
+			if !errors.Is(err, &UnsafeArchiveError{}) {
+				t.Fatalf("expected an unsafe archive error but got: %v", err)
+			}
+
+			assertClean(t, cache)
+		})
+	}
+}
+
+func TestUnpackCleansUpOnFailure(t *testing.T) {
+	archive := makeArchive(t, regular("eth", "binary"), regular("beacon", "binary"))
+
+	tests := map[string][]byte{
+		"truncated archive": archive.Bytes()[:archive.Len()/2],
+		"not an archive":    []byte("not a gzipped tarball"),
+		"empty archive":     makeArchive(t).Bytes(),
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := &PluginCache{Dir: t.TempDir()}
+
+			if _, err := cache.unpack(bytes.NewReader(data), filepath.Join(cache.Dir, "eth")); err == nil {
+				t.Fatal("expected an error")
+			}
+
+			assertClean(t, cache)
+		})
+	}
+}
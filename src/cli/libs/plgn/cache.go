@@ -1,37 +1,135 @@
 package plgn
 
 import (
-	"archive/tar"
-	"compress/gzip"
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 
-	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/dirs"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
 )
 
-type PluginCache struct {
-	Dir string
+const (
+	CHECKSUMS_ASSET_NAME = "checksums.txt"
+	SIGNATURE_ASSET_NAME = CHECKSUMS_ASSET_NAME + ".minisig"
+)
+
+const (
+	SOURCE_FILE_EXTENSION = ".source"
+	SOURCE_CACHE          = "cache"
+)
+
+type (
+	PluginCache struct {
+		Dir    string
+		Limits ArchiveLimits
+	}
+
+	DownloadOptions struct {
+		// PublicKey is used to verify the signature of the release's checksums file. If
+		// nil, then only the checksums are verified.
+		PublicKey *integrity.PublicKey
+
+		// Registries are searched in order. If empty, the default registry is used.
+		Registries []*registry.Entry
+
+		// AllowUnverified allows installing plugins from releases that do not have a
+		// checksums file (e.g. releases that were published before checksums were
+		// added). Releases that have one are always verified, and it is still required
+		// if a public key is configured.
+		AllowUnverified bool
+	}
+)
+
+var Cache = PluginCache{Dir: dirs.PluginsCache, Limits: DefaultArchiveLimits()}
+
+// NewDownloadOptions creates download options from a public key which can either be
+// a base64 encoded minisign key or the path to a key file. An empty string means
+// that signatures are only verified for registries that have their own public key.
+func NewDownloadOptions(publicKey string, registries []*registry.Entry) (DownloadOptions, error) {
+	opts := DownloadOptions{Registries: registries}
+	if publicKey == "" {
+		return opts, nil
+	}
+
+	pk, err := integrity.LoadPublicKey(publicKey)
+	if err != nil {
+		return opts, err
+	} else {
+		opts.PublicKey = pk
+		return opts, nil
+	}
 }
 
-var Cache = PluginCache{dirs.PluginsCache}
+// Download fetches and extracts the archive of a plugin version. If the reference
+// has no version, then the default version is downloaded. Archives are cached per
+// plugin version along with the registry that they were downloaded from:
+//
+//	<dir>/<plugin-id>/<version>/
+//	<dir>/<plugin-id>/<version>.source
+//
+// The returned source describes where the plugin came from (e.g. 'registry:github').
+func (cache *PluginCache) Download(ctx context.Context, ref Ref, opts DownloadOptions) ([]string, string, error) {
+	ref = ref.WithDefaultVersion()
+	cacheDir := filepath.Join(cache.Dir, filepath.Base(ref.ID), filepath.Base(ref.Version))
 
-func (cache *PluginCache) Download(ctx context.Context, pluginID string) ([]string, error) {
-	cacheDir := filepath.Join(cache.Dir, pluginID)
+	// NOTE: the lock makes sure that an archive is only downloaded once even if several
+	// processes need it at the same time - the others wait and then use the cached copy
+	cacheLock, err := lock(ctx, cache.Dir, filepath.Base(ref.String()))
+	if err != nil {
+		return []string{}, "", err
+	} else {
+		defer cacheLock.unlock()
+	}
 
-	entries, err := os.ReadDir(cacheDir)
+	// NOTE: archives are extracted into a temporary directory which is renamed once
+	// extraction succeeds, so an existing cache directory is always complete (even if
+	// a previous process was killed half-way through)
+	pluginPaths, err := cache.list(cacheDir)
 	if os.IsNotExist(err) {
-		res, err := core.GithubClient.DownloadReleaseAsset(ctx, core.VersionWithPrefix(), MakePluginReleaseAssetName(pluginID))
+		archive, registryName, err := cache.fetch(ctx, ref.Tag(), MakePluginReleaseAssetName(ref), opts)
+		if err != nil {
+			return []string{}, "", err
+		} else {
+			defer os.Remove(archive.Name())
+			defer archive.Close()
+		}
+
+		pluginPaths, err := cache.unpack(archive, cacheDir)
 		if err != nil {
-			return []string{}, err
+			return []string{}, "", err
+		}
+
+		source := RegistrySource(registryName)
+		if err := os.WriteFile(cacheDir+SOURCE_FILE_EXTENSION, []byte(source), 0644); err != nil {
+			return []string{}, "", err
 		} else {
-			defer res.Body.Close()
+			return pluginPaths, source, nil
 		}
-		return cache.unpack(res.Body, cacheDir)
 	}
+	if err != nil {
+		return []string{}, "", err
+	}
+
+	// NOTE: archives that were cached before sources were recorded have no source file
+	source, err := os.ReadFile(cacheDir + SOURCE_FILE_EXTENSION)
+	if os.IsNotExist(err) {
+		return pluginPaths, SOURCE_CACHE, nil
+	}
+	if err != nil {
+		return []string{}, "", err
+	}
+
+	return pluginPaths, string(source), nil
+}
+
+func (cache *PluginCache) list(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
 	if err != nil {
 		return []string{}, err
 	}
@@ -39,55 +137,142 @@ func (cache *PluginCache) Download(ctx context.Context, pluginID string) ([]stri
 	pluginPaths := []string{}
 	for _, entry := range entries {
 		if entry.Type().IsRegular() {
-			pluginPaths = append(pluginPaths, filepath.Join(cacheDir, entry.Name()))
+			pluginPaths = append(pluginPaths, filepath.Join(dir, entry.Name()))
 		}
 	}
 
 	return pluginPaths, nil
 }
 
-func (cache *PluginCache) unpack(src io.ReadCloser, dst string) ([]string, error) {
-	pluginPaths := []string{}
+// fetch searches the registries in order of priority and downloads the asset from
+// the first registry that has it. Only missing assets cause the search to move on to
+// the next registry - any other error (e.g. a checksum mismatch) is returned as is.
+func (cache *PluginCache) fetch(ctx context.Context, tag string, assetName string, opts DownloadOptions) (*os.File, string, error) {
+	registries := opts.Registries
+	if len(registries) == 0 {
+		registries = registry.Default()
+	}
 
-	gzipReader, err := gzip.NewReader(src)
-	if err != nil {
-		return []string{}, err
-	} else {
-		defer gzipReader.Close()
+	errs := []error{}
+	for _, entry := range registries {
+		archive, err := cache.downloadVerified(ctx, entry, tag, assetName, opts)
+		if errors.Is(err, &registry.AssetNotFoundError{}) {
+			errs = append(errs, fmt.Errorf("registry '%s': %w", entry.Name, err))
+			continue
+		}
+		if err != nil {
+			return nil, "", fmt.Errorf("registry '%s': %w", entry.Name, err)
+		} else {
+			return archive, entry.Name, nil
+		}
 	}
 
-	if err := os.MkdirAll(dst, os.ModePerm); err != nil {
-		return []string{}, err
+	return nil, "", fmt.Errorf("failed to find '%s' of release '%s' in any registry: %w", assetName, tag, errors.Join(errs...))
+}
+
+func RegistrySource(registryName string) string {
+	return "registry:" + registryName
+}
+
+func FileSource(filePath string) string {
+	if absPath, err := filepath.Abs(filePath); err == nil {
+		filePath = absPath
 	}
+	return "file:" + filePath
+}
 
-	tarReader := tar.NewReader(gzipReader)
-	for {
-		header, err := tarReader.Next()
-		if err == io.EOF {
-			break
-		}
+// downloadChecksums downloads the release's checksums file and, if a public key is
+// configured, verifies its signature. If the release does not have a checksums file
+// and unverified releases are allowed, then nil is returned.
+func (cache *PluginCache) downloadChecksums(ctx context.Context, entry *registry.Entry, tag string, opts DownloadOptions) (integrity.Checksums, error) {
+	publicKey := opts.PublicKey
+	if entry.PublicKey != nil {
+		publicKey = entry.PublicKey
+	}
+
+	data, err := readAsset(ctx, entry, tag, CHECKSUMS_ASSET_NAME)
+	if errors.Is(err, &registry.AssetNotFoundError{}) && opts.AllowUnverified && publicKey == nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if publicKey != nil {
+		sigData, err := readAsset(ctx, entry, tag, SIGNATURE_ASSET_NAME)
 		if err != nil {
-			return []string{}, err
+			return nil, err
 		}
 
-		pth := filepath.Join(dst, header.Name)
-		if header.Typeflag != tar.TypeReg {
-			return []string{}, fmt.Errorf("failed to extract release asset(s) - not a regular file: %s", header.Name)
+		sig, err := integrity.ParseSignature(string(sigData))
+		if err != nil {
+			return nil, err
 		}
 
-		out, err := os.Create(pth)
-		if err != nil {
-			return []string{}, err
-		} else {
-			defer out.Close()
+		if err := publicKey.Verify(data, sig); err != nil {
+			return nil, err
 		}
+	}
 
-		if _, err := io.Copy(out, tarReader); err != nil {
-			return []string{}, err
-		} else {
-			pluginPaths = append(pluginPaths, pth)
+	return integrity.ParseChecksums(bytes.NewReader(data))
+}
+
+// downloadVerified downloads a release asset into a temporary file and verifies it
+// against the release's checksums file. The caller is responsible for closing and
+// removing the file.
+func (cache *PluginCache) downloadVerified(ctx context.Context, entry *registry.Entry, tag string, assetName string, opts DownloadOptions) (*os.File, error) {
+	checksums, err := cache.downloadChecksums(ctx, entry, tag, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := entry.Open(ctx, tag, assetName)
+	if err != nil {
+		return nil, err
+	} else {
+		defer src.Close()
+	}
+
+	if err := os.MkdirAll(cache.Dir, os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	archive, err := os.CreateTemp(cache.Dir, assetName+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := func(err error) (*os.File, error) {
+		return nil, errors.Join(err, archive.Close(), os.Remove(archive.Name()))
+	}
+
+	digest, err := integrity.Digest(io.TeeReader(src, archive))
+	if err != nil {
+		return cleanup(err)
+	}
+
+	// NOTE: checksums are only nil if the release does not have a checksums file and
+	// unverified releases are allowed
+	if checksums != nil {
+		if err := checksums.Verify(assetName, digest); err != nil {
+			return cleanup(err)
 		}
 	}
 
-	return pluginPaths, nil
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return cleanup(err)
+	} else {
+		return archive, nil
+	}
+}
+
+func readAsset(ctx context.Context, entry *registry.Entry, tag string, assetName string) ([]byte, error) {
+	src, err := entry.Open(ctx, tag, assetName)
+	if err != nil {
+		return nil, err
+	} else {
+		defer src.Close()
+	}
+
+	return io.ReadAll(src)
 }
@@ -0,0 +1,66 @@
+package plgn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+)
+
+func TestDownloadUnverified(t *testing.T) {
+	ctx := context.Background()
+	ref := Ref{ID: "eth", Version: "0.1.0"}
+	binary := "#!/bin/sh\nexit 1\n"
+
+	// NOTE: older releases only contain the plugin archives
+	registryDir := t.TempDir()
+	releaseDir := filepath.Join(registryDir, ref.Tag())
+	if err := os.MkdirAll(releaseDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(releaseDir, MakePluginReleaseAssetName(ref)), makeArchive(t, regular(ref.ID, binary)).Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := &registry.Entry{Registry: registry.NewLocalRegistry(registryDir), Name: "mirror"}
+	pk, sign := newSigner(t)
+
+	testCases := []struct {
+		name string
+		opts DownloadOptions
+		ok   bool
+	}{
+		{name: "checksums required", opts: DownloadOptions{Registries: []*registry.Entry{entry}}, ok: false},
+		{name: "unverified allowed", opts: DownloadOptions{Registries: []*registry.Entry{entry}, AllowUnverified: true}, ok: true},
+		{name: "public key configured", opts: DownloadOptions{Registries: []*registry.Entry{entry}, AllowUnverified: true, PublicKey: pk}, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			cache := &PluginCache{Dir: t.TempDir(), Limits: DefaultArchiveLimits()}
+			_, _, err := cache.Download(ctx, ref, tc.opts)
+			if tc.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !tc.ok && !errors.Is(err, &registry.AssetNotFoundError{}) {
+				t.Fatalf("expected a missing checksums file error but got: %v", err)
+			}
+		})
+	}
+
+	// NOTE: releases that have a checksums file are always verified
+	publishRelease(t, registryDir, ref, binary, sign)
+	if err := os.WriteFile(filepath.Join(releaseDir, MakePluginReleaseAssetName(ref)), makeArchive(t, regular(ref.ID, "#!/bin/sh\nexit 2\n")).Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	cache := &PluginCache{Dir: t.TempDir(), Limits: DefaultArchiveLimits()}
+	opts := DownloadOptions{Registries: []*registry.Entry{entry}, AllowUnverified: true}
+	if _, _, err := cache.Download(ctx, ref, opts); !errors.Is(err, &integrity.ChecksumMismatchError{}) {
+		t.Fatalf("expected a checksum mismatch but got: %v", err)
+	}
+}
@@ -22,3 +22,51 @@ func (e *PluginNotFoundError) Is(target error) bool {
 	_, ok := target.(*PluginNotFoundError)
 	return ok
 }
+
+type UnsafeArchiveError struct {
+	Name   string
+	Reason string
+}
+
+func (e *UnsafeArchiveError) Error() string {
+	return fmt.Sprintf("refusing to extract archive entry '%s': %s", e.Name, e.Reason)
+}
+
+func (e *UnsafeArchiveError) Is(target error) bool {
+	_, ok := target.(*UnsafeArchiveError)
+	return ok
+}
+
+type ReloadNotSupportedError struct{}
+
+var ErrReloadNotSupported = &ReloadNotSupportedError{}
+
+func (e *ReloadNotSupportedError) Error() string {
+	return "plugin does not support reloading its config"
+}
+
+func (e *ReloadNotSupportedError) Is(target error) bool {
+	_, ok := target.(*ReloadNotSupportedError)
+	return ok
+}
+
+// NotFromRegistryError is returned when a plugin is verified against its release but
+// it was not installed from a registry (e.g. it was installed from a local file, or
+// before install records were introduced).
+type NotFromRegistryError struct {
+	Ref    string
+	Source string
+}
+
+func (e *NotFromRegistryError) Error() string {
+	if e.Source == "" {
+		return fmt.Sprintf("plugin '%s' has no install record - reinstall it to verify it against its release", e.Ref)
+	} else {
+		return fmt.Sprintf("plugin '%s' was not installed from a registry (source: '%s') - it can only be verified against its recorded checksum", e.Ref, e.Source)
+	}
+}
+
+func (e *NotFromRegistryError) Is(target error) bool {
+	_, ok := target.(*NotFromRegistryError)
+	return ok
+}
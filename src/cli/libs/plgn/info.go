@@ -0,0 +1,172 @@
+package plgn
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+)
+
+// INSTALL_FILE_NAME is the name of the file (next to each installed plugin binary)
+// that records where the plugin was installed from and when.
+const INSTALL_FILE_NAME = "install.json"
+
+type (
+	InstallRecord struct {
+		Source      string    `json:"source"`
+		InstalledAt time.Time `json:"installedAt"`
+	}
+
+	// PluginInfo describes an installed plugin version.
+	PluginInfo struct {
+		ID       string              `json:"id"`
+		Version  string              `json:"version"`
+		Path     string              `json:"path"`
+		Size     int64               `json:"size"`
+		Checksum string              `json:"checksum"`
+		Verified bool                `json:"verified"`
+		Install  *InstallRecord      `json:"install,omitempty"`
+		Manifest *handshake.Manifest `json:"manifest,omitempty"`
+
+		// Problems lists anything that looks wrong with the installation (e.g. the
+		// binary no longer matches the checksum that was recorded at install time).
+		Problems []string `json:"problems,omitempty"`
+	}
+
+	// PluginSummary is the part of PluginInfo that is cheap to collect (i.e. without
+	// hashing the plugin binary), which is what commands that list plugins print.
+	PluginSummary struct {
+		ID       string              `json:"id"`
+		Version  string              `json:"version"`
+		Path     string              `json:"path"`
+		Source   string              `json:"source,omitempty"`
+		Manifest *handshake.Manifest `json:"manifest,omitempty"`
+	}
+)
+
+func writeInstallRecord(dir string, record *InstallRecord) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	} else {
+		return os.WriteFile(filepath.Join(dir, INSTALL_FILE_NAME), data, 0644)
+	}
+}
+
+// readInstallRecord returns nil if the plugin was installed before install records
+// were introduced.
+func readInstallRecord(dir string) (*InstallRecord, error) {
+	data, err := os.ReadFile(filepath.Join(dir, INSTALL_FILE_NAME))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var record InstallRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, err
+	} else {
+		return &record, nil
+	}
+}
+
+// Info collects everything that is known about an installed plugin. Problems with
+// the installation are reported in the result rather than as an error, so that as
+// much information as possible is shown.
+func (store *PluginStore) Info(ref Ref) (*PluginInfo, error) {
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	stat, err := os.Stat(pluginPath)
+	if err != nil {
+		return nil, err
+	}
+
+	digest, err := integrity.DigestFile(pluginPath)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PluginInfo{
+		ID:       ref.ID,
+		Version:  filepath.Base(filepath.Dir(pluginPath)),
+		Path:     pluginPath,
+		Size:     stat.Size(),
+		Checksum: "sha256:" + digest,
+		Problems: []string{},
+	}
+
+	if err := store.Verify(Ref{ID: info.ID, Version: info.Version}); err != nil {
+		info.Problems = append(info.Problems, err.Error())
+	} else {
+		info.Verified = true
+	}
+
+	if record, err := readInstallRecord(filepath.Dir(pluginPath)); err != nil {
+		info.Problems = append(info.Problems, err.Error())
+	} else {
+		info.Install = record
+	}
+
+	if manifest, err := store.Manifest(Ref{ID: info.ID, Version: info.Version}); err != nil {
+		info.Problems = append(info.Problems, err.Error())
+	} else {
+		info.Manifest = manifest
+	}
+
+	return info, nil
+}
+
+// Summary describes an installed plugin version without checking its integrity.
+func (store *PluginStore) Summary(ref Ref) (*PluginSummary, error) {
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &PluginSummary{
+		ID:      ref.ID,
+		Version: filepath.Base(filepath.Dir(pluginPath)),
+		Path:    pluginPath,
+	}
+
+	if record, err := readInstallRecord(filepath.Dir(pluginPath)); err != nil {
+		return nil, err
+	} else if record != nil {
+		summary.Source = record.Source
+	}
+
+	if manifest, err := store.Manifest(ref); err != nil {
+		return nil, err
+	} else {
+		summary.Manifest = manifest
+	}
+
+	return summary, nil
+}
+
+// Summaries describes every installed plugin version.
+func (store *PluginStore) Summaries() ([]*PluginSummary, error) {
+	refs, err := store.Refs()
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]*PluginSummary, len(refs))
+	for i, ref := range refs {
+		if summary, err := store.Summary(ref); err != nil {
+			return nil, err
+		} else {
+			summaries[i] = summary
+		}
+	}
+
+	return summaries, nil
+}
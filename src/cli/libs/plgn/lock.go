@@ -0,0 +1,63 @@
+package plgn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+const (
+	// LOCKS_DIR_NAME is the directory (inside the store and cache directories) that
+	// holds the lock files. The lock files are kept apart from the plugin directories
+	// so that removing a plugin never removes a lock that another process is waiting on.
+	LOCKS_DIR_NAME     = ".locks"
+	LOCK_POLL_INTERVAL = time.Millisecond * 50
+)
+
+// fileLock is an exclusive advisory lock (i.e. flock) on a file. It synchronizes CLI
+// processes as well as goroutines within the same process, since every lock opens
+// its own file description.
+type fileLock struct {
+	file *os.File
+}
+
+// lock blocks until the lock file at <dir>/.locks/<name>.lock can be locked or the
+// context is done.
+func lock(ctx context.Context, dir string, name string) (*fileLock, error) {
+	lockPath := filepath.Join(dir, LOCKS_DIR_NAME, name+".lock")
+	if err := os.MkdirAll(filepath.Dir(lockPath), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(lockPath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: a blocking flock cannot be interrupted, so the lock is polled instead to
+	// let the caller give up (e.g. on SIGINT)
+	ticker := time.NewTicker(LOCK_POLL_INTERVAL)
+	defer ticker.Stop()
+	for {
+		err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if err == nil {
+			return &fileLock{file: file}, nil
+		}
+		if !errors.Is(err, syscall.EWOULDBLOCK) && !errors.Is(err, syscall.EINTR) {
+			return nil, errors.Join(err, file.Close())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, errors.Join(ctx.Err(), file.Close())
+		case <-ticker.C:
+		}
+	}
+}
+
+func (l *fileLock) unlock() error {
+	return errors.Join(syscall.Flock(int(l.file.Fd()), syscall.LOCK_UN), l.file.Close())
+}
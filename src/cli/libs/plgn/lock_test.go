@@ -0,0 +1,185 @@
+package plgn
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+)
+
+const (
+	TEST_INSTALLER_ENV   = "CC_TEST_INSTALLER"
+	TEST_INSTALLERS      = 4
+	TEST_INSTALLS        = 8
+	TEST_PLUGIN_VERSION  = "1.2.0"
+	TEST_PLUGIN_CONTENTS = "#!/bin/sh\nexit 1\n"
+)
+
+// countingRegistry counts how often each asset was opened
+type countingRegistry struct {
+	registry.Registry
+	opened sync.Map
+}
+
+func (r *countingRegistry) Open(ctx context.Context, tag string, name string) (io.ReadCloser, error) {
+	count, _ := r.opened.LoadOrStore(name, new(atomic.Int64))
+	count.(*atomic.Int64).Add(1)
+	return r.Registry.Open(ctx, tag, name)
+}
+
+// makeRegistry creates a local registry with a single release that contains an
+// archive of the 'eth' plugin
+func makeRegistry(t *testing.T) string {
+	ref := Ref{ID: "eth", Version: TEST_PLUGIN_VERSION}
+	dir := filepath.Join(t.TempDir(), ref.Tag())
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	archive := makeArchive(t, regular("eth", TEST_PLUGIN_CONTENTS)).Bytes()
+	digest, err := integrity.Digest(bytes.NewReader(archive))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assetName := MakePluginReleaseAssetName(ref)
+	if err := os.WriteFile(filepath.Join(dir, assetName), archive, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, CHECKSUMS_ASSET_NAME), []byte(fmt.Sprintf("%s  %s\n", digest, assetName)), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return filepath.Dir(dir)
+}
+
+// installConcurrently downloads and installs the same plugin from several goroutines
+func installConcurrently(store *PluginStore, cache *PluginCache, reg registry.Registry) error {
+	opts := DownloadOptions{Registries: []*registry.Entry{{Registry: reg, Name: "test"}}}
+	ref := Ref{ID: "eth", Version: TEST_PLUGIN_VERSION}
+	ctx := context.Background()
+
+	errs := make(chan error, TEST_INSTALLS)
+	wg := new(sync.WaitGroup)
+	for range TEST_INSTALLS {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			pluginPaths, source, err := cache.Download(ctx, ref, opts)
+			if err != nil {
+				errs <- err
+			} else {
+				errs <- store.Install(ctx, ref.Version, source, pluginPaths)
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// assertInstalled checks that exactly one intact copy of the plugin is installed and
+// that no temporary files were left behind
+func assertInstalled(t *testing.T, store *PluginStore, cache *PluginCache) {
+	refs, err := store.Refs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(refs, []Ref{{ID: "eth", Version: TEST_PLUGIN_VERSION}}) {
+		t.Fatalf("unexpected installed plugins: %v", refs)
+	}
+	if err := store.Verify(refs[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dir := range []string{filepath.Join(store.Dir, "eth"), filepath.Join(cache.Dir, "eth"), cache.Dir} {
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, entry := range entries {
+			if strings.Contains(entry.Name(), "tmp") || strings.HasPrefix(entry.Name(), ".install-") {
+				t.Errorf("unexpected temporary entry in %s: %s", dir, entry.Name())
+			}
+		}
+	}
+}
+
+func TestConcurrentInstalls(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+	cache := &PluginCache{Dir: t.TempDir()}
+	reg := &countingRegistry{Registry: registry.NewLocalRegistry(makeRegistry(t))}
+
+	if err := installConcurrently(store, cache, reg); err != nil {
+		t.Fatal(err)
+	}
+
+	assertInstalled(t, store, cache)
+
+	// NOTE: the archive must only be downloaded once - every other installer waits for
+	// the lock and then uses the cached copy
+	count, ok := reg.opened.Load(MakePluginReleaseAssetName(Ref{ID: "eth", Version: TEST_PLUGIN_VERSION}))
+	if !ok || count.(*atomic.Int64).Load() != 1 {
+		t.Fatalf("expected the archive to be downloaded exactly once")
+	}
+}
+
+// TestInstallerProcess is started as a subprocess by TestConcurrentInstallers
+func TestInstallerProcess(t *testing.T) {
+	dirs := strings.Split(os.Getenv(TEST_INSTALLER_ENV), string(os.PathListSeparator))
+	if len(dirs) != 3 {
+		t.Skip("only runs as a subprocess of TestConcurrentInstallers")
+	}
+
+	store := &PluginStore{Dir: dirs[0]}
+	cache := &PluginCache{Dir: dirs[1]}
+	if err := installConcurrently(store, cache, registry.NewLocalRegistry(dirs[2])); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestConcurrentInstallers(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+	cache := &PluginCache{Dir: t.TempDir()}
+	env := fmt.Sprintf("%s=%s", TEST_INSTALLER_ENV, strings.Join([]string{store.Dir, cache.Dir, makeRegistry(t)}, string(os.PathListSeparator)))
+
+	cmds := make([]*exec.Cmd, TEST_INSTALLERS)
+	outputs := make([]*bytes.Buffer, TEST_INSTALLERS)
+	for i := range cmds {
+		outputs[i] = new(bytes.Buffer)
+		cmds[i] = exec.Command(os.Args[0], "-test.run=^TestInstallerProcess$", "-test.count=1")
+		cmds[i].Env = append(os.Environ(), env)
+		cmds[i].Stdout = outputs[i]
+		cmds[i].Stderr = outputs[i]
+		if err := cmds[i].Start(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for i, cmd := range cmds {
+		if err := cmd.Wait(); err != nil {
+			t.Fatalf("installer %d failed: %v\n%s", i, err, outputs[i].String())
+		}
+	}
+
+	assertInstalled(t, store, cache)
+}
@@ -4,47 +4,80 @@ import (
 	"fmt"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
-
-	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
 )
 
-func IsPluginReleaseAssetName(name string) bool {
-	return strings.HasSuffix(
-		name,
-		fmt.Sprintf(
-			"-plugin_%s_%s_%s.tar.gz",
-			core.VersionWithoutPrefix(),
-			runtime.GOOS,
-			runtime.GOARCH,
-		),
-	)
+// IsPluginReleaseAssetName reports whether an asset is a plugin archive of the given
+// version that was built for the current platform.
+func IsPluginReleaseAssetName(name string, version string) bool {
+	ref, ok := ParsePluginReleaseAssetName(name)
+	return ok && ref.Version == version
 }
 
-func MakePluginReleaseAssetName(pluginID string) string {
+func MakePluginReleaseAssetName(ref Ref) string {
 	return fmt.Sprintf(
 		"%s-plugin_%s_%s_%s.tar.gz",
-		pluginID,
-		core.VersionWithoutPrefix(),
+		ref.ID,
+		ref.Version,
 		runtime.GOOS,
 		runtime.GOARCH,
 	)
 }
 
+// ParsePluginReleaseAssetName extracts the plugin ID and version from the name of a
+// plugin archive that was built for the current platform.
+func ParsePluginReleaseAssetName(name string) (Ref, bool) {
+	rest, found := strings.CutSuffix(name, fmt.Sprintf("_%s_%s.tar.gz", runtime.GOOS, runtime.GOARCH))
+	if !found {
+		return Ref{}, false
+	}
+
+	id, version, found := strings.Cut(rest, "-plugin_")
+	if !found || id == "" || !IsValidVersion(version) {
+		return Ref{}, false
+	} else {
+		return Ref{ID: id, Version: version}, true
+	}
+}
+
 func ID(filePath string) string {
 	return filepath.Base(filePath)
 }
 
+// IDs returns the references (i.e. '<plugin-id>@<version>') of all installed plugins.
 func IDs() ([]string, error) {
-	pluginPaths, err := Store.GetPaths()
+	refs, err := Store.Refs()
+	if err != nil {
+		return []string{}, err
+	}
+
+	pluginIDs := make([]string, len(refs))
+	for i, ref := range refs {
+		pluginIDs[i] = ref.String()
+	}
+
+	return pluginIDs, nil
+}
+
+// BuiltinIDs are the IDs of the plugins that are released with the CLI.
+var BuiltinIDs = []string{"beacon", "eth", "flow", "solana", "substrate"}
+
+// KnownIDs returns the IDs of the built-in plugins along with the IDs of any other
+// plugins that are installed (e.g. from a private registry).
+func KnownIDs() ([]string, error) {
+	refs, err := Store.Refs()
 	if err != nil {
 		return []string{}, err
 	}
 
-	pluginIDs := make([]string, len(pluginPaths))
-	for i, p := range pluginPaths {
-		pluginIDs[i] = ID(p)
+	pluginIDs := slices.Clone(BuiltinIDs)
+	for _, ref := range refs {
+		if !slices.Contains(pluginIDs, ref.ID) {
+			pluginIDs = append(pluginIDs, ref.ID)
+		}
 	}
 
+	slices.Sort(pluginIDs)
 	return pluginIDs, nil
 }
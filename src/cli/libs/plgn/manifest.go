@@ -0,0 +1,166 @@
+package plgn
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+)
+
+const (
+	// MANIFEST_FILE_NAME is the name of the file (next to each installed plugin binary)
+	// that holds the manifest reported by the plugin at install time.
+	MANIFEST_FILE_NAME = "manifest.json"
+	MANIFEST_TIMEOUT   = time.Second * 10
+)
+
+// queryManifest runs a plugin binary with the manifest argument and parses its output.
+// Plugins that were built before manifests were introduced wait for a handshake
+// request instead, so they exit with an error (their stdin is empty) without printing
+// anything, in which case nil is returned. Nil is also returned for binaries that were
+// built for another platform, since they cannot be run to report a manifest. Any other
+// failure (e.g. a timeout, a crash or a malformed manifest) is returned as an error.
+func queryManifest(pluginPath string) (*handshake.Manifest, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), MANIFEST_TIMEOUT)
+	defer cancel()
+
+	stdout := new(bytes.Buffer)
+	cmd := exec.CommandContext(ctx, pluginPath, handshake.MANIFEST_ARG)
+	cmd.Stdout = stdout
+
+	var exitErr *exec.ExitError
+	err := cmd.Run()
+	if errors.As(err, &exitErr) && exitErr.Exited() && ctx.Err() == nil && stdout.Len() == 0 {
+		return nil, nil
+	}
+	if errors.Is(err, syscall.ENOEXEC) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query the plugin's manifest: %w", err)
+	} else {
+		return handshake.ReadManifest(stdout)
+	}
+}
+
+// FileRef returns the reference that a local plugin binary is installed under. If
+// the plugin reports a manifest, then the ID and version in its manifest are used (so
+// the binary can have any name), and a version that is given must match the one in
+// the manifest. Otherwise, the ID is the name of the binary and the version defaults
+// to the CLI version.
+func FileRef(filePath string, version string) (Ref, error) {
+	if version != "" {
+		if v, err := NormalizeVersion(version); err != nil {
+			return Ref{}, err
+		} else {
+			version = v
+		}
+	}
+
+	manifest, err := queryManifest(filePath)
+	if err != nil {
+		return Ref{}, fmt.Errorf("plugin '%s': %w", filePath, err)
+	}
+
+	if manifest == nil {
+		ref := Ref{ID: ID(filePath), Version: version}
+		if ref.Version == "" {
+			ref.Version = DefaultVersion()
+		}
+		return ref, nil
+	}
+
+	if manifest.ID != filepath.Base(manifest.ID) || manifest.ID == "." || manifest.ID == ".." {
+		return Ref{}, fmt.Errorf("plugin '%s' reports an invalid ID in its manifest: '%s'", filePath, manifest.ID)
+	}
+
+	manifestVersion, err := NormalizeVersion(manifest.Version)
+	if err != nil {
+		return Ref{}, fmt.Errorf("plugin '%s' reports an invalid version in its manifest: %w", filePath, err)
+	}
+	if version != "" && version != manifestVersion {
+		return Ref{}, fmt.Errorf("plugin '%s' reports version '%s' in its manifest, which does not match the requested version '%s'", filePath, manifestVersion, version)
+	}
+
+	return Ref{ID: manifest.ID, Version: manifestVersion}, nil
+}
+
+// recordManifest stores the manifest of a newly installed plugin next to its binary.
+// Plugins without a manifest are still installed, but the manifest of a plugin must
+// match the ID and version that it is installed under.
+func (store *PluginStore) recordManifest(ref Ref, pluginPath string) error {
+	manifest, err := queryManifest(pluginPath)
+	if err != nil {
+		return fmt.Errorf("plugin '%s': %w", ref, err)
+	}
+
+	// NOTE: a plugin that does not support manifests is installed without one
+	if manifest == nil {
+		return nil
+	}
+
+	if manifest.ID != ref.ID {
+		return fmt.Errorf("plugin '%s' reports a different ID in its manifest: '%s'", ref, manifest.ID)
+	}
+	if version, err := NormalizeVersion(manifest.Version); err != nil || version != ref.Version {
+		return fmt.Errorf("plugin '%s' reports a different version in its manifest: '%s'", ref, manifest.Version)
+	}
+
+	file, err := os.Create(filepath.Join(filepath.Dir(pluginPath), MANIFEST_FILE_NAME))
+	if err != nil {
+		return err
+	} else {
+		defer file.Close()
+	}
+
+	return handshake.WriteManifest(file, manifest)
+}
+
+// Manifest returns the manifest of an installed plugin. If the plugin did not report
+// a manifest when it was installed, then nil is returned.
+func (store *PluginStore) Manifest(ref Ref) (*handshake.Manifest, error) {
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filepath.Join(filepath.Dir(pluginPath), MANIFEST_FILE_NAME))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	} else {
+		defer file.Close()
+	}
+
+	return handshake.ReadManifest(file)
+}
+
+// Validate checks a chain config against the manifest of the installed plugin that
+// will serve it. Plugins without a manifest are not validated.
+func (store *PluginStore) Validate(conf *config.ChainConfig) error {
+	ref, err := RefFromConfig(conf.Plugin)
+	if err != nil {
+		return err
+	}
+
+	manifest, err := store.Manifest(ref)
+	if err != nil {
+		return err
+	}
+
+	if manifest == nil {
+		return nil
+	} else {
+		return manifest.Validate(conf)
+	}
+}
@@ -0,0 +1,172 @@
+package plgn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+// writeScript creates a fake plugin which prints the given manifest
+func writeScript(t *testing.T, manifest string) string {
+	pluginPath := filepath.Join(t.TempDir(), "eth")
+	script := fmt.Sprintf("#!/bin/sh\n[ \"$1\" = manifest ] || exit 1\ncat <<'EOF'\n%s\nEOF\n", manifest)
+	if err := os.WriteFile(pluginPath, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return pluginPath
+}
+
+func TestManifest(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+
+	pluginPath := writeScript(t, `{"id":"eth","version":"1.2.0","protocolVersion":1,"chains":["evm"],"conn":[["conn.wss","conn.rpc"]],"finality":["finalized"]}`)
+	if err := store.Install(context.Background(), "1.2.0", FileSource(pluginPath), []string{pluginPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	manifest, err := store.Manifest(Ref{ID: "eth"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if manifest == nil || manifest.ID != "eth" || len(manifest.Conn) != 1 {
+		t.Fatalf("unexpected manifest: %+v", manifest)
+	}
+
+	valid := []*config.ChainConfig{
+		{Plugin: &config.PluginConfig{ID: "eth"}, Conn: &config.ConnectionConfg{Wss: "wss://localhost"}},
+		{Plugin: &config.PluginConfig{ID: "eth"}, Conn: &config.ConnectionConfg{Rpc: "/tmp/geth.ipc"}, Finality: "finalized"},
+	}
+	for _, conf := range valid {
+		if err := store.Validate(conf); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	invalid := []*config.ChainConfig{
+		{Plugin: &config.PluginConfig{ID: "eth"}, Conn: &config.ConnectionConfg{}},
+		{Plugin: &config.PluginConfig{ID: "eth"}, Conn: &config.ConnectionConfg{Wss: "wss://localhost"}, Finality: "safe"},
+	}
+	for _, conf := range invalid {
+		if err := store.Validate(conf); err == nil {
+			t.Fatalf("expected an error for config: %+v", conf)
+		}
+	}
+}
+
+func TestManifestMismatch(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+
+	for version, manifest := range map[string]string{
+		"1.2.0": `{"id":"beacon","version":"1.2.0"}`,
+		"1.3.0": `{"id":"eth","version":"1.2.0"}`,
+	} {
+		pluginPath := writeScript(t, manifest)
+		if err := store.Install(context.Background(), version, FileSource(pluginPath), []string{pluginPath}); err == nil {
+			t.Fatalf("expected an error for manifest: %s", manifest)
+		}
+	}
+
+	// NOTE: a rejected plugin must not be left behind in the store
+	if refs, err := store.Refs(); err != nil {
+		t.Fatal(err)
+	} else if len(refs) != 0 {
+		t.Fatalf("expected no plugins to be installed but got: %v", refs)
+	}
+}
+
+// writePlugin creates a fake plugin with the given name from a shell script
+func writePlugin(t *testing.T, name string, script string) string {
+	pluginPath := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(pluginPath, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return pluginPath
+}
+
+func TestManifestErrors(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+
+	// NOTE: plugins that do not support manifests exit with an error without printing
+	// anything, so they are installed without a manifest
+	legacyPath := writePlugin(t, "eth", "exit 1\n")
+	if err := store.Install(context.Background(), "1.2.0", FileSource(legacyPath), []string{legacyPath}); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err := store.Manifest(Ref{ID: "eth"}); err != nil || manifest != nil {
+		t.Fatalf("expected no manifest but got: %+v, %v", manifest, err)
+	}
+
+	testCases := []struct {
+		name   string
+		script string
+		err    string
+	}{
+		{name: "malformed manifest", script: "echo '{'\n", err: "failed to parse plugin manifest"},
+		{name: "error after printing", script: "echo '{\"id\":\"eth\"'\nexit 1\n", err: "exit status 1"},
+		{name: "crash", script: "kill -9 $$\n", err: "signal: killed"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			pluginPath := writePlugin(t, "eth", tc.script)
+			if err := store.Install(context.Background(), "1.3.0", FileSource(pluginPath), []string{pluginPath}); err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestFileRef(t *testing.T) {
+	// NOTE: the ID and version of a plugin with a manifest do not depend on the name of
+	// its binary or on the CLI version
+	renamedPath := filepath.Join(t.TempDir(), "eth-custom")
+	if err := os.Rename(writeScript(t, `{"id":"eth","version":"1.2.0"}`), renamedPath); err != nil {
+		t.Fatal(err)
+	}
+	legacyPath := writePlugin(t, "solana", "exit 1\n")
+
+	testCases := []struct {
+		name     string
+		path     string
+		version  string
+		expected Ref
+		err      string
+	}{
+		{name: "manifest", path: renamedPath, expected: Ref{ID: "eth", Version: "1.2.0"}},
+		{name: "manifest with the same version", path: renamedPath, version: "v1.2.0", expected: Ref{ID: "eth", Version: "1.2.0"}},
+		{name: "manifest with another version", path: renamedPath, version: "1.3.0", err: "does not match the requested version '1.3.0'"},
+		{name: "no manifest", path: legacyPath, expected: Ref{ID: "solana", Version: DefaultVersion()}},
+		{name: "no manifest with a version", path: legacyPath, version: "1.3.0", expected: Ref{ID: "solana", Version: "1.3.0"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ref, err := FileRef(tc.path, tc.version)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ref != tc.expected {
+				t.Fatalf("expected %v but got %v", tc.expected, ref)
+			}
+		})
+	}
+
+	store := &PluginStore{Dir: t.TempDir()}
+	if err := store.InstallFile(context.Background(), Ref{ID: "eth", Version: "1.2.0"}, renamedPath); err != nil {
+		t.Fatal(err)
+	}
+	if manifest, err := store.Manifest(Ref{ID: "eth", Version: "1.2.0"}); err != nil || manifest == nil {
+		t.Fatalf("expected a manifest but got: %+v, %v", manifest, err)
+	}
+}
@@ -0,0 +1,106 @@
+package plgn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Migrate installs the plugins from another plugins directory (e.g. the directory of
+// an older CLI version or of another home directory) into the store and returns the
+// plugins that were installed. Plugins that were installed with a version keep it,
+// and plugins that were installed before plugins were versioned (i.e. <dir>/<id>/bin)
+// are installed under the version in their manifest or, if they have no manifest,
+// under the given version. Plugins that are already installed are skipped.
+func (store *PluginStore) Migrate(ctx context.Context, srcDir string, version string) ([]Ref, error) {
+	version, err := NormalizeVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	srcAbs, err := filepath.Abs(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	dstAbs, err := filepath.Abs(store.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if srcAbs == dstAbs {
+		return nil, fmt.Errorf("cannot migrate plugins from '%s' into itself", srcDir)
+	}
+
+	entries, err := os.ReadDir(srcDir)
+	if err != nil {
+		return nil, err
+	}
+
+	migrated := []Ref{}
+	src := &PluginStore{Dir: srcDir}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		pluginDir := filepath.Join(srcDir, entry.Name())
+		if isFile(filepath.Join(pluginDir, "bin")) {
+			ref := Ref{ID: entry.Name(), Version: version}
+			if manifest, err := queryManifest(filepath.Join(pluginDir, "bin")); err == nil && manifest != nil {
+				if v, err := NormalizeVersion(manifest.Version); err == nil {
+					ref.Version = v
+				}
+			}
+
+			if ok, err := store.migrate(ctx, ref, pluginDir); err != nil {
+				return migrated, err
+			} else if ok {
+				migrated = append(migrated, ref)
+			}
+			continue
+		}
+
+		versions, err := src.Versions(entry.Name())
+		if err != nil {
+			return migrated, err
+		}
+
+		for _, v := range versions {
+			ref := Ref{ID: entry.Name(), Version: v}
+			if ok, err := store.migrate(ctx, ref, filepath.Join(pluginDir, v)); err != nil {
+				return migrated, err
+			} else if ok {
+				migrated = append(migrated, ref)
+			}
+		}
+	}
+
+	return migrated, nil
+}
+
+// migrate installs the plugin binary in the given directory unless the plugin is
+// already installed. The source that the plugin was originally installed from is
+// kept if it was recorded.
+func (store *PluginStore) migrate(ctx context.Context, ref Ref, dir string) (bool, error) {
+	if installed, err := store.IsInstalled(ref); err != nil || installed {
+		return false, err
+	}
+
+	pluginPath := filepath.Join(dir, "bin")
+	source := FileSource(pluginPath)
+	if record, err := readInstallRecord(dir); err == nil && record != nil && record.Source != "" {
+		source = record.Source
+	}
+
+	if err := store.install(ctx, ref, source, pluginPath); err != nil {
+		return false, err
+	} else {
+		return true, nil
+	}
+}
+
+func isFile(filePath string) bool {
+	info, err := os.Stat(filePath)
+	return err == nil && info.Mode().IsRegular()
+}
@@ -0,0 +1,64 @@
+package plgn
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestMigrate(t *testing.T) {
+	// NOTE: the source holds a plugin from before plugins were versioned along with a
+	// versioned plugin
+	src := &PluginStore{Dir: t.TempDir()}
+	legacyPath := filepath.Join(src.Dir, "eth", "bin")
+	if err := os.MkdirAll(filepath.Dir(legacyPath), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacyPath, []byte("eth"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	pluginPath := filepath.Join(t.TempDir(), "flow")
+	if err := os.WriteFile(pluginPath, []byte("flow"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := src.Install(context.Background(), "1.2.0", RegistrySource("default"), []string{pluginPath}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := &PluginStore{Dir: t.TempDir()}
+	refs, err := dst.Migrate(context.Background(), src.Dir, "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []Ref{{ID: "eth", Version: "1.0.0"}, {ID: "flow", Version: "1.2.0"}}
+	if !slices.Equal(refs, expected) {
+		t.Fatalf("expected %v to be migrated but got %v", expected, refs)
+	}
+
+	for _, ref := range expected {
+		if err := dst.Verify(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if info, err := dst.Info(Ref{ID: "flow", Version: "1.2.0"}); err != nil {
+		t.Fatal(err)
+	} else if info.Install == nil || info.Install.Source != RegistrySource("default") {
+		t.Fatalf("expected the install source to be kept but got: %+v", info.Install)
+	}
+
+	// NOTE: plugins that are already installed are skipped
+	if refs, err := dst.Migrate(context.Background(), src.Dir, "1.0.0"); err != nil {
+		t.Fatal(err)
+	} else if len(refs) != 0 {
+		t.Fatalf("expected no plugins to be migrated but got %v", refs)
+	}
+
+	if _, err := dst.Migrate(context.Background(), dst.Dir, "1.0.0"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,247 @@
+package plgn
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+)
+
+const (
+	HANDSHAKE_TIMEOUT = time.Second * 30
+	RELOAD_TIMEOUT    = time.Second * 30
+)
+
+type (
+	ProcessOptions struct {
+		Stdout io.Writer
+		Stderr io.Writer
+
+		// KillTimeout is how long to wait for the plugin to exit after it has been sent
+		// SIGTERM (i.e. after the context is cancelled) before it is forcefully killed.
+		// If zero, the plugin is killed immediately.
+		KillTimeout time.Duration
+
+		// HandshakeTimeout is how long to wait for the plugin to complete the handshake.
+		// If zero, HANDSHAKE_TIMEOUT is used.
+		HandshakeTimeout time.Duration
+	}
+
+	// Process is a running plugin that has successfully completed the handshake.
+	Process struct {
+		Cmd       *exec.Cmd
+		Handshake *handshake.Response
+		stdin     io.WriteCloser
+		reloads   chan handshakeResult
+		reloading *sync.Mutex
+		output    chan struct{}
+	}
+
+	handshakeResult struct {
+		res *handshake.Response
+		err error
+	}
+)
+
+// Start launches the plugin, sends it the chain config, and blocks until the plugin
+// reports that it is ready. If the plugin exits early, reports an incompatible
+// protocol version, or does not respond in time, then it is killed and an error is
+// returned.
+func (store *PluginStore) Start(ctx context.Context, conf *config.ChainConfig, opts ProcessOptions) (*Process, error) {
+	ref, err := RefFromConfig(conf.Plugin)
+	if err != nil {
+		return nil, err
+	}
+
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := exec.CommandContext(ctx, pluginPath)
+	if opts.KillTimeout > 0 {
+		cmd.WaitDelay = opts.KillTimeout
+		cmd.Cancel = func() error {
+			return cmd.Process.Signal(syscall.SIGTERM)
+		}
+	}
+	cmd.Stderr = opts.Stderr
+
+	// NOTE: stdin is kept open after the handshake request so that reload requests
+	// can be sent to the plugin later on
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	proc := &Process{
+		Cmd:       cmd,
+		stdin:     stdin,
+		reloads:   make(chan handshakeResult, 1),
+		reloading: &sync.Mutex{},
+		output:    make(chan struct{}),
+	}
+
+	ready := make(chan handshakeResult, 1)
+	go func() {
+		defer close(proc.output)
+
+		// NOTE: lines are read without a length limit, since a plugin that writes a line
+		// which cannot be read would block once its stdout pipe is full
+		isReady := false
+		reader := bufio.NewReader(stdout)
+		for {
+			line, readErr := reader.ReadString('\n')
+			if line == "" && readErr != nil {
+				break
+			} else {
+				line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			}
+
+			if res, ok, err := handshake.ParseResponse(line); ok {
+				if !isReady {
+					ready <- handshakeResult{res, err}
+					isReady = true
+				}
+			} else if res, ok, err := handshake.ParseReloadResponse(line); ok && isReady {
+				if err == nil {
+					err = res.Err()
+				}
+				select {
+				case proc.reloads <- handshakeResult{err: err}:
+				default:
+				}
+			} else {
+				fmt.Fprintln(opts.Stdout, line)
+			}
+
+			if readErr != nil {
+				break
+			}
+		}
+
+		if !isReady {
+			ready <- handshakeResult{nil, errors.New("plugin exited before completing the handshake")}
+		}
+	}()
+
+	if err := handshake.WriteRequest(stdin, handshake.NewRequest(conf)); err != nil {
+		return nil, errors.Join(err, proc.kill())
+	}
+
+	timeout := opts.HandshakeTimeout
+	if timeout == 0 {
+		timeout = HANDSHAKE_TIMEOUT
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case result := <-ready:
+		if result.err != nil {
+			return nil, errors.Join(result.err, proc.kill())
+		} else {
+			proc.Handshake = result.res
+			return proc, nil
+		}
+	case <-timer.C:
+		return nil, errors.Join(
+			fmt.Errorf("plugin did not complete the handshake within %s", timeout),
+			proc.kill(),
+		)
+	}
+}
+
+func (proc *Process) kill() error {
+	if err := proc.Cmd.Process.Kill(); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+
+	<-proc.output
+	_ = proc.Cmd.Wait()
+	return nil
+}
+
+func (proc *Process) String() string {
+	return fmt.Sprintf(
+		"pid = %d, protocol = v%d, address = %s, capabilities = [ %s ]",
+		proc.Cmd.Process.Pid,
+		proc.Handshake.ProtocolVersion,
+		proc.Handshake.Address,
+		strings.Join(proc.Handshake.Capabilities, ", "),
+	)
+}
+
+// ExitCode returns the exit code of the plugin or -1 if it has not exited yet or if
+// it was terminated by a signal.
+func (proc *Process) ExitCode() int {
+	if proc.Cmd.ProcessState == nil {
+		return -1
+	} else {
+		return proc.Cmd.ProcessState.ExitCode()
+	}
+}
+
+// Wait blocks until the plugin exits and all of its output has been forwarded.
+func (proc *Process) Wait() error {
+	// NOTE: Wait() closes the stdout pipe, so we need to make sure that all output
+	// has been read before calling it
+	<-proc.output
+	return proc.Cmd.Wait()
+}
+
+// Reload sends a new chain config to the plugin and waits for the plugin to apply it.
+// The plugin keeps serving its current consumers while it swaps its upstream
+// connections. Only plugins with the reload capability can be reloaded.
+func (proc *Process) Reload(ctx context.Context, conf *config.ChainConfig) error {
+	if !proc.Handshake.HasCapability(handshake.CAPABILITY_RELOAD) {
+		return ErrReloadNotSupported
+	}
+
+	proc.reloading.Lock()
+	defer proc.reloading.Unlock()
+
+	// NOTE: discard any stale response from a reload that previously timed out
+	select {
+	case <-proc.reloads:
+	default:
+	}
+
+	if err := handshake.WriteRequest(proc.stdin, handshake.NewRequest(conf)); err != nil {
+		return fmt.Errorf("failed to send reload request: %w", err)
+	}
+
+	timer := time.NewTimer(RELOAD_TIMEOUT)
+	defer timer.Stop()
+
+	select {
+	case result := <-proc.reloads:
+		return result.err
+	case <-proc.output:
+		return errors.New("plugin exited before completing the reload")
+	case <-timer.C:
+		return fmt.Errorf("plugin did not complete the reload within %s", RELOAD_TIMEOUT)
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
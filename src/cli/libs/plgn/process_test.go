@@ -0,0 +1,135 @@
+package plgn
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+// installScript installs a shell script as version 1.0.0 of a plugin
+func installScript(t *testing.T, store *PluginStore, pluginID string, script string) {
+	pluginPath := filepath.Join(store.Dir, pluginID, "1.0.0", "bin")
+	if err := os.MkdirAll(filepath.Dir(pluginPath), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newChainConfig(pluginID string) *config.ChainConfig {
+	return &config.ChainConfig{
+		Plugin: &config.PluginConfig{ID: pluginID},
+		Server: &config.ServerConfig{Host: "localhost", Port: 3000},
+		Conn:   &config.ConnectionConfg{Wss: "wss://eth.example.com"},
+	}
+}
+
+func TestStart(t *testing.T) {
+	const handshake = `echo 'CC_HANDSHAKE {"protocolVersion":1,"capabilities":["cursors"],"address":"127.0.0.1:3000"}'`
+
+	testCases := []struct {
+		name   string
+		script string
+		err    string
+	}{
+		{name: "ready", script: "read -r req\n" + handshake + "\nread -r req\n"},
+		{name: "exits before the handshake", script: "read -r req\nexit 1\n", err: "plugin exited before completing the handshake"},
+		{name: "handshake timeout", script: "read -r req\nexec sleep 5\n", err: "plugin did not complete the handshake within 100ms"},
+		{name: "incompatible protocol", script: "read -r req\necho 'CC_HANDSHAKE {\"protocolVersion\":99,\"address\":\"127.0.0.1:3000\"}'\nread -r req\n", err: "incompatible plugin protocol version 99"},
+		{name: "long log line", script: "read -r req\nhead -c 100000 /dev/zero | tr '\\0' 'a'\necho\n" + handshake + "\nread -r req\n"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store := &PluginStore{Dir: t.TempDir()}
+			installScript(t, store, "stub", tc.script)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stdout := new(strings.Builder)
+			proc, err := store.Start(ctx, newChainConfig("stub"), ProcessOptions{
+				Stdout:           stdout,
+				Stderr:           io.Discard,
+				HandshakeTimeout: time.Millisecond * 100,
+			})
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if proc.Handshake.Address != "127.0.0.1:3000" {
+				t.Fatalf("unexpected handshake: %+v", proc.Handshake)
+			}
+
+			cancel()
+			_ = proc.Wait()
+
+			// NOTE: log lines of any length are forwarded
+			if tc.name == "long log line" && len(strings.TrimSpace(stdout.String())) != 100000 {
+				t.Fatalf("expected a log line of 100000 bytes but got %d", len(strings.TrimSpace(stdout.String())))
+			}
+		})
+	}
+}
+
+func TestReload(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+	installScript(t, store, "reloadable", `read -r req
+echo 'CC_HANDSHAKE {"protocolVersion":1,"capabilities":["cursors","reload"],"address":"127.0.0.1:3000"}'
+while read -r req; do
+  case "$req" in
+    *invalid*) echo 'CC_RELOAD {"error":"failed to connect"}' ;;
+    *) echo 'CC_RELOAD {}' ;;
+  esac
+done
+`)
+	installScript(t, store, "static", `read -r req
+echo 'CC_HANDSHAKE {"protocolVersion":1,"capabilities":["cursors"],"address":"127.0.0.1:3000"}'
+while read -r req; do :; done
+`)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	proc, err := store.Start(ctx, newChainConfig("reloadable"), ProcessOptions{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := newChainConfig("reloadable")
+	conf.Conn.Wss = "wss://other.example.com"
+	if err := proc.Reload(ctx, conf); err != nil {
+		t.Fatal(err)
+	}
+
+	conf.Conn.Wss = "wss://invalid.example.com"
+	if err := proc.Reload(ctx, conf); err == nil || !strings.Contains(err.Error(), "failed to connect") {
+		t.Fatalf("expected the plugin's reload error but got: %v", err)
+	}
+
+	// NOTE: the CLI restarts plugins that cannot reload their config
+	static, err := store.Start(ctx, newChainConfig("static"), ProcessOptions{Stdout: io.Discard, Stderr: io.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := static.Reload(ctx, newChainConfig("static")); !errors.Is(err, ErrReloadNotSupported) {
+		t.Fatalf("expected reloads to be unsupported but got: %v", err)
+	}
+
+	cancel()
+	_ = proc.Wait()
+	_ = static.Wait()
+}
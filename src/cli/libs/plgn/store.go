@@ -1,46 +1,165 @@
 package plgn
 
 import (
+	"cmp"
 	"context"
-	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
-	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/dirs"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
 )
 
+// CHECKSUM_FILE_NAME is the name of the file (next to each installed plugin binary)
+// that records the SHA-256 digest of the binary at install time.
+const CHECKSUM_FILE_NAME = "bin.sha256"
+
+// PluginStore holds the installed plugins. Several versions of a plugin can be
+// installed side by side:
+//
+//	<dir>/<plugin-id>/<version>/bin
+//	<dir>/<plugin-id>/<version>/bin.sha256
 type PluginStore struct {
 	Dir string
 }
 
 var Store = PluginStore{dirs.PluginsConfig}
 
-func (store *PluginStore) Install(filePaths []string) error {
+// Install installs plugin binaries under the given version. The ID of each plugin is
+// the name of its binary, and the source describes where the binaries came from (see
+// RegistrySource and FileSource). Installing a binary that is already installed is a
+// no-op, and installing a different binary under the same version replaces it.
+func (store *PluginStore) Install(ctx context.Context, version string, source string, filePaths []string) error {
+	version, err := NormalizeVersion(version)
+	if err != nil {
+		return err
+	}
+
 	for _, filePath := range filePaths {
-		dstDir := filepath.Join(store.Dir, ID(filePath))
-		if err := os.MkdirAll(dstDir, os.ModePerm); err != nil {
+		if err := store.install(ctx, Ref{ID: ID(filePath), Version: version}, source, filePath); err != nil {
 			return err
 		}
+	}
 
-		dstPlg := filepath.Join(dstDir, "bin")
-		if err := os.Link(filePath, dstPlg); err != nil {
-			return err
-		}
+	return nil
+}
 
-		if err := os.Chmod(dstPlg, core.FileModeExecutable); err != nil {
-			return err
-		}
+// InstallFile installs a local plugin binary under the given reference (see FileRef).
+func (store *PluginStore) InstallFile(ctx context.Context, ref Ref, filePath string) error {
+	if version, err := NormalizeVersion(ref.Version); err != nil {
+		return err
+	} else {
+		return store.install(ctx, Ref{ID: filepath.Base(ref.ID), Version: version}, FileSource(filePath), filePath)
 	}
+}
 
-	return nil
+// install stages a plugin in a temporary directory which is then renamed into place,
+// so other processes never see a partially installed plugin. The plugin's lock is
+// held throughout so that concurrent installs and removals of the plugin take turns.
+func (store *PluginStore) install(ctx context.Context, ref Ref, source string, filePath string) error {
+	storeLock, err := lock(ctx, store.Dir, ref.ID)
+	if err != nil {
+		return err
+	} else {
+		defer storeLock.unlock()
+	}
+
+	digest, err := integrity.DigestFile(filePath)
+	if err != nil {
+		return err
+	}
+
+	pluginDir := filepath.Join(store.Dir, ref.ID)
+	dstDir := filepath.Join(pluginDir, ref.Version)
+	if installed, err := integrity.DigestFile(filepath.Join(dstDir, "bin")); err == nil && installed == digest {
+		return nil
+	}
+
+	if err := os.MkdirAll(pluginDir, os.ModePerm); err != nil {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(pluginDir, ".install-*")
+	if err != nil {
+		return err
+	}
+
+	cleanup := func(err error) error {
+		return errors.Join(err, os.RemoveAll(tmpDir))
+	}
+
+	tmpPlg := filepath.Join(tmpDir, "bin")
+	if err := linkOrCopy(filePath, tmpPlg); err != nil {
+		return cleanup(err)
+	}
+
+	if err := os.Chmod(tmpPlg, core.FileModeExecutable); err != nil {
+		return cleanup(err)
+	}
+
+	if err := store.recordManifest(ref, tmpPlg); err != nil {
+		return cleanup(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, CHECKSUM_FILE_NAME), []byte(fmt.Sprintf("%s  %s\n", digest, filepath.Base(tmpPlg))), 0644); err != nil {
+		return cleanup(err)
+	}
+
+	if err := writeInstallRecord(tmpDir, &InstallRecord{Source: source, InstalledAt: time.Now().UTC()}); err != nil {
+		return cleanup(err)
+	}
+
+	if err := os.RemoveAll(dstDir); err != nil {
+		return cleanup(err)
+	}
+
+	if err := os.Rename(tmpDir, dstDir); err != nil {
+		return cleanup(err)
+	} else {
+		return nil
+	}
+}
+
+// Verify re-computes the digest of an installed plugin binary and compares it with
+// the digest that was recorded when the plugin was installed.
+func (store *PluginStore) Verify(ref Ref) error {
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return err
+	}
+
+	checksumsFile, err := os.Open(filepath.Join(filepath.Dir(pluginPath), CHECKSUM_FILE_NAME))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("no checksum was recorded for plugin '%s' - reinstall it to record one", ref)
+	}
+	if err != nil {
+		return err
+	} else {
+		defer checksumsFile.Close()
+	}
+
+	checksums, err := integrity.ParseChecksums(checksumsFile)
+	if err != nil {
+		return err
+	}
+
+	digest, err := integrity.DigestFile(pluginPath)
+	if err != nil {
+		return err
+	}
+
+	return checksums.Verify(filepath.Base(pluginPath), digest)
 }
 
-func (store *PluginStore) IsInstalled(pluginID string) (bool, error) {
-	_, err := store.GetPath(pluginID)
+func (store *PluginStore) IsInstalled(ref Ref) (bool, error) {
+	_, err := store.GetPath(ref)
 	if errors.Is(err, &PluginNotFoundError{}) {
 		return false, nil
 	}
@@ -50,8 +169,9 @@ func (store *PluginStore) IsInstalled(pluginID string) (bool, error) {
 	return true, nil
 }
 
-func (store *PluginStore) GetPaths() ([]string, error) {
-	entries, err := os.ReadDir(store.Dir)
+// Versions returns the installed versions of a plugin from oldest to newest.
+func (store *PluginStore) Versions(pluginID string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(store.Dir, filepath.Base(pluginID)))
 	if os.IsNotExist(err) {
 		return []string{}, nil
 	}
@@ -59,56 +179,137 @@ func (store *PluginStore) GetPaths() ([]string, error) {
 		return []string{}, err
 	}
 
-	paths := make([]string, len(entries))
-	for i, entry := range entries {
-		paths[i] = filepath.Join(store.Dir, entry.Name())
+	// NOTE: directories that are not valid versions (e.g. plugins that were installed
+	// before plugins were versioned) are ignored
+	versions := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() && IsValidVersion(entry.Name()) {
+			versions = append(versions, entry.Name())
+		}
 	}
-	return paths, nil
+
+	slices.SortFunc(versions, CompareVersions)
+	return versions, nil
 }
 
-func (store *PluginStore) GetPath(pluginID string) (string, error) {
-	pluginPaths, err := store.GetPaths()
+// Refs returns all installed plugin versions sorted by plugin ID and version.
+func (store *PluginStore) Refs() ([]Ref, error) {
+	entries, err := os.ReadDir(store.Dir)
+	if os.IsNotExist(err) {
+		return []Ref{}, nil
+	}
 	if err != nil {
-		return "", err
+		return []Ref{}, err
 	}
 
-	ids := make([]string, len(pluginPaths))
-	for i, pluginPath := range pluginPaths {
-		id := ID(pluginPath)
-		if id == pluginID {
-			return filepath.Join(pluginPath, "bin"), nil
-		} else {
-			ids[i] = id
+	refs := []Ref{}
+	for _, entry := range entries {
+		if !entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		versions, err := store.Versions(entry.Name())
+		if err != nil {
+			return []Ref{}, err
+		}
+
+		for _, version := range versions {
+			refs = append(refs, Ref{ID: entry.Name(), Version: version})
 		}
 	}
 
-	return "", &PluginNotFoundError{ids, pluginID}
+	slices.SortStableFunc(refs, func(a, b Ref) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+
+	return refs, nil
 }
 
-func (store *PluginStore) Remove(pluginID string) error {
-	pluginPath, err := store.GetPath(pluginID)
-	if errors.Is(err, &PluginNotFoundError{}) {
-		return nil
+// GetPath returns the path to an installed plugin binary. If the reference has no
+// version, then the newest installed version is used.
+func (store *PluginStore) GetPath(ref Ref) (string, error) {
+	versions, err := store.Versions(ref.ID)
+	if err != nil {
+		return "", err
 	}
+
+	version := ref.Version
+	if version == "" && len(versions) != 0 {
+		version = versions[len(versions)-1]
+	}
+
+	// NOTE: the ID is reduced to its base name (as it is when the versions are read)
+	// so that it cannot point outside of the store
+	if slices.Contains(versions, version) {
+		return filepath.Join(store.Dir, filepath.Base(ref.ID), version, "bin"), nil
+	}
+
+	refs, err := store.Refs()
+	if err != nil {
+		return "", err
+	}
+
+	ids := make([]string, len(refs))
+	for i, installed := range refs {
+		ids[i] = installed.String()
+	}
+
+	return "", &PluginNotFoundError{ids, ref.String()}
+}
+
+// Remove removes an installed plugin version. If the reference has no version, then
+// all versions of the plugin are removed.
+func (store *PluginStore) Remove(ctx context.Context, ref Ref) error {
+	storeLock, err := lock(ctx, store.Dir, filepath.Base(ref.ID))
+	if err != nil {
+		return err
+	} else {
+		defer storeLock.unlock()
+	}
+
+	pluginDir := filepath.Join(store.Dir, filepath.Base(ref.ID))
+	if ref.Version == "" {
+		return os.RemoveAll(pluginDir)
+	}
+
+	if err := os.RemoveAll(filepath.Join(pluginDir, filepath.Base(ref.Version))); err != nil {
+		return err
+	}
+
+	versions, err := store.Versions(ref.ID)
 	if err != nil {
 		return err
 	}
-	return os.RemoveAll(filepath.Dir(pluginPath))
+
+	if len(versions) == 0 {
+		return os.RemoveAll(pluginDir)
+	} else {
+		return nil
+	}
 }
 
-func (store *PluginStore) Exec(ctx context.Context, config *config.ChainConfig) error {
-	pluginPath, err := store.GetPath(config.Plugin.ID)
+// linkOrCopy hard links a file or, if that is not possible (e.g. the file is on a
+// different file system), copies it.
+func linkOrCopy(src string, dst string) error {
+	if err := os.Link(src, dst); err == nil {
+		return nil
+	}
+
+	in, err := os.Open(src)
 	if err != nil {
 		return err
+	} else {
+		defer in.Close()
 	}
 
-	confBytes, err := json.Marshal(config)
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, core.FileModeExecutable)
 	if err != nil {
 		return err
 	}
 
-	cmd := exec.CommandContext(ctx, pluginPath, string(confBytes))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+	if _, err := io.Copy(out, in); err != nil {
+		return errors.Join(err, out.Close())
+	} else {
+		return out.Close()
+	}
 }
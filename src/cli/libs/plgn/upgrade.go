@@ -0,0 +1,76 @@
+package plgn
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+)
+
+// Release is a published version of a plugin.
+type Release struct {
+	Ref
+	Registry string
+}
+
+// Latest searches the registries for the newest stable release of a plugin that was
+// built for the current platform. Pre-releases are never considered upgrades.
+func Latest(ctx context.Context, pluginID string, registries []*registry.Entry) (*Release, error) {
+	if len(registries) == 0 {
+		registries = registry.Default()
+	}
+
+	var latest *Release
+	for _, entry := range registries {
+		release, err := latestIn(ctx, entry, pluginID)
+		if err != nil {
+			return nil, fmt.Errorf("registry '%s': %w", entry.Name, err)
+		}
+		if release != nil && (latest == nil || CompareVersions(release.Version, latest.Version) > 0) {
+			latest = release
+		}
+	}
+
+	if latest == nil {
+		return nil, fmt.Errorf("no release of plugin '%s' was found in any registry", pluginID)
+	} else {
+		return latest, nil
+	}
+}
+
+func latestIn(ctx context.Context, entry *registry.Entry, pluginID string) (*Release, error) {
+	tags, err := entry.Releases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	versions := []string{}
+	for _, tag := range tags {
+		if version, err := NormalizeVersion(tag); err == nil && !IsPrerelease(version) {
+			versions = append(versions, version)
+		}
+	}
+
+	// NOTE: not every release contains every plugin, so releases are checked from
+	// newest to oldest until one of them has an archive for the plugin
+	slices.SortFunc(versions, func(a, b string) int { return CompareVersions(b, a) })
+	for _, version := range versions {
+		ref := Ref{ID: pluginID, Version: version}
+
+		assets, err := entry.Assets(ctx, ref.Tag())
+		if errors.Is(err, &registry.ReleaseNotFoundError{}) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if slices.Contains(assets, MakePluginReleaseAssetName(ref)) {
+			return &Release{Ref: ref, Registry: entry.Name}, nil
+		}
+	}
+
+	return nil, nil
+}
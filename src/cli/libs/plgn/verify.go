@@ -0,0 +1,113 @@
+package plgn
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+)
+
+// ReleaseVerification describes how an installed plugin was verified against the
+// release that it was installed from.
+type ReleaseVerification struct {
+	// Registry is the name of the registry that the release was downloaded from
+	Registry string
+
+	// Signed is true if the signature of the release's checksums file was verified
+	// (i.e. the registry has a public key or one was given)
+	Signed bool
+}
+
+// VerifyRelease checks an installed plugin against the release that it was installed
+// from. The binary is first checked against the digest that was recorded at install
+// time (see PluginStore.Verify). Then the plugin's archive is downloaded from the
+// registry in the plugin's install record and verified against the release's
+// checksums file and its signature, and the binary in the archive is compared with
+// the installed binary. Plugins that were not installed from a registry can only be
+// verified with PluginStore.Verify (the error matches *NotFromRegistryError).
+func (cache *PluginCache) VerifyRelease(ctx context.Context, store *PluginStore, ref Ref, opts DownloadOptions) (*ReleaseVerification, error) {
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		return nil, err
+	} else {
+		ref = Ref{ID: filepath.Base(ref.ID), Version: filepath.Base(filepath.Dir(pluginPath))}
+	}
+
+	if err := store.Verify(ref); err != nil {
+		return nil, err
+	}
+
+	record, err := readInstallRecord(filepath.Dir(pluginPath))
+	if err != nil {
+		return nil, err
+	}
+	if record == nil {
+		return nil, &NotFromRegistryError{Ref: ref.String()}
+	}
+
+	registryName, isRegistry := strings.CutPrefix(record.Source, RegistrySource(""))
+	if !isRegistry {
+		return nil, &NotFromRegistryError{Ref: ref.String(), Source: record.Source}
+	}
+
+	registries := opts.Registries
+	if len(registries) == 0 {
+		registries = registry.Default()
+	}
+
+	i := slices.IndexFunc(registries, func(entry *registry.Entry) bool { return entry.Name == registryName })
+	if i == -1 {
+		return nil, fmt.Errorf(
+			"plugin '%s' was installed from registry '%s', which is not configured - must be one of: [ %s ]",
+			ref,
+			registryName,
+			strings.Join(registry.Names(registries), ", "),
+		)
+	}
+
+	entry := registries[i]
+	archive, err := cache.downloadVerified(ctx, entry, ref.Tag(), MakePluginReleaseAssetName(ref), opts)
+	if err != nil {
+		return nil, fmt.Errorf("registry '%s': %w", entry.Name, err)
+	} else {
+		defer os.Remove(archive.Name())
+		defer archive.Close()
+	}
+
+	// NOTE: the archive is extracted next to the cache rather than into it, since the
+	// cached copy of the release is not touched by verification
+	tmpDir, err := os.MkdirTemp(cache.Dir, ".verify-*")
+	if err != nil {
+		return nil, err
+	} else {
+		defer os.RemoveAll(tmpDir)
+	}
+
+	if err := cache.extract(archive, tmpDir); err != nil {
+		return nil, err
+	}
+
+	expected, err := integrity.DigestFile(filepath.Join(tmpDir, ref.ID))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("the archive of release '%s' does not contain plugin '%s'", ref.Tag(), ref.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	actual, err := integrity.DigestFile(pluginPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if expected != actual {
+		return nil, &integrity.ChecksumMismatchError{Name: pluginPath, Expected: expected, Actual: actual}
+	} else {
+		return &ReleaseVerification{Registry: entry.Name, Signed: entry.PublicKey != nil || opts.PublicKey != nil}, nil
+	}
+}
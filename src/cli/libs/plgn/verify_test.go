@@ -0,0 +1,170 @@
+package plgn
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/registry"
+	"golang.org/x/crypto/blake2b"
+)
+
+// newSigner returns a minisign public key and a function that signs a message with
+// the matching secret key (in the same format as `minisign -S`)
+func newSigner(t *testing.T) (*integrity.PublicKey, func(message []byte) string) {
+	pub, sec, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyID := make([]byte, 8)
+	if _, err := rand.Read(keyID); err != nil {
+		t.Fatal(err)
+	}
+
+	pk, err := integrity.ParsePublicKey(base64.StdEncoding.EncodeToString(append(append([]byte(integrity.SIGNATURE_ALGORITHM), keyID...), pub...)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pk, func(message []byte) string {
+		const trustedComment = "timestamp:1700000000"
+		digest := blake2b.Sum512(message)
+		sig := ed25519.Sign(sec, digest[:])
+		globalSig := ed25519.Sign(sec, append(append([]byte{}, sig...), []byte(trustedComment)...))
+		return strings.Join([]string{
+			integrity.UNTRUSTED_COMMENT_PREFIX + "signature from minisign secret key",
+			base64.StdEncoding.EncodeToString(append(append([]byte(integrity.SIGNATURE_ALGORITHM_HASHED), keyID...), sig...)),
+			integrity.TRUSTED_COMMENT_PREFIX + trustedComment,
+			base64.StdEncoding.EncodeToString(globalSig),
+		}, "\n") + "\n"
+	}
+}
+
+// publishRelease writes the archive of a plugin along with the release's checksums
+// file and its signature to a local registry
+func publishRelease(t *testing.T, registryDir string, ref Ref, binary string, sign func(message []byte) string) {
+	releaseDir := filepath.Join(registryDir, ref.Tag())
+	if err := os.MkdirAll(releaseDir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	assetName := MakePluginReleaseAssetName(ref)
+	archive := makeArchive(t, regular(ref.ID, binary)).Bytes()
+	if err := os.WriteFile(filepath.Join(releaseDir, assetName), archive, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := integrity.Digest(strings.NewReader(string(archive)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checksums := []byte(fmt.Sprintf("%s  %s\n", digest, assetName))
+	if err := os.WriteFile(filepath.Join(releaseDir, CHECKSUMS_ASSET_NAME), checksums, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(releaseDir, SIGNATURE_ASSET_NAME), []byte(sign(checksums)), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestVerifyRelease(t *testing.T) {
+	ctx := context.Background()
+	ref := Ref{ID: "eth", Version: "1.0.0"}
+	binary := "#!/bin/sh\nexit 1\n"
+
+	pk, sign := newSigner(t)
+	registryDir := t.TempDir()
+	publishRelease(t, registryDir, ref, binary, sign)
+
+	entry := &registry.Entry{Registry: registry.NewLocalRegistry(registryDir), Name: "mirror", PublicKey: pk}
+	opts := DownloadOptions{Registries: []*registry.Entry{entry}}
+
+	// NOTE: the plugin is installed the same way the install commands install it
+	store := &PluginStore{Dir: t.TempDir()}
+	cache := &PluginCache{Dir: t.TempDir(), Limits: DefaultArchiveLimits()}
+	pluginPaths, source, err := cache.Download(ctx, ref, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Install(ctx, ref.Version, source, pluginPaths); err != nil {
+		t.Fatal(err)
+	}
+
+	verification, err := cache.VerifyRelease(ctx, store, Ref{ID: ref.ID}, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if verification.Registry != "mirror" || !verification.Signed {
+		t.Fatalf("unexpected verification: %+v", verification)
+	}
+
+	// NOTE: without a public key only the checksums are verified
+	unsigned := DownloadOptions{Registries: []*registry.Entry{{Registry: entry.Registry, Name: entry.Name}}}
+	if verification, err := cache.VerifyRelease(ctx, store, ref, unsigned); err != nil || verification.Signed {
+		t.Fatalf("expected an unsigned verification but got: %+v (%v)", verification, err)
+	}
+
+	// NOTE: the registry that the plugin was installed from must be configured
+	other := DownloadOptions{Registries: []*registry.Entry{{Registry: entry.Registry, Name: "other"}}}
+	if _, err := cache.VerifyRelease(ctx, store, ref, other); err == nil || !strings.Contains(err.Error(), "registry 'mirror', which is not configured") {
+		t.Fatalf("expected an error for a registry that is not configured but got: %v", err)
+	}
+
+	// NOTE: a release signed by another key is rejected
+	_, otherSign := newSigner(t)
+	publishRelease(t, registryDir, ref, binary, otherSign)
+	if _, err := cache.VerifyRelease(ctx, store, ref, opts); !errors.Is(err, &integrity.SignatureError{}) {
+		t.Fatalf("expected a signature error but got: %v", err)
+	}
+	publishRelease(t, registryDir, ref, binary, sign)
+
+	// NOTE: a binary that was replaced along with its recorded checksum still passes the
+	// offline check, but it no longer matches the release
+	pluginPath, err := store.GetPath(ref)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tampered := "#!/bin/sh\nexit 2\n"
+	if err := os.Remove(pluginPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte(tampered), 0755); err != nil {
+		t.Fatal(err)
+	}
+	digest, err := integrity.DigestFile(pluginPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(filepath.Dir(pluginPath), CHECKSUM_FILE_NAME), []byte(digest+"  bin\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := store.Verify(ref); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.VerifyRelease(ctx, store, ref, opts); !errors.Is(err, &integrity.ChecksumMismatchError{}) {
+		t.Fatalf("expected a checksum mismatch but got: %v", err)
+	}
+
+	// NOTE: plugins that were installed from a file have no release
+	filePath := filepath.Join(t.TempDir(), "solana")
+	if err := os.WriteFile(filePath, []byte(binary), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.Install(ctx, "1.0.0", FileSource(filePath), []string{filePath}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.VerifyRelease(ctx, store, Ref{ID: "solana"}, opts); !errors.Is(err, &NotFromRegistryError{}) {
+		t.Fatalf("expected a NotFromRegistryError but got: %v", err)
+	}
+}
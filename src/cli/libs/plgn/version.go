@@ -0,0 +1,229 @@
+package plgn
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+)
+
+// Ref identifies a plugin and, optionally, one of its versions (e.g. 'eth' or
+// 'eth@1.2.0'). Versions are stored without the 'v' prefix that release tags use.
+type Ref struct {
+	ID      string
+	Version string
+}
+
+type semver struct {
+	parts      [3]int64
+	prerelease string
+}
+
+func ParseRef(value string) (Ref, error) {
+	id, version, found := strings.Cut(value, "@")
+	if id == "" {
+		return Ref{}, fmt.Errorf("invalid plugin reference '%s' - expected '<plugin-id>' or '<plugin-id>@<version>'", value)
+	}
+	if !found {
+		return Ref{ID: id}, nil
+	}
+
+	normalized, err := NormalizeVersion(version)
+	if err != nil {
+		return Ref{}, fmt.Errorf("invalid plugin reference '%s': %w", value, err)
+	} else {
+		return Ref{ID: id, Version: normalized}, nil
+	}
+}
+
+// RefFromConfig creates a reference from a plugin config. The version can either be
+// part of the ID (e.g. 'eth@1.2.0') or set separately, but not both.
+func RefFromConfig(conf *config.PluginConfig) (Ref, error) {
+	if conf == nil {
+		return Ref{}, fmt.Errorf("chain config is missing the 'plugin' block")
+	}
+
+	ref, err := ParseRef(conf.ID)
+	if err != nil {
+		return Ref{}, err
+	}
+
+	if conf.Version != "" {
+		version, err := NormalizeVersion(conf.Version)
+		if err != nil {
+			return Ref{}, err
+		}
+		if ref.Version != "" && ref.Version != version {
+			return Ref{}, fmt.Errorf("plugin '%s' has conflicting versions '%s' and '%s'", ref.ID, ref.Version, version)
+		}
+		ref.Version = version
+	}
+
+	return ref, nil
+}
+
+// DefaultVersion is the plugin version that is installed when no version is given,
+// which is the version of the release that the CLI itself was published with.
+func DefaultVersion() string {
+	return core.VersionWithoutPrefix()
+}
+
+func (ref Ref) String() string {
+	if ref.Version == "" {
+		return ref.ID
+	} else {
+		return ref.ID + "@" + ref.Version
+	}
+}
+
+func (ref Ref) WithDefaultVersion() Ref {
+	if ref.Version == "" {
+		ref.Version = DefaultVersion()
+	}
+	return ref
+}
+
+// Tag returns the release tag that the plugin version is published under.
+func (ref Ref) Tag() string {
+	return VersionTag(ref.Version)
+}
+
+func VersionTag(version string) string {
+	return "v" + version
+}
+
+// NormalizeVersion validates a semantic version and strips its 'v' prefix.
+func NormalizeVersion(version string) (string, error) {
+	version = strings.TrimPrefix(version, "v")
+	if _, err := parseSemver(version); err != nil {
+		return "", err
+	} else {
+		return version, nil
+	}
+}
+
+func IsValidVersion(version string) bool {
+	_, err := parseSemver(strings.TrimPrefix(version, "v"))
+	return err == nil
+}
+
+func IsPrerelease(version string) bool {
+	v, err := parseSemver(strings.TrimPrefix(version, "v"))
+	return err == nil && v.prerelease != ""
+}
+
+// CompareVersions compares two semantic versions (ignoring build metadata). Invalid
+// versions are ordered before valid ones.
+func CompareVersions(a string, b string) int {
+	va, errA := parseSemver(strings.TrimPrefix(a, "v"))
+	vb, errB := parseSemver(strings.TrimPrefix(b, "v"))
+	if errA != nil || errB != nil {
+		if errA != nil && errB != nil {
+			return strings.Compare(a, b)
+		}
+		if errA != nil {
+			return -1
+		}
+		return 1
+	}
+
+	for i := range va.parts {
+		if c := cmp.Compare(va.parts[i], vb.parts[i]); c != 0 {
+			return c
+		}
+	}
+
+	// NOTE: a pre-release has a lower precedence than the release itself
+	switch {
+	case va.prerelease == vb.prerelease:
+		return 0
+	case va.prerelease == "":
+		return 1
+	case vb.prerelease == "":
+		return -1
+	default:
+		return comparePrereleases(va.prerelease, vb.prerelease)
+	}
+}
+
+// comparePrereleases compares pre-release versions as described by the semver spec
+// (https://semver.org/#spec-item-11): identifiers are compared one at a time from
+// left to right, numeric identifiers are compared as numbers and have a lower
+// precedence than alphanumeric ones, and a shorter list of identifiers has a lower
+// precedence if all of its identifiers are equal to the other list's
+func comparePrereleases(a string, b string) int {
+	idsA := strings.Split(a, ".")
+	idsB := strings.Split(b, ".")
+	for i := range min(len(idsA), len(idsB)) {
+		if c := compareIdentifiers(idsA[i], idsB[i]); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(idsA), len(idsB))
+}
+
+func compareIdentifiers(a string, b string) int {
+	numA := isNumeric(a)
+	numB := isNumeric(b)
+	switch {
+	case numA && numB:
+		// NOTE: numbers are compared by length first so that identifiers of any size
+		// can be compared without overflowing
+		a = strings.TrimLeft(a, "0")
+		b = strings.TrimLeft(b, "0")
+		if c := cmp.Compare(len(a), len(b)); c != 0 {
+			return c
+		} else {
+			return strings.Compare(a, b)
+		}
+	case numA:
+		return -1
+	case numB:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func isNumeric(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func parseSemver(version string) (semver, error) {
+	var v semver
+
+	core, _, _ := strings.Cut(version, "+")
+	core, prerelease, found := strings.Cut(core, "-")
+	if found && prerelease == "" {
+		return v, fmt.Errorf("invalid version '%s' - empty pre-release", version)
+	} else {
+		v.prerelease = prerelease
+	}
+
+	parts := strings.Split(core, ".")
+	if len(parts) != 3 {
+		return v, fmt.Errorf("invalid version '%s' - expected '<major>.<minor>.<patch>'", version)
+	}
+
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 {
+			return v, fmt.Errorf("invalid version '%s' - '%s' is not a number", version, part)
+		} else {
+			v.parts[i] = n
+		}
+	}
+
+	return v, nil
+}
@@ -0,0 +1,159 @@
+package plgn
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+func TestParseRef(t *testing.T) {
+	valid := map[string]Ref{
+		"eth":          {ID: "eth"},
+		"eth@1.2.0":    {ID: "eth", Version: "1.2.0"},
+		"eth@v1.2.0":   {ID: "eth", Version: "1.2.0"},
+		"eth@1.2.0-rc": {ID: "eth", Version: "1.2.0-rc"},
+	}
+	for value, expected := range valid {
+		if ref, err := ParseRef(value); err != nil {
+			t.Fatal(err)
+		} else if ref != expected {
+			t.Fatalf("expected %+v for '%s' but got %+v", expected, value, ref)
+		}
+	}
+
+	for _, value := range []string{"", "@1.2.0", "eth@", "eth@1.2", "eth@latest", "eth@1.2.x"} {
+		if _, err := ParseRef(value); err == nil {
+			t.Fatalf("expected an error for '%s'", value)
+		}
+	}
+}
+
+func TestRefFromConfig(t *testing.T) {
+	ref, err := RefFromConfig(&config.PluginConfig{ID: "eth", Version: "v1.2.0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ref != (Ref{ID: "eth", Version: "1.2.0"}) {
+		t.Fatalf("unexpected ref: %+v", ref)
+	}
+
+	if _, err := RefFromConfig(&config.PluginConfig{ID: "eth@1.2.0", Version: "1.2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RefFromConfig(&config.PluginConfig{ID: "eth@1.2.0", Version: "1.3.0"}); err == nil {
+		t.Fatal("expected an error for conflicting versions")
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	versions := []string{"1.10.0", "invalid", "1.2.0", "v1.2.1", "1.2.0-rc.1", "0.9.9"}
+	slices.SortFunc(versions, CompareVersions)
+
+	expected := []string{"invalid", "0.9.9", "1.2.0-rc.1", "1.2.0", "v1.2.1", "1.10.0"}
+	if !slices.Equal(versions, expected) {
+		t.Fatalf("expected %v but got %v", expected, versions)
+	}
+
+	// NOTE: the example from the semver spec (https://semver.org/#spec-item-11)
+	prereleases := []string{"1.0.0", "1.0.0-rc.1", "1.0.0-beta.11", "1.0.0-beta.2", "1.0.0-beta", "1.0.0-alpha.beta", "1.0.0-alpha.1", "1.0.0-alpha"}
+	slices.SortFunc(prereleases, CompareVersions)
+
+	expected = []string{"1.0.0-alpha", "1.0.0-alpha.1", "1.0.0-alpha.beta", "1.0.0-beta", "1.0.0-beta.2", "1.0.0-beta.11", "1.0.0-rc.1", "1.0.0"}
+	if !slices.Equal(prereleases, expected) {
+		t.Fatalf("expected %v but got %v", expected, prereleases)
+	}
+
+	testCases := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{a: "1.2.0-rc.10", b: "1.2.0-rc.2", expected: 1},
+		{a: "1.2.0-rc.2", b: "1.2.0-rc.10", expected: -1},
+		{a: "1.2.0-rc.1", b: "1.2.0-rc.1", expected: 0},
+		{a: "1.2.0-rc.1+build.5", b: "1.2.0-rc.1", expected: 0},
+		{a: "1.2.0-1", b: "1.2.0-alpha", expected: -1},
+		{a: "1.2.0-rc.1", b: "1.2.0-rc.1.1", expected: -1},
+		{a: "1.2.0-99999999999999999999", b: "1.2.0-100000000000000000000", expected: -1},
+	}
+
+	for _, tc := range testCases {
+		if actual := CompareVersions(tc.a, tc.b); actual != tc.expected {
+			t.Fatalf("expected CompareVersions(%s, %s) to be %d but got %d", tc.a, tc.b, tc.expected, actual)
+		}
+	}
+}
+
+func TestStoreVersions(t *testing.T) {
+	store := &PluginStore{Dir: t.TempDir()}
+
+	src := t.TempDir()
+	for _, version := range []string{"1.10.0", "1.9.0"} {
+		pluginPath := filepath.Join(src, version, "eth")
+		if err := os.MkdirAll(filepath.Dir(pluginPath), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(pluginPath, []byte(version), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.Install(context.Background(), version, FileSource(pluginPath), []string{pluginPath}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	refs, err := store.Refs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(refs, []Ref{{ID: "eth", Version: "1.9.0"}, {ID: "eth", Version: "1.10.0"}}) {
+		t.Fatalf("unexpected refs: %v", refs)
+	}
+
+	// NOTE: the newest version is used if no version is given
+	for ref, expected := range map[Ref]string{{ID: "eth"}: "1.10.0", {ID: "eth", Version: "1.9.0"}: "1.9.0"} {
+		pluginPath, err := store.GetPath(ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if data, err := os.ReadFile(pluginPath); err != nil {
+			t.Fatal(err)
+		} else if string(data) != expected {
+			t.Fatalf("expected '%s' to resolve to %s but got %s", ref, expected, string(data))
+		}
+		if err := store.Verify(ref); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := store.GetPath(Ref{ID: "eth", Version: "2.0.0"}); !errors.Is(err, &PluginNotFoundError{}) {
+		t.Fatalf("expected a plugin not found error but got: %v", err)
+	}
+
+	// NOTE: plugin IDs cannot point outside of the store
+	if pluginPath, err := store.GetPath(Ref{ID: "../other/eth", Version: "1.9.0"}); err != nil {
+		t.Fatal(err)
+	} else if expected := filepath.Join(store.Dir, "eth", "1.9.0", "bin"); pluginPath != expected {
+		t.Fatalf("expected '%s' but got '%s'", expected, pluginPath)
+	}
+
+	if err := store.Remove(context.Background(), Ref{ID: "eth", Version: "1.10.0"}); err != nil {
+		t.Fatal(err)
+	}
+	if versions, err := store.Versions("eth"); err != nil {
+		t.Fatal(err)
+	} else if !slices.Equal(versions, []string{"1.9.0"}) {
+		t.Fatalf("unexpected versions after removal: %v", versions)
+	}
+
+	if err := store.Remove(context.Background(), Ref{ID: "eth"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(store.Dir, "eth")); !os.IsNotExist(err) {
+		t.Fatalf("expected the plugin dir to be removed but got: %v", err)
+	}
+}
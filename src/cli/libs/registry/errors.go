@@ -0,0 +1,44 @@
+package registry
+
+import "fmt"
+
+type AssetNotFoundError struct {
+	Tag  string
+	Name string
+}
+
+func (e *AssetNotFoundError) Error() string {
+	return fmt.Sprintf("asset '%s' does not exist in release '%s'", e.Name, e.Tag)
+}
+
+func (e *AssetNotFoundError) Is(target error) bool {
+	_, ok := target.(*AssetNotFoundError)
+	return ok
+}
+
+type ReleaseNotFoundError struct {
+	Tag string
+}
+
+func (e *ReleaseNotFoundError) Error() string {
+	return fmt.Sprintf("release '%s' does not exist", e.Tag)
+}
+
+func (e *ReleaseNotFoundError) Is(target error) bool {
+	_, ok := target.(*ReleaseNotFoundError)
+	return ok
+}
+
+type HttpStatusError struct {
+	Url        string
+	StatusCode int
+}
+
+func (e *HttpStatusError) Error() string {
+	return fmt.Sprintf("request to %s failed with status %d", e.Url, e.StatusCode)
+}
+
+func (e *HttpStatusError) Is(target error) bool {
+	_, ok := target.(*HttpStatusError)
+	return ok
+}
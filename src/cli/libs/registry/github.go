@@ -0,0 +1,67 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/gh"
+)
+
+// GithubRegistry serves assets from the releases of a GitHub repository.
+type GithubRegistry struct {
+	client *gh.GithubApi
+}
+
+func NewGithubRegistry(client *gh.GithubApi) *GithubRegistry {
+	return &GithubRegistry{client: client}
+}
+
+func newGithubClient(owner string, repo string) *gh.GithubApi {
+	return gh.NewClient(gh.NewRepository(owner, repo))
+}
+
+func (r *GithubRegistry) Releases(ctx context.Context) ([]string, error) {
+	releases, err := r.client.ListReleases(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, release := range releases {
+		if !release.Draft && !release.Prerelease {
+			tags = append(tags, release.TagName)
+		}
+	}
+
+	return tags, nil
+}
+
+func (r *GithubRegistry) Assets(ctx context.Context, tag string) ([]string, error) {
+	release, err := r.client.GetReleaseByTag(ctx, tag)
+	if errors.Is(err, &gh.NotFoundError{}) {
+		return nil, &ReleaseNotFoundError{Tag: tag}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(release.Assets))
+	for i, asset := range release.Assets {
+		names[i] = asset.Name
+	}
+
+	return names, nil
+}
+
+func (r *GithubRegistry) Open(ctx context.Context, tag string, name string) (io.ReadCloser, error) {
+	res, err := r.client.DownloadReleaseAsset(ctx, tag, name)
+	if errors.Is(err, &gh.NotFoundError{}) {
+		return nil, &AssetNotFoundError{Tag: tag, Name: name}
+	}
+	if err != nil {
+		return nil, err
+	} else {
+		return res.Body, nil
+	}
+}
@@ -0,0 +1,162 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const HTTP_INDEX_FILE_NAME = "index.json"
+
+type (
+	// HttpRegistry serves assets from a static HTTP server. The registry has an index
+	// file which lists its releases, and each release has an index file which lists
+	// its assets:
+	//
+	//	<url>/index.json
+	//	<url>/<tag>/index.json
+	//
+	// An asset's URL is resolved relative to the index file and defaults to its name,
+	// so the tarballs can either sit next to the index or be hosted elsewhere.
+	HttpRegistry struct {
+		HttpClient *http.Client
+		Url        string
+	}
+
+	HttpIndex struct {
+		Assets []HttpIndexAsset `json:"assets"`
+	}
+
+	HttpRootIndex struct {
+		Releases []string `json:"releases"`
+	}
+
+	HttpIndexAsset struct {
+		Name string `json:"name"`
+		Url  string `json:"url,omitempty"`
+	}
+)
+
+func NewHttpRegistry(url string) *HttpRegistry {
+	return &HttpRegistry{HttpClient: &http.Client{}, Url: strings.TrimSuffix(url, "/")}
+}
+
+func (r *HttpRegistry) indexUrl(tag string) string {
+	return fmt.Sprintf("%s/%s/%s", r.Url, url.PathEscape(tag), HTTP_INDEX_FILE_NAME)
+}
+
+func (r *HttpRegistry) get(ctx context.Context, url string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := r.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, &HttpStatusError{Url: url, StatusCode: res.StatusCode}
+	} else {
+		return res, nil
+	}
+}
+
+func (r *HttpRegistry) index(ctx context.Context, tag string) (*HttpIndex, error) {
+	res, err := r.get(ctx, r.indexUrl(tag))
+	statusErr := &HttpStatusError{}
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, &ReleaseNotFoundError{Tag: tag}
+	}
+	if err != nil {
+		return nil, err
+	} else {
+		defer res.Body.Close()
+	}
+
+	var index HttpIndex
+	if err := json.NewDecoder(res.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", r.indexUrl(tag), err)
+	} else {
+		return &index, nil
+	}
+}
+
+func (r *HttpRegistry) Releases(ctx context.Context) ([]string, error) {
+	indexUrl := fmt.Sprintf("%s/%s", r.Url, HTTP_INDEX_FILE_NAME)
+
+	res, err := r.get(ctx, indexUrl)
+	if err != nil {
+		return nil, err
+	} else {
+		defer res.Body.Close()
+	}
+
+	var index HttpRootIndex
+	if err := json.NewDecoder(res.Body).Decode(&index); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", indexUrl, err)
+	} else {
+		return index.Releases, nil
+	}
+}
+
+func (r *HttpRegistry) Assets(ctx context.Context, tag string) ([]string, error) {
+	index, err := r.index(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(index.Assets))
+	for i, asset := range index.Assets {
+		names[i] = asset.Name
+	}
+
+	return names, nil
+}
+
+func (r *HttpRegistry) Open(ctx context.Context, tag string, name string) (io.ReadCloser, error) {
+	index, err := r.index(ctx, tag)
+	if errors.Is(err, &ReleaseNotFoundError{}) {
+		return nil, &AssetNotFoundError{Tag: tag, Name: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, asset := range index.Assets {
+		if asset.Name != name {
+			continue
+		}
+
+		base, err := url.Parse(r.indexUrl(tag))
+		if err != nil {
+			return nil, err
+		}
+
+		ref := asset.Url
+		if ref == "" {
+			ref = url.PathEscape(asset.Name)
+		}
+
+		assetUrl, err := base.Parse(ref)
+		if err != nil {
+			return nil, fmt.Errorf("invalid URL for asset '%s': %w", name, err)
+		}
+
+		res, err := r.get(ctx, assetUrl.String())
+		if err != nil {
+			return nil, err
+		} else {
+			return res.Body, nil
+		}
+	}
+
+	return nil, &AssetNotFoundError{Tag: tag, Name: name}
+}
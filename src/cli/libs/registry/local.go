@@ -0,0 +1,70 @@
+package registry
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalRegistry serves assets from a directory (e.g. a mirror on an air-gapped host).
+// The assets of each release are stored in a subdirectory named after its tag:
+//
+//	<path>/<tag>/checksums.txt
+//	<path>/<tag>/eth-plugin_<version>_<os>_<arch>.tar.gz
+type LocalRegistry struct {
+	path string
+}
+
+func NewLocalRegistry(path string) *LocalRegistry {
+	return &LocalRegistry{path: path}
+}
+
+func (r *LocalRegistry) Releases(ctx context.Context) ([]string, error) {
+	entries, err := os.ReadDir(r.path)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			tags = append(tags, entry.Name())
+		}
+	}
+
+	return tags, nil
+}
+
+func (r *LocalRegistry) Assets(ctx context.Context, tag string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(r.path, filepath.Base(tag)))
+	if os.IsNotExist(err) {
+		return nil, &ReleaseNotFoundError{Tag: tag}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.Type().IsRegular() {
+			names = append(names, entry.Name())
+		}
+	}
+
+	return names, nil
+}
+
+func (r *LocalRegistry) Open(ctx context.Context, tag string, name string) (io.ReadCloser, error) {
+	// NOTE: the tag and the name are reduced to their base names so that they cannot
+	// be used to read files outside of the registry
+	file, err := os.Open(filepath.Join(r.path, filepath.Base(tag), filepath.Base(name)))
+	if os.IsNotExist(err) {
+		return nil, &AssetNotFoundError{Tag: tag, Name: name}
+	}
+	if err != nil {
+		return nil, err
+	} else {
+		return file, nil
+	}
+}
@@ -0,0 +1,154 @@
+package registry
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/core"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/integrity"
+)
+
+const (
+	TYPE_GITHUB = "github"
+	TYPE_HTTP   = "http"
+	TYPE_LOCAL  = "local"
+	TYPE_OCI    = "oci"
+)
+
+const DEFAULT_REGISTRY_NAME = "github"
+
+type (
+	// Registry is a source of release assets (i.e. plugin archives along with the
+	// checksums file and its signature). Assets are grouped by release tag.
+	Registry interface {
+		// Releases lists the tags of all releases that the registry serves.
+		Releases(ctx context.Context) ([]string, error)
+
+		// Assets lists the names of the assets that were published for a release.
+		Assets(ctx context.Context, tag string) ([]string, error)
+
+		// Open opens an asset for reading. If the asset does not exist, then the error
+		// matches *AssetNotFoundError.
+		Open(ctx context.Context, tag string, name string) (io.ReadCloser, error)
+	}
+
+	Entry struct {
+		Registry
+		Name      string
+		Priority  int64
+		PublicKey *integrity.PublicKey
+	}
+)
+
+// Default returns the registry that is used if none are configured (i.e. the GitHub
+// releases of this repository).
+func Default() []*Entry {
+	return []*Entry{{Registry: NewGithubRegistry(core.GithubClient), Name: DEFAULT_REGISTRY_NAME}}
+}
+
+// FromConfig creates registries from the CLI config sorted by priority (highest
+// first). Registries with the same priority keep the order of the config. If no
+// registries are configured, then the default registry is returned.
+func FromConfig(confs []config.RegistryConfig) ([]*Entry, error) {
+	if len(confs) == 0 {
+		return Default(), nil
+	}
+
+	names := map[string]bool{}
+	entries := []*Entry{}
+	for _, conf := range confs {
+		if conf.Name == "" {
+			return nil, fmt.Errorf("registry of type '%s' is missing a name", conf.Type)
+		}
+		if names[conf.Name] {
+			return nil, fmt.Errorf("registry with name '%s' is defined more than once", conf.Name)
+		} else {
+			names[conf.Name] = true
+		}
+
+		reg, err := New(conf)
+		if err != nil {
+			return nil, fmt.Errorf("registry '%s': %w", conf.Name, err)
+		}
+
+		entry := &Entry{Registry: reg, Name: conf.Name, Priority: conf.Priority}
+		if conf.PublicKey != "" {
+			if pk, err := integrity.LoadPublicKey(conf.PublicKey); err != nil {
+				return nil, fmt.Errorf("registry '%s': %w", conf.Name, err)
+			} else {
+				entry.PublicKey = pk
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	slices.SortStableFunc(entries, func(a, b *Entry) int {
+		return cmp.Compare(b.Priority, a.Priority)
+	})
+
+	return entries, nil
+}
+
+func New(conf config.RegistryConfig) (Registry, error) {
+	switch conf.Type {
+	case TYPE_GITHUB:
+		if conf.Owner == "" || conf.Repo == "" {
+			return nil, fmt.Errorf("a %s registry requires an 'owner' and a 'repo'", conf.Type)
+		} else {
+			return NewGithubRegistry(newGithubClient(conf.Owner, conf.Repo)), nil
+		}
+	case TYPE_HTTP:
+		if conf.Url == "" {
+			return nil, fmt.Errorf("a %s registry requires a 'url'", conf.Type)
+		} else {
+			return NewHttpRegistry(conf.Url), nil
+		}
+	case TYPE_LOCAL:
+		if conf.Path == "" {
+			return nil, fmt.Errorf("a %s registry requires a 'path'", conf.Type)
+		} else {
+			return NewLocalRegistry(conf.Path), nil
+		}
+	case TYPE_OCI:
+		if conf.Url == "" {
+			return nil, fmt.Errorf("an %s registry requires a 'url'", conf.Type)
+		} else {
+			return NewOciRegistry(conf.Url)
+		}
+	default:
+		return nil, fmt.Errorf(
+			"invalid registry type '%s' - must be one of: [ %s ]",
+			conf.Type,
+			strings.Join([]string{TYPE_GITHUB, TYPE_HTTP, TYPE_LOCAL, TYPE_OCI}, ", "),
+		)
+	}
+}
+
+func Names(entries []*Entry) []string {
+	names := make([]string, len(entries))
+	for i, entry := range entries {
+		names[i] = entry.Name
+	}
+	return names
+}
+
+// FromConfigFile loads the registries from a CLI config file. If the path is empty,
+// then the default registry is returned.
+func FromConfigFile(configPath string) ([]*Entry, error) {
+	if configPath == "" {
+		return Default(), nil
+	}
+
+	cliConfig, err := config.ParseCliConfig(configPath)
+	if err != nil {
+		return nil, err
+	} else {
+		return FromConfig(cliConfig.Registries)
+	}
+}
@@ -0,0 +1,180 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+)
+
+const (
+	TEST_TAG   = "v1.0.0"
+	TEST_ASSET = "eth-plugin_1.0.0_linux_amd64.tar.gz"
+	TEST_DATA  = "archive"
+	TEST_TOKEN = "secret-token"
+)
+
+// testRegistry checks the behavior that every registry implementation must share
+func testRegistry(t *testing.T, reg Registry) {
+	ctx := context.Background()
+
+	tags, err := reg.Releases(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(tags, []string{TEST_TAG}) {
+		t.Fatalf("expected releases to be [%s] but got: %v", TEST_TAG, tags)
+	}
+
+	assets, err := reg.Assets(ctx, TEST_TAG)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(assets, TEST_ASSET) {
+		t.Fatalf("expected assets to contain '%s' but got: %v", TEST_ASSET, assets)
+	}
+
+	src, err := reg.Open(ctx, TEST_TAG, TEST_ASSET)
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		defer src.Close()
+	}
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != TEST_DATA {
+		t.Fatalf("unexpected asset contents: %s", string(data))
+	}
+
+	if _, err := reg.Open(ctx, TEST_TAG, "missing.tar.gz"); !errors.Is(err, &AssetNotFoundError{}) {
+		t.Fatalf("expected an asset not found error but got: %v", err)
+	}
+	if _, err := reg.Open(ctx, "v0.0.0", TEST_ASSET); !errors.Is(err, &AssetNotFoundError{}) {
+		t.Fatalf("expected an asset not found error but got: %v", err)
+	}
+	if _, err := reg.Assets(ctx, "v0.0.0"); !errors.Is(err, &ReleaseNotFoundError{}) {
+		t.Fatalf("expected a release not found error but got: %v", err)
+	}
+}
+
+func TestLocalRegistry(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, TEST_TAG), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, TEST_TAG, TEST_ASSET), []byte(TEST_DATA), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	testRegistry(t, NewLocalRegistry(dir))
+}
+
+func TestHttpRegistry(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/releases/index.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HttpRootIndex{Releases: []string{TEST_TAG}})
+	})
+	mux.HandleFunc("/releases/"+TEST_TAG+"/index.json", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(HttpIndex{Assets: []HttpIndexAsset{{Name: TEST_ASSET, Url: "../../files/" + TEST_ASSET}}})
+	})
+	mux.HandleFunc("/files/"+TEST_ASSET, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, TEST_DATA)
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	testRegistry(t, NewHttpRegistry(server.URL+"/releases/"))
+}
+
+func TestOciRegistry(t *testing.T) {
+	hash := sha256.Sum256([]byte(TEST_DATA))
+	digest := "sha256:" + hex.EncodeToString(hash[:])
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("scope") != "repository:org/plugins:pull" {
+			w.WriteHeader(http.StatusBadRequest)
+		} else {
+			json.NewEncoder(w).Encode(map[string]string{"token": TEST_TOKEN})
+		}
+	})
+	mux.HandleFunc("/v2/org/plugins/", func(w http.ResponseWriter, r *http.Request) {
+		// NOTE: mimics registries such as ghcr.io which require a token even for
+		// anonymous pulls
+		if r.Header.Get("Authorization") != "Bearer "+TEST_TOKEN {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="http://%s/token",service="test",scope="repository:org/plugins:pull"`, r.Host))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch strings.TrimPrefix(r.URL.Path, "/v2/org/plugins/") {
+		case "tags/list":
+			json.NewEncoder(w).Encode(map[string]any{"name": "org/plugins", "tags": []string{TEST_TAG}})
+		case "manifests/" + TEST_TAG:
+			json.NewEncoder(w).Encode(ociManifest{Layers: []ociDescriptor{{
+				MediaType:   "application/vnd.oci.image.layer.v1.tar",
+				Digest:      digest,
+				Size:        int64(len(TEST_DATA)),
+				Annotations: map[string]string{OCI_TITLE_ANNOTATION_KEY: TEST_ASSET},
+			}}})
+		case "blobs/" + digest:
+			fmt.Fprint(w, TEST_DATA)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	reg, err := NewOciRegistry(server.URL + "/org/plugins")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testRegistry(t, reg)
+}
+
+func TestFromConfig(t *testing.T) {
+	entries, err := FromConfig([]config.RegistryConfig{
+		{Name: "low", Type: TYPE_LOCAL, Path: "/tmp", Priority: -1},
+		{Name: "first", Type: TYPE_HTTP, Url: "https://example.com"},
+		{Name: "high", Type: TYPE_OCI, Url: "ghcr.io/org/plugins", Priority: 10},
+		{Name: "second", Type: TYPE_GITHUB, Owner: "org", Repo: "plugins"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if names := Names(entries); !slices.Equal(names, []string{"high", "first", "second", "low"}) {
+		t.Fatalf("unexpected registry order: %v", names)
+	}
+
+	invalid := [][]config.RegistryConfig{
+		{{Name: "a", Type: "ftp"}},
+		{{Name: "a", Type: TYPE_HTTP}},
+		{{Type: TYPE_LOCAL, Path: "/tmp"}},
+		{{Name: "a", Type: TYPE_LOCAL, Path: "/tmp"}, {Name: "a", Type: TYPE_LOCAL, Path: "/tmp"}},
+	}
+	for _, confs := range invalid {
+		if _, err := FromConfig(confs); err == nil {
+			t.Fatalf("expected an error for config: %+v", confs)
+		}
+	}
+}
@@ -0,0 +1,282 @@
+package registry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+const (
+	OCI_MANIFEST_MEDIA_TYPE  = "application/vnd.oci.image.manifest.v1+json"
+	OCI_TITLE_ANNOTATION_KEY = "org.opencontainers.image.title"
+)
+
+var authParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+type (
+	// OciRegistry serves assets from an OCI registry (e.g. ghcr.io). Each release is
+	// an artifact tagged with the release tag whose layers are the release's assets,
+	// which is the layout produced by `oras push <url>:<tag> <files...>`. Only
+	// anonymous (i.e. public) repositories are supported.
+	OciRegistry struct {
+		HttpClient *http.Client
+		scheme     string
+		host       string
+		repository string
+		token      string
+		mutex      *sync.Mutex
+	}
+
+	ociDescriptor struct {
+		MediaType   string            `json:"mediaType"`
+		Digest      string            `json:"digest"`
+		Size        int64             `json:"size"`
+		Annotations map[string]string `json:"annotations,omitempty"`
+	}
+
+	ociManifest struct {
+		Layers []ociDescriptor `json:"layers"`
+	}
+
+	// digestReader verifies the digest of a blob once it has been read completely
+	digestReader struct {
+		io.ReadCloser
+		hash   hash.Hash
+		digest string
+	}
+)
+
+// NewOciRegistry creates a registry from a reference such as 'ghcr.io/owner/repo'.
+// The scheme defaults to https, but can be set explicitly (e.g. for a local registry
+// at 'http://localhost:5000/repo').
+func NewOciRegistry(ref string) (*OciRegistry, error) {
+	ref = strings.TrimPrefix(ref, "oci://")
+
+	scheme := "https"
+	if u, err := url.Parse(ref); err == nil && (u.Scheme == "http" || u.Scheme == "https") {
+		scheme = u.Scheme
+		ref = strings.TrimPrefix(ref, u.Scheme+"://")
+	}
+
+	host, repository, found := strings.Cut(strings.Trim(ref, "/"), "/")
+	if !found || host == "" || repository == "" {
+		return nil, fmt.Errorf("invalid OCI reference '%s' - expected '<host>/<repository>'", ref)
+	}
+
+	return &OciRegistry{
+		HttpClient: &http.Client{},
+		scheme:     scheme,
+		host:       host,
+		repository: repository,
+		mutex:      &sync.Mutex{},
+	}, nil
+}
+
+func (r *OciRegistry) url(format string, args ...any) string {
+	return fmt.Sprintf("%s://%s/v2/%s/%s", r.scheme, r.host, r.repository, fmt.Sprintf(format, args...))
+}
+
+// authenticate requests an anonymous bearer token as described by the challenge in
+// the WWW-Authenticate header of a 401 response.
+func (r *OciRegistry) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return fmt.Errorf("unsupported authentication scheme '%s'", scheme)
+	}
+
+	values := map[string]string{}
+	for _, match := range authParamRegex.FindAllStringSubmatch(params, -1) {
+		values[match[1]] = match[2]
+	}
+
+	realm, err := url.Parse(values["realm"])
+	if err != nil || values["realm"] == "" {
+		return fmt.Errorf("invalid authentication realm '%s'", values["realm"])
+	}
+
+	query := realm.Query()
+	for _, key := range []string{"service", "scope"} {
+		if values[key] != "" {
+			query.Set(key, values[key])
+		}
+	}
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", realm.String(), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := r.HttpClient.Do(req)
+	if err != nil {
+		return err
+	} else {
+		defer res.Body.Close()
+	}
+
+	if res.StatusCode != http.StatusOK {
+		return &HttpStatusError{Url: realm.String(), StatusCode: res.StatusCode}
+	}
+
+	var data struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if data.Token != "" {
+		r.token = data.Token
+	} else {
+		r.token = data.AccessToken
+	}
+
+	return nil
+}
+
+func (r *OciRegistry) get(ctx context.Context, url string, accept string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+		if err != nil {
+			return nil, err
+		}
+		if accept != "" {
+			req.Header.Set("Accept", accept)
+		}
+
+		r.mutex.Lock()
+		if r.token != "" {
+			req.Header.Set("Authorization", "Bearer "+r.token)
+		}
+		r.mutex.Unlock()
+
+		res, err := r.HttpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		if res.StatusCode == http.StatusUnauthorized && attempt == 0 {
+			res.Body.Close()
+			if err := r.authenticate(ctx, res.Header.Get("WWW-Authenticate")); err != nil {
+				return nil, err
+			} else {
+				continue
+			}
+		}
+
+		if res.StatusCode != http.StatusOK {
+			res.Body.Close()
+			return nil, &HttpStatusError{Url: url, StatusCode: res.StatusCode}
+		} else {
+			return res, nil
+		}
+	}
+}
+
+func (r *OciRegistry) manifest(ctx context.Context, tag string) (*ociManifest, error) {
+	res, err := r.get(ctx, r.url("manifests/%s", url.PathEscape(tag)), OCI_MANIFEST_MEDIA_TYPE)
+	statusErr := &HttpStatusError{}
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+		return nil, &ReleaseNotFoundError{Tag: tag}
+	}
+	if err != nil {
+		return nil, err
+	} else {
+		defer res.Body.Close()
+	}
+
+	var manifest ociManifest
+	if err := json.NewDecoder(res.Body).Decode(&manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse manifest of '%s': %w", tag, err)
+	} else {
+		return &manifest, nil
+	}
+}
+
+func (r *OciRegistry) Releases(ctx context.Context) ([]string, error) {
+	res, err := r.get(ctx, r.url("tags/list"), "")
+	if err != nil {
+		return nil, err
+	} else {
+		defer res.Body.Close()
+	}
+
+	var data struct {
+		Tags []string `json:"tags"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return nil, fmt.Errorf("failed to parse tags of '%s': %w", r.repository, err)
+	} else {
+		return data.Tags, nil
+	}
+}
+
+func (r *OciRegistry) Assets(ctx context.Context, tag string) ([]string, error) {
+	manifest, err := r.manifest(ctx, tag)
+	if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, layer := range manifest.Layers {
+		if name := layer.Annotations[OCI_TITLE_ANNOTATION_KEY]; name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names, nil
+}
+
+func (r *OciRegistry) Open(ctx context.Context, tag string, name string) (io.ReadCloser, error) {
+	manifest, err := r.manifest(ctx, tag)
+	if errors.Is(err, &ReleaseNotFoundError{}) {
+		return nil, &AssetNotFoundError{Tag: tag, Name: name}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	for _, layer := range manifest.Layers {
+		if layer.Annotations[OCI_TITLE_ANNOTATION_KEY] != name {
+			continue
+		}
+
+		digest, found := strings.CutPrefix(layer.Digest, "sha256:")
+		if !found {
+			return nil, fmt.Errorf("unsupported digest '%s' for asset '%s'", layer.Digest, name)
+		}
+
+		res, err := r.get(ctx, r.url("blobs/%s", layer.Digest), "")
+		if err != nil {
+			return nil, err
+		} else {
+			return &digestReader{ReadCloser: res.Body, hash: sha256.New(), digest: digest}, nil
+		}
+	}
+
+	return nil, &AssetNotFoundError{Tag: tag, Name: name}
+}
+
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		if actual := hex.EncodeToString(r.hash.Sum(nil)); actual != r.digest {
+			return n, fmt.Errorf("blob digest mismatch - expected sha256:%s but got sha256:%s", r.digest, actual)
+		}
+	}
+	return n, err
+}
@@ -0,0 +1,22 @@
+package supervisor
+
+import "fmt"
+
+type ExitError struct {
+	Code     int
+	Restarts int
+	Err      error
+}
+
+func (e *ExitError) Error() string {
+	return fmt.Sprintf("plugin exited with code %d after %d restart(s): %v", e.Code, e.Restarts, e.Err)
+}
+
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+func (e *ExitError) Is(target error) bool {
+	_, ok := target.(*ExitError)
+	return ok
+}
@@ -0,0 +1,240 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+)
+
+type RestartPolicy string
+
+const (
+	RestartAlways    RestartPolicy = "always"
+	RestartOnFailure RestartPolicy = "on-failure"
+	RestartNever     RestartPolicy = "never"
+)
+
+const (
+	DEFAULT_MAX_RESTARTS = 5
+	DEFAULT_WINDOW       = time.Minute * 5
+	DEFAULT_BACKOFF      = time.Second
+	DEFAULT_MAX_BACKOFF  = time.Minute
+	DEFAULT_KILL_TIMEOUT = time.Second * 10
+)
+
+type (
+	Options struct {
+		Policy RestartPolicy
+
+		// MaxRestarts is the maximum number of restarts allowed within Window. Once the
+		// limit is reached the supervisor gives up. If zero, restarts are unlimited.
+		MaxRestarts int64
+		Window      time.Duration
+
+		// Backoff is the delay before the first restart. It doubles after each restart
+		// up to MaxBackoff and is reset once the plugin stays up for longer than Window.
+		Backoff    time.Duration
+		MaxBackoff time.Duration
+
+		KillTimeout time.Duration
+	}
+
+	StartFunc func(ctx context.Context, killTimeout time.Duration) (*plgn.Process, error)
+
+	// Hooks are optional callbacks that let callers track the state of the plugin.
+	// OnReady is called every time the plugin completes the handshake, and OnExit is
+	// called every time a ready plugin exits (including on shutdown).
+	Hooks struct {
+		OnReady func(proc *plgn.Process)
+		OnExit  func(exitCode int, err error)
+	}
+
+	Supervisor struct {
+		opts   Options
+		start  StartFunc
+		hooks  Hooks
+		logger *log.Logger
+	}
+)
+
+func ParseRestartPolicy(policy string) (RestartPolicy, error) {
+	switch RestartPolicy(policy) {
+	case RestartAlways, RestartOnFailure, RestartNever:
+		return RestartPolicy(policy), nil
+	case "":
+		return RestartOnFailure, nil
+	default:
+		return "", fmt.Errorf(
+			"invalid restart policy '%s' - must be one of: [ %s, %s, %s ]",
+			policy,
+			RestartAlways,
+			RestartOnFailure,
+			RestartNever,
+		)
+	}
+}
+
+// NewOptions converts the restart settings from a chain config into supervisor
+// options. Any settings that are missing from the config fall back to defaults.
+func NewOptions(conf *config.RestartConfig) (Options, error) {
+	opts := Options{
+		Policy:      RestartOnFailure,
+		MaxRestarts: DEFAULT_MAX_RESTARTS,
+		Window:      DEFAULT_WINDOW,
+		Backoff:     DEFAULT_BACKOFF,
+		MaxBackoff:  DEFAULT_MAX_BACKOFF,
+		KillTimeout: DEFAULT_KILL_TIMEOUT,
+	}
+
+	if conf == nil {
+		return opts, nil
+	}
+
+	policy, err := ParseRestartPolicy(conf.Policy)
+	if err != nil {
+		return opts, err
+	} else {
+		opts.Policy = policy
+	}
+
+	// NOTE: zero means "use the default" in the config, so unlimited restarts must be
+	// requested explicitly with a negative value
+	if conf.MaxRestarts < 0 {
+		opts.MaxRestarts = 0
+	} else if conf.MaxRestarts > 0 {
+		opts.MaxRestarts = conf.MaxRestarts
+	}
+
+	if conf.Window > 0 {
+		opts.Window = conf.Window.Duration()
+	}
+	if conf.Backoff > 0 {
+		opts.Backoff = conf.Backoff.Duration()
+	}
+	if conf.MaxBackoff > 0 {
+		opts.MaxBackoff = conf.MaxBackoff.Duration()
+	}
+	if conf.KillTimeout > 0 {
+		opts.KillTimeout = conf.KillTimeout.Duration()
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = opts.Backoff
+	}
+
+	return opts, nil
+}
+
+func New(opts Options, start StartFunc, logger *log.Logger) *Supervisor {
+	return &Supervisor{opts: opts, start: start, logger: logger}
+}
+
+func (s *Supervisor) WithHooks(hooks Hooks) *Supervisor {
+	s.hooks = hooks
+	return s
+}
+
+func (s *Supervisor) shouldRestart(exitErr error) bool {
+	switch s.opts.Policy {
+	case RestartAlways:
+		return true
+	case RestartOnFailure:
+		return exitErr != nil
+	default:
+		return false
+	}
+}
+
+func (s *Supervisor) run(ctx context.Context) (int, error) {
+	proc, err := s.start(ctx, s.opts.KillTimeout)
+	if err != nil {
+		return -1, err
+	} else {
+		s.logger.Printf("Plugin is ready (%s)", proc)
+	}
+
+	if s.hooks.OnReady != nil {
+		s.hooks.OnReady(proc)
+	}
+
+	err = proc.Wait()
+	if s.hooks.OnExit != nil {
+		s.hooks.OnExit(proc.ExitCode(), err)
+	}
+
+	return proc.ExitCode(), err
+}
+
+// Run starts the plugin and keeps it running according to the restart policy. It
+// returns nil once the context is cancelled and the plugin has shut down. If the
+// plugin exits and is not restarted, then an *ExitError is returned.
+func (s *Supervisor) Run(ctx context.Context) error {
+	restarts := []time.Time{}
+	backoff := s.opts.Backoff
+
+	for {
+		startedAt := time.Now()
+		exitCode, err := s.run(ctx)
+		if ctx.Err() != nil {
+			s.logger.Printf("Plugin shut down (exit code = %d)", exitCode)
+			return nil
+		}
+
+		uptime := time.Since(startedAt)
+		if err != nil {
+			s.logger.Printf("Plugin exited after %s (exit code = %d): %v", uptime.Round(time.Millisecond), exitCode, err)
+		} else {
+			s.logger.Printf("Plugin exited after %s (exit code = %d)", uptime.Round(time.Millisecond), exitCode)
+		}
+
+		if !s.shouldRestart(err) {
+			if err == nil {
+				return nil
+			} else {
+				return &ExitError{Code: exitCode, Restarts: len(restarts), Err: err}
+			}
+		}
+
+		// NOTE: if the plugin managed to stay up for a while, then it is no longer
+		// considered to be crash-looping, so the backoff starts over
+		if uptime > s.opts.Window {
+			backoff = s.opts.Backoff
+		}
+
+		now := time.Now()
+		recent := []time.Time{}
+		for _, t := range restarts {
+			if now.Sub(t) < s.opts.Window {
+				recent = append(recent, t)
+			}
+		}
+		restarts = recent
+
+		if s.opts.MaxRestarts > 0 && int64(len(restarts)) >= s.opts.MaxRestarts {
+			return &ExitError{
+				Code:     exitCode,
+				Restarts: len(restarts),
+				Err: errors.Join(
+					fmt.Errorf("plugin restarted %d time(s) within %s - giving up", len(restarts), s.opts.Window),
+					err,
+				),
+			}
+		}
+
+		s.logger.Printf("Restarting plugin in %s (policy = %s)", backoff, s.opts.Policy)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		restarts = append(restarts, time.Now())
+		backoff = min(backoff*2, s.opts.MaxBackoff)
+	}
+}
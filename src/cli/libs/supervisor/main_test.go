@@ -0,0 +1,213 @@
+package supervisor
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/plgn"
+)
+
+const HANDSHAKE = `read -r req
+echo 'CC_HANDSHAKE {"protocolVersion":1,"capabilities":["cursors"],"address":"127.0.0.1:3000"}'
+`
+
+// logs is a log writer that can be read while the supervisor is writing to it
+type logs struct {
+	buf   bytes.Buffer
+	mutex sync.Mutex
+}
+
+func (l *logs) Write(p []byte) (int, error) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.buf.Write(p)
+}
+
+func (l *logs) String() string {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	return l.buf.String()
+}
+
+// newStartFunc returns a start function that runs a shell script as a plugin, along
+// with the number of times that the plugin was started. The script must complete the
+// handshake (e.g. with HANDSHAKE).
+func newStartFunc(t *testing.T, script string) (StartFunc, *atomic.Int64) {
+	store := &plgn.PluginStore{Dir: t.TempDir()}
+	pluginPath := filepath.Join(store.Dir, "stub", "1.0.0", "bin")
+	if err := os.MkdirAll(filepath.Dir(pluginPath), os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(pluginPath, []byte("#!/bin/sh\n"+script), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	conf := &config.ChainConfig{
+		Plugin: &config.PluginConfig{ID: "stub"},
+		Server: &config.ServerConfig{Host: "localhost", Port: 3000},
+		Conn:   &config.ConnectionConfg{Wss: "wss://eth.example.com"},
+	}
+
+	starts := &atomic.Int64{}
+	return func(ctx context.Context, killTimeout time.Duration) (*plgn.Process, error) {
+		starts.Add(1)
+		return store.Start(ctx, conf, plgn.ProcessOptions{Stdout: io.Discard, Stderr: io.Discard, KillTimeout: killTimeout})
+	}, starts
+}
+
+func newOptions(policy RestartPolicy, maxRestarts int64) Options {
+	return Options{
+		Policy:      policy,
+		MaxRestarts: maxRestarts,
+		Window:      time.Minute,
+		Backoff:     time.Millisecond,
+		MaxBackoff:  time.Millisecond,
+		KillTimeout: time.Second,
+	}
+}
+
+func TestRestartPolicies(t *testing.T) {
+	testCases := []struct {
+		name     string
+		policy   RestartPolicy
+		exitCode int
+		starts   int64
+		err      bool
+	}{
+		{name: "always restarts after success", policy: RestartAlways, exitCode: 0, starts: 3, err: true},
+		{name: "always restarts after failure", policy: RestartAlways, exitCode: 3, starts: 3, err: true},
+		{name: "on-failure stops after success", policy: RestartOnFailure, exitCode: 0, starts: 1, err: false},
+		{name: "on-failure restarts after failure", policy: RestartOnFailure, exitCode: 3, starts: 3, err: true},
+		{name: "never stops after success", policy: RestartNever, exitCode: 0, starts: 1, err: false},
+		{name: "never stops after failure", policy: RestartNever, exitCode: 3, starts: 1, err: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			start, starts := newStartFunc(t, HANDSHAKE+fmt.Sprintf("exit %d\n", tc.exitCode))
+
+			// NOTE: the supervisor gives up after two restarts
+			err := New(newOptions(tc.policy, 2), start, log.New(io.Discard, "", 0)).Run(context.Background())
+			if starts.Load() != tc.starts {
+				t.Fatalf("expected %d start(s) but got %d", tc.starts, starts.Load())
+			}
+			if !tc.err {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+
+			exitErr := &ExitError{}
+			if !errors.As(err, &exitErr) {
+				t.Fatalf("expected an exit error but got: %v", err)
+			}
+			if exitErr.Code != tc.exitCode {
+				t.Fatalf("expected exit code %d but got %d", tc.exitCode, exitErr.Code)
+			}
+			if exitErr.Restarts != int(tc.starts-1) {
+				t.Fatalf("expected %d restart(s) but got %d", tc.starts-1, exitErr.Restarts)
+			}
+		})
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	start, starts := newStartFunc(t, HANDSHAKE+"exit 1\n")
+
+	opts := newOptions(RestartOnFailure, 5)
+	opts.Backoff = time.Millisecond * 10
+	opts.MaxBackoff = time.Millisecond * 40
+
+	out := &logs{}
+	if err := New(opts, start, log.New(out, "", 0)).Run(context.Background()); err == nil {
+		t.Fatal("expected an error")
+	}
+	if starts.Load() != 6 {
+		t.Fatalf("expected 6 starts but got %d", starts.Load())
+	}
+
+	// NOTE: the backoff doubles after every restart until it reaches the maximum
+	delays := []string{}
+	for _, match := range regexp.MustCompile(`Restarting plugin in (\S+)`).FindAllStringSubmatch(out.String(), -1) {
+		delays = append(delays, match[1])
+	}
+	if expected := "10ms,20ms,40ms,40ms,40ms"; strings.Join(delays, ",") != expected {
+		t.Fatalf("expected delays [%s] but got %v", expected, delays)
+	}
+}
+
+func TestRestartWindow(t *testing.T) {
+	// NOTE: the plugin stays up for longer than the window, so its restarts are never
+	// counted against the limit
+	start, starts := newStartFunc(t, HANDSHAKE+"sleep 0.1\nexit 1\n")
+
+	opts := newOptions(RestartOnFailure, 1)
+	opts.Window = time.Millisecond * 50
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+
+	if err := New(opts, start, log.New(io.Discard, "", 0)).Run(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if starts.Load() < 3 {
+		t.Fatalf("expected the plugin to keep restarting but it was started %d time(s)", starts.Load())
+	}
+}
+
+func TestKillTimeout(t *testing.T) {
+	testCases := []struct {
+		name   string
+		script string
+		min    time.Duration
+		max    time.Duration
+	}{
+		{name: "exits on SIGTERM", script: "trap 'exit 0' TERM\n" + HANDSHAKE + "while :; do sleep 0.01; done\n", min: 0, max: time.Millisecond * 500},
+		{name: "killed after timeout", script: "trap '' TERM\n" + HANDSHAKE + "while :; do sleep 0.01; done\n", min: time.Millisecond * 500, max: time.Second * 5},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// NOTE: the traps are set before the handshake, since the supervisor can stop
+			// the plugin as soon as it is ready
+			start, _ := newStartFunc(t, tc.script)
+
+			opts := newOptions(RestartAlways, 0)
+			opts.KillTimeout = time.Millisecond * 500
+
+			ctx, cancel := context.WithCancel(context.Background())
+			ready := make(chan struct{})
+			hooks := Hooks{OnReady: func(proc *plgn.Process) { close(ready) }}
+
+			errs := make(chan error, 1)
+			go func() {
+				errs <- New(opts, start, log.New(io.Discard, "", 0)).WithHooks(hooks).Run(ctx)
+			}()
+
+			<-ready
+			cancelledAt := time.Now()
+			cancel()
+
+			if err := <-errs; err != nil {
+				t.Fatal(err)
+			}
+			if elapsed := time.Since(cancelledAt); elapsed < tc.min || elapsed > tc.max {
+				t.Fatalf("expected the plugin to stop within [%s, %s] but it took %s", tc.min, tc.max, elapsed)
+			}
+		})
+	}
+}
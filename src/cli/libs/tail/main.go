@@ -0,0 +1,173 @@
+package tail
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/gateway"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	DEFAULT_BACKOFF     = time.Second
+	DEFAULT_MAX_BACKOFF = time.Second * 30
+)
+
+type Options struct {
+	Address string
+
+	// Chain is sent with every call (as the x-chain metadata key) so that a gateway
+	// can route the call to the right plugin. Plugins ignore it.
+	Chain string
+
+	// Start is the first cursor to stream (nil for the latest cursor) and End is the
+	// last cursor to stream (nil to stream forever).
+	Start *big.Int
+	End   *big.Int
+
+	// Reconnect makes Tail reconnect whenever the stream fails with a transient error
+	// (e.g. while the plugin restarts) and resume right after the last cursor that it
+	// received. The delay between attempts starts at Backoff and doubles up to
+	// MaxBackoff.
+	Reconnect  bool
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+
+	Logger *log.Logger
+}
+
+var errEndReached = errors.New("end cursor reached")
+
+// Tail streams cursors from a plugin (or a gateway) and calls the callback once for
+// each cursor. It returns nil once the end cursor has been received or the context
+// is cancelled. If the callback fails, then its error is returned.
+func Tail(ctx context.Context, opts Options, cb func(cursor *big.Int) error) error {
+	if opts.Start != nil && opts.End != nil && opts.Start.Cmp(opts.End) == 1 {
+		return fmt.Errorf("start cursor %s is above the end cursor %s", opts.Start, opts.End)
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = DEFAULT_BACKOFF
+	}
+	if opts.MaxBackoff <= 0 {
+		opts.MaxBackoff = DEFAULT_MAX_BACKOFF
+	}
+	if opts.MaxBackoff < opts.Backoff {
+		opts.MaxBackoff = opts.Backoff
+	}
+	if opts.Logger == nil {
+		opts.Logger = log.New(io.Discard, "", 0)
+	}
+
+	conn, err := grpc.NewClient(opts.Address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return err
+	} else {
+		defer conn.Close()
+	}
+
+	if opts.Chain != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, gateway.METADATA_KEY_CHAIN, opts.Chain)
+	}
+
+	client := pb.NewChainCursorClient(conn)
+	next := opts.Start
+	backoff := opts.Backoff
+	for {
+		received, err := stream(ctx, client, next, opts.End, func(cursor *big.Int) error {
+			next = new(big.Int).Add(cursor, big.NewInt(1))
+			return cb(cursor)
+		})
+		if errors.Is(err, errEndReached) || ctx.Err() != nil {
+			return nil
+		}
+		if !opts.Reconnect || !IsRetryable(err) {
+			return err
+		}
+
+		if received {
+			backoff = opts.Backoff
+		}
+
+		opts.Logger.Printf("Stream failed (%v) - reconnecting in %s", err, backoff)
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+		}
+
+		backoff = min(backoff*2, opts.MaxBackoff)
+	}
+}
+
+// IsRetryable returns true if the stream failed with a gRPC error that is expected to
+// go away on its own (e.g. the plugin is unavailable while it restarts).
+func IsRetryable(err error) bool {
+	s, ok := status.FromError(err)
+	if !ok {
+		return false
+	}
+
+	switch s.Code() {
+	case codes.Unavailable, codes.Unknown, codes.Internal, codes.Aborted, codes.ResourceExhausted, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// stream opens a single stream and reports whether any cursors were received before
+// it failed.
+func stream(ctx context.Context, client pb.ChainCursorClient, start *big.Int, end *big.Int, cb func(cursor *big.Int) error) (bool, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req := &pb.StartCursor{}
+	if start != nil {
+		value := start.String()
+		req.Value = &value
+	}
+
+	cursors, err := client.Cursors(ctx, req)
+	if err != nil {
+		return false, err
+	}
+
+	received := false
+	for {
+		msg, err := cursors.Recv()
+		if errors.Is(err, io.EOF) {
+			return received, status.Error(codes.Unavailable, "stream was closed by the server")
+		}
+		if err != nil {
+			return received, err
+		}
+
+		cursor, ok := new(big.Int).SetString(msg.GetValue(), 10)
+		if !ok {
+			return received, fmt.Errorf("received an invalid cursor '%s'", msg.GetValue())
+		}
+		if end != nil && cursor.Cmp(end) == 1 {
+			return received, errEndReached
+		}
+
+		received = true
+		if err := cb(cursor); err != nil {
+			return received, err
+		}
+		if end != nil && cursor.Cmp(end) == 0 {
+			return received, errEndReached
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package tail
+
+import (
+	"context"
+	"math/big"
+	"net"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// flakyServer sends a few cursors on each stream and then fails with a transient
+// error, so clients have to reconnect to receive more cursors.
+type flakyServer struct {
+	pb.UnimplementedChainCursorServer
+	mutex  *sync.Mutex
+	starts []string
+	chains []string
+}
+
+func (s *flakyServer) Cursors(start *pb.StartCursor, stream grpc.ServerStreamingServer[pb.Cursor]) error {
+	md, _ := metadata.FromIncomingContext(stream.Context())
+
+	s.mutex.Lock()
+	s.starts = append(s.starts, start.GetValue())
+	s.chains = append(s.chains, md.Get("x-chain")...)
+	s.mutex.Unlock()
+
+	cur := big.NewInt(100)
+	if start.Value != nil {
+		cur.SetString(start.GetValue(), 10)
+	}
+
+	for range 3 {
+		if err := stream.Send(&pb.Cursor{Value: cur.String()}); err != nil {
+			return err
+		}
+		cur.Add(cur, big.NewInt(1))
+	}
+
+	return status.Error(codes.Unavailable, "plugin is restarting")
+}
+
+func serve(t *testing.T, server *flakyServer) string {
+	lis, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	grpcServer := grpc.NewServer()
+	pb.RegisterChainCursorServer(grpcServer, server)
+	go grpcServer.Serve(lis)
+	t.Cleanup(grpcServer.Stop)
+
+	return lis.Addr().String()
+}
+
+func TestTailResumesAfterFailures(t *testing.T) {
+	server := &flakyServer{mutex: &sync.Mutex{}}
+	opts := Options{
+		Address:   serve(t, server),
+		Chain:     "eth",
+		End:       big.NewInt(106),
+		Reconnect: true,
+		Backoff:   time.Millisecond,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	received := []string{}
+	if err := Tail(ctx, opts, func(cursor *big.Int) error {
+		received = append(received, cursor.String())
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"100", "101", "102", "103", "104", "105", "106"}
+	if !slices.Equal(received, expected) {
+		t.Fatalf("expected cursors %v but got %v", expected, received)
+	}
+
+	// NOTE: the first stream starts at the latest cursor and each reconnect resumes
+	// right after the last cursor that was received
+	if !slices.Equal(server.starts, []string{"", "103", "106"}) {
+		t.Fatalf("unexpected start cursors: %v", server.starts)
+	}
+	if !slices.Equal(server.chains, []string{"eth", "eth", "eth"}) {
+		t.Fatalf("unexpected chain metadata: %v", server.chains)
+	}
+}
+
+func TestTailWithoutReconnect(t *testing.T) {
+	server := &flakyServer{mutex: &sync.Mutex{}}
+	opts := Options{Address: serve(t, server), Start: big.NewInt(5)}
+
+	received := 0
+	err := Tail(context.Background(), opts, func(cursor *big.Int) error {
+		received++
+		return nil
+	})
+
+	if status.Code(err) != codes.Unavailable || received != 3 {
+		t.Fatalf("expected the stream to fail after 3 cursors but got %d cursors and error: %v", received, err)
+	}
+}
+
+func TestTailRejectsInvalidRange(t *testing.T) {
+	opts := Options{Address: "127.0.0.1:0", Start: big.NewInt(10), End: big.NewInt(5)}
+	if err := Tail(context.Background(), opts, func(cursor *big.Int) error { return nil }); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor/beacon"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/runner"
+)
+
+var manifest = &handshake.Manifest{
+	ID:              "beacon",
+	Version:         handshake.ReleaseVersion(),
+	ProtocolVersion: handshake.PROTOCOL_VERSION,
+	Chains:          []string{"ethereum-beacon"},
+	Conn:            [][]string{{handshake.FIELD_CONN_RPC}},
+	Finality:        []string{string(beacon.ModeHead), string(beacon.ModeFinalized)},
+}
+
+func main() {
+	runner.Run(&runner.Plugin{
+		Manifest: manifest,
+		Connect:  connect,
+		Logger: func(conf *config.ChainConfig) *log.Logger {
+			return beacon.NewLogger()
+		},
+		Capabilities: []string{"finality"},
+	})
+}
+
+// connect creates a cursor for the Beacon API at the chain's RPC URL. The cursor opens
+// its own event stream (SSE) for each subscription and closes it when the subscription
+// ends, so there is no shared connection to close.
+func connect(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error) {
+	mode, err := beacon.ParseMode(conf.Finality)
+	if err != nil {
+		return nil, nil, err
+	} else {
+		return beacon.NewChainCursor(beacon.NewClient(conf.Conn.Rpc), mode), func() {}, nil
+	}
+}
@@ -2,73 +2,52 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
-	"net"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor/eth"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
-	"github.com/ethereum/go-ethereum/ethclient"
-	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/runner"
 )
 
-func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+var manifest = &handshake.Manifest{
+	ID:              "eth",
+	Version:         handshake.ReleaseVersion(),
+	ProtocolVersion: handshake.PROTOCOL_VERSION,
+	Chains:          []string{"evm"},
+	Conn:            [][]string{{handshake.FIELD_CONN_WSS, handshake.FIELD_CONN_RPC}},
+}
 
-	var arg1 []byte
-	if len(os.Args) >= 1 {
-		arg1 = []byte(os.Args[1])
-	} else {
-		arg1 = []byte("{}")
-	}
+func main() {
+	runner.Run(&runner.Plugin{
+		Manifest: manifest,
+		Connect:  connect,
+		Logger: func(conf *config.ChainConfig) *log.Logger {
+			return eth.NewLogger()
+		},
+		Capabilities: []string{"ipc", "http-polling"},
+	})
+}
 
-	var conf *config.ChainConfig
-	if err := json.Unmarshal(arg1, &conf); err != nil {
-		log.Fatal(err)
+// connect dials the chain's WSS URL, or its RPC URL if no WSS URL is given. An RPC URL
+// can either be an HTTP endpoint (polled for new heads) or an IPC socket path.
+func connect(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error) {
+	endpoint := conf.Conn.Wss
+	if endpoint == "" {
+		endpoint = conf.Conn.Rpc
 	}
 
-	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", conf.Server.Url())
+	client, transport, err := eth.Dial(ctx, endpoint)
 	if err != nil {
-		log.Fatal(err)
+		return nil, nil, err
 	} else {
-		defer lis.Close()
+		log.Printf("Connected over %s (push = %t)\n", transport, transport.IsPush())
 	}
 
-	client, err := ethclient.Dial(conf.Conn.Wss)
-	if err != nil {
-		log.Fatal(err)
+	if transport.IsPush() {
+		return eth.NewChainCursor(client), client.Close, nil
 	} else {
-		defer client.Close()
-	}
-
-	app := api.New(
-		grpc.NewServer(),
-		streamer.New(
-			eth.NewChainCursor(client),
-			eth.NewLogger(),
-		),
-	)
-
-	eg := new(errgroup.Group)
-	eg.Go(func() error {
-		return app.Stream.Subscribe(ctx)
-	})
-	eg.Go(func() error {
-		return app.Server.Serve(lis)
-	})
-
-	log.Printf("Listening on %s\n", conf.Server.Url())
-	<-ctx.Done()
-
-	app.Server.GracefulStop()
-	if err := eg.Wait(); err != nil {
-		log.Fatal(err)
+		return eth.NewPollingChainCursor(client, eth.DEFAULT_POLL_INTERVAL), client.Close, nil
 	}
 }
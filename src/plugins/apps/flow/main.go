@@ -2,78 +2,62 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
-	"net"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor/flow"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/runner"
 	"github.com/onflow/flow/protobuf/go/flow/access"
 	"github.com/onflow/flow/protobuf/go/flow/executiondata"
-	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
-func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+var manifest = &handshake.Manifest{
+	ID:              "flow",
+	Version:         handshake.ReleaseVersion(),
+	ProtocolVersion: handshake.PROTOCOL_VERSION,
+	Chains:          []string{"flow"},
+	Conn:            [][]string{{handshake.FIELD_CONN_WSS, handshake.FIELD_CONN_SPORKS}},
+}
 
-	var arg1 []byte
-	if len(os.Args) >= 1 {
-		arg1 = []byte(os.Args[1])
-	} else {
-		arg1 = []byte("{}")
-	}
+func main() {
+	runner.Run(&runner.Plugin{
+		Manifest: manifest,
+		Connect:  connect,
+		Logger: func(conf *config.ChainConfig) *log.Logger {
+			return flow.NewLogger()
+		},
+		Capabilities: []string{"sporks"},
+	})
+}
 
-	var conf *config.ChainConfig
-	if err := json.Unmarshal(arg1, &conf); err != nil {
-		log.Fatal(err)
+func connect(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error) {
+	if len(conf.Conn.Sporks) == 0 {
+		conn, err := grpc.NewClient(conf.Conn.Wss, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		chainCursor := flow.NewChainCursor(
+			executiondata.NewExecutionDataAPIClient(conn),
+			access.NewAccessAPIClient(conn),
+		)
+		return chainCursor, func() { conn.Close() }, nil
 	}
 
-	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", conf.Server.Url())
-	if err != nil {
-		log.Fatal(err)
-	} else {
-		defer lis.Close()
+	sporks := make([]flow.Spork, len(conf.Conn.Sporks))
+	for i, spork := range conf.Conn.Sporks {
+		sporks[i] = flow.Spork{Name: spork.Name, RootHeight: spork.RootHeight, AccessNode: spork.Url}
 	}
 
-	conn, err := grpc.NewClient(conf.Conn.Wss, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	table, err := flow.NewSporkTable(sporks)
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		defer conn.Close()
+		return nil, nil, err
 	}
 
-	app := api.New(
-		grpc.NewServer(),
-		streamer.New(
-			flow.NewChainCursor(
-				executiondata.NewExecutionDataAPIClient(conn),
-				access.NewAccessAPIClient(conn),
-			),
-			flow.NewLogger(),
-		),
-	)
-
-	eg := new(errgroup.Group)
-	eg.Go(func() error {
-		return app.Stream.Subscribe(ctx)
-	})
-	eg.Go(func() error {
-		return app.Server.Serve(lis)
-	})
-
-	log.Printf("Listening on %s\n", conf.Server.Url())
-	<-ctx.Done()
-
-	app.Server.GracefulStop()
-	if err := eg.Wait(); err != nil {
-		log.Fatal(err)
-	}
+	sporkCursor := flow.NewSporkChainCursor(table, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	return sporkCursor, func() { sporkCursor.Close() }, nil
 }
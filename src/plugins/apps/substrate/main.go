@@ -2,73 +2,55 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"log"
-	"net"
-	"os"
-	"os/signal"
-	"syscall"
 
 	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor/substrate"
-	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
-	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/runner"
 )
 
-func main() {
-	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
-	defer cancel()
+var manifest = &handshake.Manifest{
+	ID:              "substrate",
+	Version:         handshake.ReleaseVersion(),
+	ProtocolVersion: handshake.PROTOCOL_VERSION,
+	Chains:          []string{"substrate"},
+	Conn:            [][]string{{handshake.FIELD_CONN_WSS, handshake.FIELD_PARACHAIN_RELAY}},
+}
 
-	var arg1 []byte
-	if len(os.Args) >= 1 {
-		arg1 = []byte(os.Args[1])
-	} else {
-		arg1 = []byte("{}")
-	}
+func main() {
+	runner.Run(&runner.Plugin{
+		Manifest: manifest,
+		Connect:  connect,
+		Logger: func(conf *config.ChainConfig) *log.Logger {
+			if conf.Parachain != nil {
+				return substrate.NewParachainLogger()
+			} else {
+				return substrate.NewLogger()
+			}
+		},
+		Capabilities: []string{"parachain"},
+	})
+}
 
-	var conf *config.ChainConfig
-	if err := json.Unmarshal(arg1, &conf); err != nil {
-		log.Fatal(err)
+func connect(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error) {
+	// NOTE: in parachain mode we connect to the relay chain instead of the parachain
+	// since the parachain heights are derived from relay chain finality
+	url := conf.Conn.Wss
+	if conf.Parachain != nil {
+		url = conf.Parachain.Relay
 	}
 
-	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", conf.Server.Url())
+	client, err := gsrpc.NewSubstrateAPI(url)
 	if err != nil {
-		log.Fatal(err)
-	} else {
-		defer lis.Close()
+		return nil, nil, err
 	}
 
-	client, err := gsrpc.NewSubstrateAPI(conf.Conn.Wss)
-	if err != nil {
-		log.Fatal(err)
+	if conf.Parachain != nil {
+		return substrate.NewParachainChainCursor(client, conf.Parachain.ID), client.Client.Close, nil
 	} else {
-		defer client.Client.Close()
-	}
-
-	app := api.New(
-		grpc.NewServer(),
-		streamer.New(
-			substrate.NewChainCursor(client),
-			substrate.NewLogger(),
-		),
-	)
-
-	eg := new(errgroup.Group)
-	eg.Go(func() error {
-		return app.Stream.Subscribe(ctx)
-	})
-	eg.Go(func() error {
-		return app.Server.Serve(lis)
-	})
-
-	log.Printf("Listening on %s\n", conf.Server.Url())
-	<-ctx.Done()
-
-	app.Server.GracefulStop()
-	if err := eg.Wait(); err != nil {
-		log.Fatal(err)
+		return substrate.NewChainCursor(client), client.Client.Close, nil
 	}
 }
@@ -7,6 +7,8 @@ import (
 	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type API struct {
@@ -32,6 +34,17 @@ func (api *API) Cursors(start *pb.StartCursor, stream grpc.ServerStreamingServer
 		} else {
 			cur = cursor
 		}
+
+		earliest, err := api.Stream.GetEarliestCursor(ctx)
+		if err != nil {
+			return err
+		}
+		if earliest != nil && cur.Cmp(earliest) == -1 {
+			return status.Errorf(codes.OutOfRange, "start cursor %s is below the earliest available cursor %s", cur.String(), earliest.String())
+		}
+		if err := api.Stream.CheckCursor(ctx, cur); err != nil {
+			return err
+		}
 	}
 
 	for {
@@ -0,0 +1,116 @@
+package api
+
+import (
+	"context"
+	"io"
+	"log"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"golang.org/x/net/nettest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	EARLIEST_CURSOR = 10
+	LATEST_CURSOR   = 20
+
+	// NOTE: cursors below this value are held by an upstream that cannot serve them
+	// (e.g. a Flow spork whose access node has been shut down)
+	UNAVAILABLE_CURSOR = 15
+)
+
+// fakeCursor is a chain cursor that is bounded below and whose history is split
+// across upstreams
+type fakeCursor struct{}
+
+func (c *fakeCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(LATEST_CURSOR), nil
+}
+
+func (c *fakeCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	<-ctx.Done()
+	return nil
+}
+
+func (c *fakeCursor) GetEarliestValue(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(EARLIEST_CURSOR), nil
+}
+
+func (c *fakeCursor) CheckValue(ctx context.Context, value *big.Int) error {
+	if value.Cmp(big.NewInt(UNAVAILABLE_CURSOR)) == -1 {
+		return status.Errorf(codes.OutOfRange, "start cursor %s is held by an unavailable upstream", value.String())
+	} else {
+		return nil
+	}
+}
+
+func TestCursors(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	app := New(grpc.NewServer(), streamer.New(&fakeCursor{}, log.New(io.Discard, "", 0)))
+
+	// NOTE: the gRPC server will automatically close the listener
+	lis, err := nettest.NewLocalListener("tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+	go app.Server.Serve(lis)
+	defer app.Server.Stop()
+
+	client, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		defer client.Close()
+	}
+
+	testCases := []struct {
+		name    string
+		start   string
+		code    codes.Code
+		err     string
+		cursors []string
+	}{
+		{name: "invalid start cursor", start: "abc", code: codes.Unknown, err: "failed to convert string 'abc' to big int"},
+		{name: "below the earliest cursor", start: "9", code: codes.OutOfRange, err: "below the earliest available cursor 10"},
+		{name: "held by an unavailable upstream", start: "14", code: codes.OutOfRange, err: "held by an unavailable upstream"},
+		{name: "historical start cursor", start: "17", code: codes.OK, cursors: []string{"17", "18", "19", "20"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			stream, err := pb.NewChainCursorClient(client).Cursors(ctx, &pb.StartCursor{Value: &tc.start})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, expected := range tc.cursors {
+				if cursor, err := stream.Recv(); err != nil {
+					t.Fatal(err)
+				} else if cursor.Value != expected {
+					t.Fatalf("expected cursor %s but got %s", expected, cursor.Value)
+				}
+			}
+			if tc.code == codes.OK {
+				return
+			}
+
+			_, err = stream.Recv()
+			if status.Code(err) != tc.code || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected %s error '%s' but got: %v", tc.code, tc.err, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,179 @@
+package beacon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+const (
+	EVENT_TOPIC_HEAD                 = "head"
+	EVENT_TOPIC_FINALIZED_CHECKPOINT = "finalized_checkpoint"
+)
+
+type (
+	BeaconBlockHeaderMessage struct {
+		Slot string `json:"slot"`
+	}
+
+	BeaconBlockHeader struct {
+		Message BeaconBlockHeaderMessage `json:"message"`
+	}
+
+	BeaconBlockHeaderData struct {
+		Header BeaconBlockHeader `json:"header"`
+	}
+
+	Checkpoint struct {
+		Epoch string `json:"epoch"`
+	}
+
+	FinalityCheckpointsData struct {
+		Finalized Checkpoint `json:"finalized"`
+	}
+
+	HeadEvent struct {
+		Slot string `json:"slot"`
+	}
+
+	FinalizedCheckpointEvent struct {
+		Epoch string `json:"epoch"`
+	}
+
+	Event struct {
+		Topic string
+		Data  []byte
+	}
+
+	Client struct {
+		HttpClient *http.Client
+		Url        string
+	}
+)
+
+func NewClient(url string) *Client {
+	return &Client{HttpClient: &http.Client{}, Url: strings.TrimSuffix(url, "/")}
+}
+
+func (client *Client) handleHttpError(res *http.Response) error {
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	} else {
+		return fmt.Errorf("beacon API request failed with status %d: %s", res.StatusCode, string(body))
+	}
+}
+
+func (client *Client) get(ctx context.Context, endpoint string, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", client.Url+endpoint, nil)
+	if err != nil {
+		return nil, err
+	} else {
+		req.Header.Set("Accept", accept)
+	}
+
+	res, err := client.HttpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode != http.StatusOK {
+		defer res.Body.Close()
+		return nil, client.handleHttpError(res)
+	} else {
+		return res, nil
+	}
+}
+
+func (client *Client) getJSON(ctx context.Context, endpoint string, data any) error {
+	res, err := client.get(ctx, endpoint, "application/json")
+	if err != nil {
+		return err
+	} else {
+		defer res.Body.Close()
+	}
+
+	body := struct {
+		Data any `json:"data"`
+	}{Data: data}
+
+	return json.NewDecoder(res.Body).Decode(&body)
+}
+
+func (client *Client) GetHeadHeader(ctx context.Context) (*BeaconBlockHeaderData, error) {
+	var data BeaconBlockHeaderData
+	if err := client.getJSON(ctx, "/eth/v1/beacon/headers/head", &data); err != nil {
+		return nil, err
+	} else {
+		return &data, nil
+	}
+}
+
+func (client *Client) GetFinalityCheckpoints(ctx context.Context) (*FinalityCheckpointsData, error) {
+	var data FinalityCheckpointsData
+	if err := client.getJSON(ctx, "/eth/v1/beacon/states/head/finality_checkpoints", &data); err != nil {
+		return nil, err
+	} else {
+		return &data, nil
+	}
+}
+
+// Events opens a server-sent events stream for the given topics and invokes the
+// callback once for each event that is received. It returns when the context is
+// cancelled, when the server closes the stream (in which case nil is returned), or
+// when the callback fails.
+func (client *Client) Events(ctx context.Context, topics []string, cb func(event *Event) error) error {
+	res, err := client.get(ctx, "/eth/v1/events?topics="+strings.Join(topics, ","), "text/event-stream")
+	if err != nil {
+		return err
+	} else {
+		defer res.Body.Close()
+	}
+
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	event := &Event{}
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		// NOTE: an empty line marks the end of an event - see the SSE spec for details:
+		// https://html.spec.whatwg.org/multipage/server-sent-events.html
+		if line == "" {
+			if event.Topic != "" || len(event.Data) != 0 {
+				if err := cb(event); err != nil {
+					return err
+				}
+			}
+			event = &Event{}
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			event.Topic = value
+		case "data":
+			if len(event.Data) != 0 {
+				event.Data = append(event.Data, '\n')
+			}
+			event.Data = append(event.Data, value...)
+		}
+	}
+
+	if err := scanner.Err(); err != nil && !errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		return err
+	} else {
+		return nil
+	}
+}
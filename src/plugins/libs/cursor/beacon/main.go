@@ -0,0 +1,162 @@
+package beacon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+)
+
+type Mode string
+
+const (
+	ModeHead      Mode = "head"
+	ModeFinalized Mode = "finalized"
+)
+
+// Beacon nodes close event streams (e.g. idle ones), so the cursor re-opens the stream
+// when that happens. If the stream did not deliver any events, then the delay before
+// the next attempt doubles up to the maximum.
+const (
+	RECONNECT_BACKOFF     = time.Second
+	RECONNECT_MAX_BACKOFF = time.Second * 30
+)
+
+type ChainCursor struct {
+	client *Client
+	mode   Mode
+}
+
+func NewChainCursor(client *Client, mode Mode) cursor.Cursor {
+	return &ChainCursor{client: client, mode: mode}
+}
+
+func NewLogger() *log.Logger {
+	return log.New(os.Stdout, fmt.Sprintf("[%s] ", "beacon-slot-cursor"), log.LstdFlags)
+}
+
+func ParseMode(mode string) (Mode, error) {
+	switch Mode(mode) {
+	case "", ModeHead:
+		return ModeHead, nil
+	case ModeFinalized:
+		return ModeFinalized, nil
+	default:
+		return "", fmt.Errorf("invalid beacon cursor mode '%s' - must be one of: [ %s, %s ]", mode, ModeHead, ModeFinalized)
+	}
+}
+
+func (streamer *ChainCursor) topic() string {
+	if streamer.mode == ModeFinalized {
+		return EVENT_TOPIC_FINALIZED_CHECKPOINT
+	} else {
+		return EVENT_TOPIC_HEAD
+	}
+}
+
+func (streamer *ChainCursor) parseEvent(event *Event) (*big.Int, error) {
+	var value string
+
+	switch event.Topic {
+	case EVENT_TOPIC_HEAD:
+		var data HeadEvent
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		} else {
+			value = data.Slot
+		}
+	case EVENT_TOPIC_FINALIZED_CHECKPOINT:
+		var data FinalizedCheckpointEvent
+		if err := json.Unmarshal(event.Data, &data); err != nil {
+			return nil, err
+		} else {
+			value = data.Epoch
+		}
+	default:
+		return nil, nil
+	}
+
+	return parseUint(value)
+}
+
+func (streamer *ChainCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	topic := streamer.topic()
+	backoff := RECONNECT_BACKOFF
+
+	var last *big.Int = nil
+	for {
+		received := false
+		err := streamer.client.Events(ctx, []string{topic}, func(event *Event) error {
+			if event.Topic != topic {
+				return nil
+			}
+
+			value, err := streamer.parseEvent(event)
+			if err != nil {
+				return err
+			} else {
+				received = true
+			}
+
+			// NOTE: re-orgs can cause the head slot to be reported more than once (or even
+			// move backwards), so we only notify the callback if the cursor has advanced
+			if last == nil || last.Cmp(value) == -1 {
+				cb(value)
+				last = value
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		// NOTE: the server closed the stream cleanly - a stream that delivered events is
+		// re-opened right away, otherwise the next attempt is delayed
+		if received {
+			backoff = RECONNECT_BACKOFF
+			continue
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case <-timer.C:
+			backoff = min(backoff*2, RECONNECT_MAX_BACKOFF)
+		}
+	}
+}
+
+func (streamer *ChainCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	if streamer.mode == ModeFinalized {
+		if checkpoints, err := streamer.client.GetFinalityCheckpoints(ctx); err != nil {
+			return nil, err
+		} else {
+			return parseUint(checkpoints.Finalized.Epoch)
+		}
+	} else {
+		if header, err := streamer.client.GetHeadHeader(ctx); err != nil {
+			return nil, err
+		} else {
+			return parseUint(header.Header.Message.Slot)
+		}
+	}
+}
+
+func parseUint(value string) (*big.Int, error) {
+	if n, ok := new(big.Int).SetString(value, 10); !ok || n.Sign() == -1 {
+		return nil, fmt.Errorf("failed to convert string '%s' to big int", value)
+	} else {
+		return n, nil
+	}
+}
@@ -0,0 +1,133 @@
+package beacon
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/testutils/beacon_testutils"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/testutils/consumer_testutils"
+	"golang.org/x/net/nettest"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+const (
+	TESTS_DUR  = time.Millisecond * 3750
+	SLOT_DELAY = time.Millisecond * 100
+	SLOT_COUNT = 1
+)
+
+func TestBeaconHead(t *testing.T) {
+	testBeacon(t, ModeHead, func(backend *beacon_testutils.Backend) uint64 {
+		return backend.LatestSlot()
+	})
+}
+
+func TestBeaconFinalized(t *testing.T) {
+	testBeacon(t, ModeFinalized, func(backend *beacon_testutils.Backend) uint64 {
+		return backend.LatestEpoch()
+	})
+}
+
+func testBeacon(t *testing.T, mode Mode, latest func(backend *beacon_testutils.Backend) uint64) {
+	mockConsumer := consumer_testutils.NewChainCursorConsumer()
+	ctx := context.Background()
+	eg := new(errgroup.Group)
+
+	// NOTE: the gRPC server will automatically close the listener
+	lis, err := nettest.NewLocalListener("tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	backend := beacon_testutils.InitBackend()
+	t.Cleanup(backend.Close)
+
+	app := api.New(
+		grpc.NewServer(),
+		streamer.New(
+			NewChainCursor(NewClient(backend.Url()), mode),
+			NewLogger(),
+		),
+	)
+
+	testCtx, testCancel := context.WithTimeout(ctx, TESTS_DUR)
+	defer testCancel()
+
+	eg.Go(func() error {
+		return beacon_testutils.
+			NewSlotGenerator(
+				backend,
+				beacon_testutils.NewSlotGeneratorLogger(),
+			).
+			Start(testCtx, SLOT_DELAY, SLOT_COUNT)
+	})
+	eg.Go(func() error {
+		return app.Stream.Subscribe(testCtx)
+	})
+	eg.Go(func() error {
+		return app.Server.Serve(lis)
+	})
+	eg.Go(func() error {
+		return mockConsumer.Listen(testCtx, lis.Addr().String())
+	})
+
+	<-testCtx.Done()
+	if err := mockConsumer.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Server.GracefulStop()
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+
+	mockConsumer.AssertCursorsNotEmpty(t)
+	mockConsumer.AssertCursorsInSync(t, latest(backend))
+	mockConsumer.AssertCursorsInOrder(t)
+}
+
+func TestBeaconReconnect(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), TESTS_DUR)
+	defer cancel()
+
+	backend := beacon_testutils.InitBackend()
+	t.Cleanup(backend.Close)
+
+	cursors := make(chan uint64, 10)
+	streamer := NewChainCursor(NewClient(backend.Url()), ModeHead)
+
+	eg := new(errgroup.Group)
+	eg.Go(func() error {
+		return streamer.Subscribe(ctx, func(cursor *big.Int) { cursors <- cursor.Uint64() })
+	})
+
+	// NOTE: beacon nodes close event streams, which should not end the subscription
+	for stream := 1; stream <= 3; stream++ {
+		waitForStreams(t, ctx, backend, stream)
+		if slot := backend.Advance(); slot != <-cursors {
+			t.Fatalf("expected cursor %d to be received on stream %d", slot, stream)
+		}
+		backend.CloseStreams()
+	}
+
+	waitForStreams(t, ctx, backend, 4)
+	cancel()
+	if err := eg.Wait(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func waitForStreams(t *testing.T, ctx context.Context, backend *beacon_testutils.Backend, count int) {
+	for backend.Streams() < count {
+		select {
+		case <-ctx.Done():
+			t.Fatalf("expected %d event streams to be opened but got %d", count, backend.Streams())
+		case <-time.After(time.Millisecond * 10):
+		}
+	}
+}
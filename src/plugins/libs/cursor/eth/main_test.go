@@ -6,9 +6,11 @@ import (
 	"time"
 
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/testutils/consumer_testutils"
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/testutils/eth_testutils"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
 	"golang.org/x/net/nettest"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
@@ -18,9 +20,22 @@ const (
 	TESTS_DUR = time.Millisecond * 3750
 	TXN_DELAY = time.Millisecond * 100
 	TXN_COUNT = 1
+	POLL_RATE = time.Millisecond * 10
 )
 
 func TestEth(t *testing.T) {
+	testEth(t, func(backend *simulated.Backend) cursor.Cursor {
+		return NewChainCursor(backend.Client())
+	})
+}
+
+func TestEthPolling(t *testing.T) {
+	testEth(t, func(backend *simulated.Backend) cursor.Cursor {
+		return NewPollingChainCursor(backend.Client(), POLL_RATE)
+	})
+}
+
+func testEth(t *testing.T, newChainCursor func(backend *simulated.Backend) cursor.Cursor) {
 	mockConsumer := consumer_testutils.NewChainCursorConsumer()
 	ctx := context.Background()
 	eg := new(errgroup.Group)
@@ -50,7 +65,7 @@ func TestEth(t *testing.T) {
 	app := api.New(
 		grpc.NewServer(),
 		streamer.New(
-			NewChainCursor(backend.Client()),
+			newChainCursor(backend),
 			NewLogger(),
 		),
 	)
@@ -0,0 +1,66 @@
+package eth
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"github.com/ethereum/go-ethereum"
+)
+
+const DEFAULT_POLL_INTERVAL = time.Second * 2
+
+type PollingChainCursor struct {
+	client   ethereum.BlockNumberReader
+	interval time.Duration
+}
+
+// NewPollingChainCursor creates a cursor for transports that do not support
+// subscriptions (e.g. plain HTTP endpoints). Instead of waiting for new heads to
+// be pushed to it, the cursor periodically asks the node for the latest block.
+func NewPollingChainCursor(client ethereum.BlockNumberReader, interval time.Duration) cursor.Cursor {
+	return &PollingChainCursor{client: client, interval: interval}
+}
+
+func (streamer *PollingChainCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	timer := time.NewTimer(streamer.interval)
+	defer timer.Stop()
+
+	var lastBlockNum *uint64 = nil
+	for {
+		timer.Reset(streamer.interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-timer.C:
+			if !ok {
+				return nil
+			}
+
+			blockNum, err := streamer.client.BlockNumber(ctx)
+			if ctx.Err() != nil {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+
+			if lastBlockNum == nil || *lastBlockNum < blockNum {
+				cb(new(big.Int).SetUint64(blockNum))
+			}
+			if lastBlockNum == nil {
+				lastBlockNum = new(uint64)
+			}
+			*lastBlockNum = blockNum
+		}
+	}
+}
+
+func (streamer *PollingChainCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	if latestBlockNumUint64, err := streamer.client.BlockNumber(ctx); err != nil {
+		return nil, err
+	} else {
+		return new(big.Int).SetUint64(latestBlockNumUint64), nil
+	}
+}
@@ -0,0 +1,79 @@
+package eth
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+type Transport string
+
+const (
+	TransportWss  Transport = "wss"
+	TransportHttp Transport = "http"
+	TransportIpc  Transport = "ipc"
+)
+
+const IPC_SCHEME_PREFIX = "ipc://"
+
+// ParseTransport infers the transport from the scheme of the endpoint. Endpoints
+// with no scheme (e.g. `/var/lib/geth/geth.ipc`) or with an `ipc://` prefix are
+// treated as paths to an IPC socket.
+func ParseTransport(endpoint string) (Transport, error) {
+	if path, ok := strings.CutPrefix(endpoint, IPC_SCHEME_PREFIX); ok {
+		if path == "" {
+			return "", fmt.Errorf("endpoint '%s' is missing the path to the IPC socket", endpoint)
+		} else {
+			return TransportIpc, nil
+		}
+	}
+
+	// NOTE: without this check `localhost:8545` would be parsed as a URL with the scheme
+	// `localhost` (and `127.0.0.1:8545` would fail to parse), which is confusing to users
+	if !strings.Contains(endpoint, "://") {
+		if _, _, err := net.SplitHostPort(endpoint); err == nil {
+			return "", fmt.Errorf("endpoint '%s' is missing a URL scheme - use 'ws://%s' or 'http://%s' (or 'ipc://' for a socket path)", endpoint, endpoint, endpoint)
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	switch u.Scheme {
+	case "ws", "wss":
+		return TransportWss, nil
+	case "http", "https":
+		return TransportHttp, nil
+	case "":
+		return TransportIpc, nil
+	default:
+		return "", fmt.Errorf("unsupported URL scheme '%s' - must be one of: [ ws, wss, http, https, ipc ]", u.Scheme)
+	}
+}
+
+// IsPush returns true if the transport supports subscriptions (i.e. new heads are
+// pushed to the client) and false if the chain must be polled for new heads.
+func (t Transport) IsPush() bool {
+	return t != TransportHttp
+}
+
+func Dial(ctx context.Context, endpoint string) (*ethclient.Client, Transport, error) {
+	transport, err := ParseTransport(endpoint)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client, err := rpc.DialContext(ctx, strings.TrimPrefix(endpoint, IPC_SCHEME_PREFIX))
+	if err != nil {
+		return nil, "", err
+	} else {
+		return ethclient.NewClient(client), transport, nil
+	}
+}
@@ -0,0 +1,46 @@
+package eth
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseTransport(t *testing.T) {
+	testCases := []struct {
+		name      string
+		endpoint  string
+		transport Transport
+		err       string
+	}{
+		{name: "ws", endpoint: "ws://localhost:8546", transport: TransportWss},
+		{name: "wss", endpoint: "wss://mainnet.example.com/v1", transport: TransportWss},
+		{name: "http", endpoint: "http://localhost:8545", transport: TransportHttp},
+		{name: "https", endpoint: "https://mainnet.example.com/v1", transport: TransportHttp},
+		{name: "ipc url", endpoint: "ipc:///var/lib/geth/geth.ipc", transport: TransportIpc},
+		{name: "ipc url with a relative path", endpoint: "ipc://geth.ipc", transport: TransportIpc},
+		{name: "absolute path", endpoint: "/var/lib/geth/geth.ipc", transport: TransportIpc},
+		{name: "relative path", endpoint: "./geth.ipc", transport: TransportIpc},
+		{name: "ipc url without a path", endpoint: "ipc://", err: "missing the path"},
+		{name: "unsupported scheme", endpoint: "ftp://localhost:8545", err: "unsupported URL scheme 'ftp'"},
+		{name: "host and port", endpoint: "localhost:8545", err: "missing a URL scheme"},
+		{name: "ip and port", endpoint: "127.0.0.1:8545", err: "missing a URL scheme"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			transport, err := ParseTransport(tc.endpoint)
+			if tc.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.err) {
+					t.Fatalf("expected an error containing '%s' but got: %v", tc.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if transport != tc.transport {
+				t.Fatalf("expected transport '%s' but got '%s'", tc.transport, transport)
+			}
+		})
+	}
+}
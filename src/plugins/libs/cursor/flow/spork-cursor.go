@@ -0,0 +1,253 @@
+package flow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/executiondata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const SPORK_RETRY_INTERVAL = time.Second * 5
+
+// SporkChainCursor is a cursor that is aware of Flow sporks. Each spork has its own
+// access node, so the cursor tails the live spork and moves on to the next access
+// node in the table once the current spork reaches its end height.
+type SporkChainCursor struct {
+	table    *SporkTable
+	dialOpts []grpc.DialOption
+	conns    map[int]*grpc.ClientConn
+	mutex    *sync.Mutex
+	current  int
+}
+
+func NewSporkChainCursor(table *SporkTable, dialOpts ...grpc.DialOption) *SporkChainCursor {
+	return &SporkChainCursor{
+		table:    table,
+		dialOpts: dialOpts,
+		conns:    map[int]*grpc.ClientConn{},
+		mutex:    &sync.Mutex{},
+		current:  -1,
+	}
+}
+
+func (streamer *SporkChainCursor) Close() error {
+	streamer.mutex.Lock()
+	defer streamer.mutex.Unlock()
+
+	errs := []error{}
+	for i, conn := range streamer.conns {
+		errs = append(errs, conn.Close())
+		delete(streamer.conns, i)
+	}
+
+	return errors.Join(errs...)
+}
+
+// cursor returns a chain cursor that is connected to the access node of the i-th
+// spork. Connections are created lazily and reused across calls.
+func (streamer *SporkChainCursor) cursor(i int) (*ChainCursor, error) {
+	streamer.mutex.Lock()
+	defer streamer.mutex.Unlock()
+
+	conn, exists := streamer.conns[i]
+	if !exists {
+		c, err := grpc.NewClient(streamer.table.At(i).AccessNode, streamer.dialOpts...)
+		if err != nil {
+			return nil, err
+		} else {
+			streamer.conns[i] = c
+			conn = c
+		}
+	}
+
+	return &ChainCursor{
+		executiondataClient: executiondata.NewExecutionDataAPIClient(conn),
+		accessClient:        access.NewAccessAPIClient(conn),
+	}, nil
+}
+
+func (streamer *SporkChainCursor) getCurrent() int {
+	streamer.mutex.Lock()
+	defer streamer.mutex.Unlock()
+	return streamer.current
+}
+
+func (streamer *SporkChainCursor) setCurrent(i int) {
+	streamer.mutex.Lock()
+	defer streamer.mutex.Unlock()
+	streamer.current = i
+}
+
+func (streamer *SporkChainCursor) getLatestSealedHeight(ctx context.Context, i int) (uint64, error) {
+	chainCursor, err := streamer.cursor(i)
+	if err != nil {
+		return 0, err
+	}
+
+	latestBlockHeader, err := chainCursor.accessClient.GetLatestBlockHeader(ctx, &access.GetLatestBlockHeaderRequest{IsSealed: true})
+	if err != nil {
+		return 0, err
+	} else {
+		return latestBlockHeader.Block.Height, nil
+	}
+}
+
+// findLiveSpork walks the spork table from newest to oldest and returns the first
+// spork whose access node is reachable and has sealed its root block. Entries for
+// upcoming sporks (whose access nodes are not up yet) are skipped.
+func (streamer *SporkChainCursor) findLiveSpork(ctx context.Context) (int, uint64, error) {
+	errs := []error{}
+	for i := streamer.table.Len() - 1; i >= 0; i-- {
+		height, err := streamer.getLatestSealedHeight(ctx, i)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("spork '%s': %w", streamer.table.At(i).Name, err))
+			continue
+		}
+		if height >= streamer.table.At(i).RootHeight {
+			return i, height, nil
+		}
+	}
+
+	return -1, 0, fmt.Errorf("failed to find a live spork: %w", errors.Join(errs...))
+}
+
+// waitForSpork blocks until the access node of the i-th spork is reachable.
+func (streamer *SporkChainCursor) waitForSpork(ctx context.Context, i int) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+			if _, err := streamer.getLatestSealedHeight(ctx, i); err == nil {
+				return nil
+			} else {
+				timer.Reset(SPORK_RETRY_INTERVAL)
+			}
+		}
+	}
+}
+
+func (streamer *SporkChainCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	i, startHeight, err := streamer.findLiveSpork(ctx)
+	if err != nil {
+		return err
+	}
+
+	for {
+		streamer.setCurrent(i)
+
+		chainCursor, err := streamer.cursor(i)
+		if err != nil {
+			return err
+		}
+
+		stream, err := chainCursor.executiondataClient.SubscribeExecutionDataFromStartBlockHeight(ctx, &executiondata.SubscribeExecutionDataFromStartBlockHeightRequest{
+			StartBlockHeight: startHeight,
+		})
+		if err != nil {
+			return err
+		}
+
+		endHeight, hasNext := streamer.table.EndHeight(i)
+		for isSporkDone := false; !isSporkDone; {
+			select {
+			case <-ctx.Done():
+				return nil
+			default:
+				data, err := stream.Recv()
+				if status.Code(err) == codes.Canceled {
+					return nil
+				}
+
+				// NOTE: the cursor only moves on to the next spork once it has received the
+				// spork's end height (see below). A stream that closes before then (e.g. the
+				// access node restarted) is returned as an error so that the subscription is
+				// retried rather than skipping the rest of the spork.
+				if err == io.EOF {
+					return fmt.Errorf("the execution data stream of spork '%s' closed before the spork ended", streamer.table.At(i).Name)
+				}
+				if err != nil {
+					return err
+				}
+
+				cb(new(big.Int).SetUint64(data.BlockHeight))
+				if hasNext && data.BlockHeight >= endHeight {
+					isSporkDone = true
+				}
+			}
+		}
+
+		i += 1
+		startHeight = streamer.table.At(i).RootHeight
+		if err := streamer.waitForSpork(ctx, i); err != nil {
+			return nil
+		}
+	}
+}
+
+func (streamer *SporkChainCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	i := streamer.getCurrent()
+	if i == -1 {
+		if live, _, err := streamer.findLiveSpork(ctx); err != nil {
+			return nil, err
+		} else {
+			i = live
+		}
+	}
+
+	if height, err := streamer.getLatestSealedHeight(ctx, i); err != nil {
+		return nil, err
+	} else {
+		return new(big.Int).SetUint64(height), nil
+	}
+}
+
+// CheckValue checks that the access node of the spork that serves a height below the
+// live spork still has the block at that height. Heights of the live spork (and any
+// later spork) are served by tailing, so they are not checked.
+func (streamer *SporkChainCursor) CheckValue(ctx context.Context, value *big.Int) error {
+	if !value.IsUint64() {
+		return status.Errorf(codes.OutOfRange, "start cursor %s is not a valid block height", value.String())
+	}
+
+	height := value.Uint64()
+	i, err := streamer.table.Lookup(height)
+	if err != nil {
+		return status.Error(codes.OutOfRange, err.Error())
+	}
+
+	if live := streamer.getCurrent(); live != -1 && i >= live {
+		return nil
+	}
+
+	chainCursor, err := streamer.cursor(i)
+	if err != nil {
+		return err
+	}
+
+	spork := streamer.table.At(i)
+	_, err = chainCursor.accessClient.GetBlockHeaderByHeight(ctx, &access.GetBlockHeaderByHeightRequest{Height: height})
+	if status.Code(err) == codes.NotFound {
+		return status.Errorf(codes.OutOfRange, "height %d is not available on the access node of spork '%s'", height, spork.Name)
+	}
+	if err != nil {
+		return status.Errorf(codes.Unavailable, "the access node of spork '%s' cannot serve height %d: %v", spork.Name, height, err)
+	}
+
+	return nil
+}
+
+func (streamer *SporkChainCursor) GetEarliestValue(ctx context.Context) (*big.Int, error) {
+	return new(big.Int).SetUint64(streamer.table.RootHeight()), nil
+}
@@ -0,0 +1,219 @@
+package flow
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/onflow/flow/protobuf/go/flow/access"
+	"github.com/onflow/flow/protobuf/go/flow/entities"
+	"github.com/onflow/flow/protobuf/go/flow/executiondata"
+	"golang.org/x/net/nettest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// fakeAccessAPI serves the latest sealed height of a spork and the block headers
+// that its access node still has
+type fakeAccessAPI struct {
+	access.UnimplementedAccessAPIServer
+	sealedHeight   uint64
+	earliestHeight uint64
+}
+
+func (api *fakeAccessAPI) GetLatestBlockHeader(ctx context.Context, req *access.GetLatestBlockHeaderRequest) (*access.BlockHeaderResponse, error) {
+	return &access.BlockHeaderResponse{Block: &entities.BlockHeader{Height: api.sealedHeight}}, nil
+}
+
+func (api *fakeAccessAPI) GetBlockHeaderByHeight(ctx context.Context, req *access.GetBlockHeaderByHeightRequest) (*access.BlockHeaderResponse, error) {
+	if req.Height < api.earliestHeight || req.Height > api.sealedHeight {
+		return nil, status.Errorf(codes.NotFound, "block %d not found", req.Height)
+	} else {
+		return &access.BlockHeaderResponse{Block: &entities.BlockHeader{Height: req.Height}}, nil
+	}
+}
+
+// fakeExecutionDataAPI streams a fixed list of heights and then ends the stream with
+// the given error (or keeps it open if keepOpen is set)
+type fakeExecutionDataAPI struct {
+	executiondata.UnimplementedExecutionDataAPIServer
+	heights  []uint64
+	err      error
+	keepOpen bool
+	starts   chan uint64
+}
+
+func (api *fakeExecutionDataAPI) SubscribeExecutionDataFromStartBlockHeight(req *executiondata.SubscribeExecutionDataFromStartBlockHeightRequest, stream executiondata.ExecutionDataAPI_SubscribeExecutionDataFromStartBlockHeightServer) error {
+	api.starts <- req.StartBlockHeight
+	for _, height := range api.heights {
+		if height < req.StartBlockHeight {
+			continue
+		}
+		if err := stream.Send(&executiondata.SubscribeExecutionDataResponse{BlockHeight: height}); err != nil {
+			return err
+		}
+	}
+
+	if api.keepOpen {
+		<-stream.Context().Done()
+	}
+	return api.err
+}
+
+func newFakeExecutionDataAPI(heights []uint64, err error, keepOpen bool) *fakeExecutionDataAPI {
+	return &fakeExecutionDataAPI{heights: heights, err: err, keepOpen: keepOpen, starts: make(chan uint64, 10)}
+}
+
+// startAccessNode serves the fake APIs of a spork's access node and returns its address
+func startAccessNode(t *testing.T, accessAPI *fakeAccessAPI, executionDataAPI *fakeExecutionDataAPI) string {
+	// NOTE: the gRPC server will automatically close the listener
+	lis, err := nettest.NewLocalListener("tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := grpc.NewServer()
+	access.RegisterAccessAPIServer(server, accessAPI)
+	executiondata.RegisterExecutionDataAPIServer(server, executionDataAPI)
+	go server.Serve(lis)
+	t.Cleanup(server.Stop)
+
+	return lis.Addr().String()
+}
+
+func newSporkChainCursor(t *testing.T, sporks []Spork) *SporkChainCursor {
+	table, err := NewSporkTable(sporks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	streamer := NewSporkChainCursor(table, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	t.Cleanup(func() { streamer.Close() })
+	return streamer
+}
+
+func TestSporkChainCursorSubscribe(t *testing.T) {
+	testCases := []struct {
+		name    string
+		heights []uint64
+		err     error
+		code    codes.Code
+		cursors []uint64
+	}{
+		// NOTE: the first spork ends at height 199, so the cursor moves on to the second
+		// spork once it has received that height and the stream has closed
+		{name: "end height reached", heights: []uint64{198, 199}, code: codes.OK, cursors: []uint64{198, 199, 200, 201}},
+		{name: "unavailable before the end height", heights: []uint64{198}, err: status.Error(codes.Unavailable, "connection reset"), code: codes.Unavailable, cursors: []uint64{198}},
+		{name: "closed before the end height", heights: []uint64{198}, code: codes.Unknown, cursors: []uint64{198}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+			defer cancel()
+
+			// NOTE: the access node of the second spork has not sealed the spork's root
+			// block yet, so the cursor starts tailing the first spork
+			first := newFakeExecutionDataAPI(tc.heights, tc.err, false)
+			second := newFakeExecutionDataAPI([]uint64{200, 201}, nil, true)
+			streamer := newSporkChainCursor(t, []Spork{
+				{Name: "mainnet-1", RootHeight: 100, AccessNode: startAccessNode(t, &fakeAccessAPI{sealedHeight: 198}, first)},
+				{Name: "mainnet-2", RootHeight: 200, AccessNode: startAccessNode(t, &fakeAccessAPI{sealedHeight: 150}, second)},
+			})
+
+			mutex := &sync.Mutex{}
+			cursors := []uint64{}
+			err := streamer.Subscribe(ctx, func(cursor *big.Int) {
+				mutex.Lock()
+				defer mutex.Unlock()
+				cursors = append(cursors, cursor.Uint64())
+				if len(cursors) == len(tc.cursors) && tc.code == codes.OK {
+					cancel()
+				}
+			})
+
+			if tc.code == codes.OK && err != nil {
+				t.Fatal(err)
+			}
+			if tc.code != codes.OK && (err == nil || status.Code(err) != tc.code) {
+				t.Fatalf("expected a %s error but got: %v", tc.code, err)
+			}
+			if !slices.Equal(cursors, tc.cursors) {
+				t.Fatalf("expected cursors %v but got %v", tc.cursors, cursors)
+			}
+
+			// NOTE: the second spork is only subscribed to once the first spork has ended
+			if start := <-first.starts; start != 198 {
+				t.Fatalf("expected the first spork to be streamed from height 198 but got %d", start)
+			}
+			select {
+			case start := <-second.starts:
+				if tc.code != codes.OK || start != 200 {
+					t.Fatalf("unexpected subscription to the second spork from height %d", start)
+				}
+			default:
+				if tc.code == codes.OK {
+					t.Fatal("expected the second spork to be streamed")
+				}
+			}
+		})
+	}
+}
+
+func TestSporkChainCursorSubscribeLiveSpork(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// NOTE: a live spork that becomes unavailable is an error rather than the end of
+	// the subscription
+	live := newFakeExecutionDataAPI([]uint64{198}, status.Error(codes.Unavailable, "connection reset"), false)
+	streamer := newSporkChainCursor(t, []Spork{
+		{Name: "mainnet-1", RootHeight: 100, AccessNode: startAccessNode(t, &fakeAccessAPI{sealedHeight: 198}, live)},
+	})
+
+	if err := streamer.Subscribe(ctx, func(cursor *big.Int) {}); status.Code(err) != codes.Unavailable {
+		t.Fatalf("expected an unavailable error but got: %v", err)
+	}
+}
+
+func TestSporkChainCursorCheckValue(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second*10)
+	defer cancel()
+
+	// NOTE: the access node of the first spork has pruned the blocks below height 150
+	streamer := newSporkChainCursor(t, []Spork{
+		{Name: "mainnet-1", RootHeight: 100, AccessNode: startAccessNode(t, &fakeAccessAPI{sealedHeight: 199, earliestHeight: 150}, newFakeExecutionDataAPI(nil, nil, true))},
+		{Name: "mainnet-2", RootHeight: 200, AccessNode: startAccessNode(t, &fakeAccessAPI{sealedHeight: 300, earliestHeight: 200}, newFakeExecutionDataAPI(nil, nil, true))},
+	})
+
+	if earliest, err := streamer.GetEarliestValue(ctx); err != nil {
+		t.Fatal(err)
+	} else if earliest.Uint64() != 100 {
+		t.Fatalf("expected the earliest cursor to be 100 but got %s", earliest.String())
+	}
+
+	testCases := []struct {
+		name  string
+		value *big.Int
+		code  codes.Code
+	}{
+		{name: "negative height", value: big.NewInt(-1), code: codes.OutOfRange},
+		{name: "below the root height", value: big.NewInt(99), code: codes.OutOfRange},
+		{name: "pruned by the access node", value: big.NewInt(149), code: codes.OutOfRange},
+		{name: "served by a past spork", value: big.NewInt(150), code: codes.OK},
+		{name: "served by the last spork", value: big.NewInt(250), code: codes.OK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if err := streamer.CheckValue(ctx, tc.value); status.Code(err) != tc.code {
+				t.Fatalf("expected code %s but got: %v", tc.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package flow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+type (
+	Spork struct {
+		Name       string
+		RootHeight uint64
+		AccessNode string
+	}
+
+	// SporkTable maps height ranges to the access node that serves them. Each spork
+	// covers every height from its root height up to (but excluding) the root height
+	// of the next spork. The last spork in the table has no upper bound.
+	SporkTable struct {
+		sporks []Spork
+	}
+)
+
+func NewSporkTable(sporks []Spork) (*SporkTable, error) {
+	if len(sporks) == 0 {
+		return nil, errors.New("spork table must contain at least one spork")
+	}
+
+	sorted := make([]Spork, len(sporks))
+	copy(sorted, sporks)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].RootHeight < sorted[j].RootHeight
+	})
+
+	for i, spork := range sorted {
+		if spork.AccessNode == "" {
+			return nil, fmt.Errorf("spork '%s' does not have an access node", spork.Name)
+		}
+		if i > 0 && sorted[i-1].RootHeight == spork.RootHeight {
+			return nil, fmt.Errorf("sporks '%s' and '%s' have the same root height %d", sorted[i-1].Name, spork.Name, spork.RootHeight)
+		}
+	}
+
+	return &SporkTable{sporks: sorted}, nil
+}
+
+func (table *SporkTable) Len() int {
+	return len(table.sporks)
+}
+
+func (table *SporkTable) At(i int) *Spork {
+	return &table.sporks[i]
+}
+
+// RootHeight returns the lowest height that can be served by any spork in the table.
+func (table *SporkTable) RootHeight() uint64 {
+	return table.sporks[0].RootHeight
+}
+
+// EndHeight returns the last height served by the i-th spork and false if the spork
+// is the last one in the table (i.e. it has no upper bound yet).
+func (table *SporkTable) EndHeight(i int) (uint64, bool) {
+	if i+1 >= len(table.sporks) {
+		return 0, false
+	} else {
+		return table.sporks[i+1].RootHeight - 1, true
+	}
+}
+
+// Lookup returns the index of the spork that serves the given height.
+func (table *SporkTable) Lookup(height uint64) (int, error) {
+	if height < table.RootHeight() {
+		return -1, fmt.Errorf("height %d is below the root height of the earliest known spork (%d)", height, table.RootHeight())
+	}
+
+	i := sort.Search(len(table.sporks), func(i int) bool {
+		return table.sporks[i].RootHeight > height
+	})
+
+	return i - 1, nil
+}
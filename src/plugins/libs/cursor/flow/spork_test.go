@@ -0,0 +1,107 @@
+package flow
+
+import (
+	"strings"
+	"testing"
+)
+
+func newSporkTable(t *testing.T) *SporkTable {
+	// NOTE: the sporks are sorted by root height when the table is created
+	table, err := NewSporkTable([]Spork{
+		{Name: "mainnet-3", RootHeight: 3000, AccessNode: "access-3:9000"},
+		{Name: "mainnet-1", RootHeight: 1000, AccessNode: "access-1:9000"},
+		{Name: "mainnet-2", RootHeight: 2000, AccessNode: "access-2:9000"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return table
+}
+
+func TestNewSporkTable(t *testing.T) {
+	table := newSporkTable(t)
+	if table.Len() != 3 || table.RootHeight() != 1000 {
+		t.Fatalf("unexpected spork table: %+v", table.sporks)
+	}
+	for i, name := range []string{"mainnet-1", "mainnet-2", "mainnet-3"} {
+		if table.At(i).Name != name {
+			t.Fatalf("expected spork %d to be '%s' but got '%s'", i, name, table.At(i).Name)
+		}
+	}
+
+	testCases := []struct {
+		name   string
+		sporks []Spork
+		err    string
+	}{
+		{name: "empty", sporks: []Spork{}, err: "at least one spork"},
+		{name: "no access node", sporks: []Spork{{Name: "mainnet-1", RootHeight: 1000}}, err: "does not have an access node"},
+		{name: "same root height", sporks: []Spork{
+			{Name: "mainnet-1", RootHeight: 1000, AccessNode: "access-1:9000"},
+			{Name: "mainnet-2", RootHeight: 1000, AccessNode: "access-2:9000"},
+		}, err: "have the same root height 1000"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := NewSporkTable(tc.sporks); err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Fatalf("expected error '%s' but got: %v", tc.err, err)
+			}
+		})
+	}
+}
+
+func TestEndHeight(t *testing.T) {
+	table := newSporkTable(t)
+
+	testCases := []struct {
+		spork     int
+		endHeight uint64
+		hasEnd    bool
+	}{
+		{spork: 0, endHeight: 1999, hasEnd: true},
+		{spork: 1, endHeight: 2999, hasEnd: true},
+		{spork: 2, endHeight: 0, hasEnd: false},
+	}
+
+	for _, tc := range testCases {
+		if endHeight, hasEnd := table.EndHeight(tc.spork); endHeight != tc.endHeight || hasEnd != tc.hasEnd {
+			t.Fatalf("expected spork %d to end at (%d, %t) but got (%d, %t)", tc.spork, tc.endHeight, tc.hasEnd, endHeight, hasEnd)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	table := newSporkTable(t)
+
+	testCases := []struct {
+		height uint64
+		spork  int
+		err    bool
+	}{
+		{height: 0, err: true},
+		{height: 999, err: true},
+		{height: 1000, spork: 0},
+		{height: 1999, spork: 0},
+		{height: 2000, spork: 1},
+		{height: 2999, spork: 1},
+		{height: 3000, spork: 2},
+		{height: 1_000_000, spork: 2},
+	}
+
+	for _, tc := range testCases {
+		spork, err := table.Lookup(tc.height)
+		if tc.err {
+			if err == nil {
+				t.Fatalf("expected height %d to be below the root height", tc.height)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if spork != tc.spork {
+			t.Fatalf("expected height %d to be served by spork %d but got %d", tc.height, tc.spork, spork)
+		}
+	}
+}
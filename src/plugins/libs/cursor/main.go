@@ -9,3 +9,16 @@ type Cursor interface {
 	GetLatestValue(ctx context.Context) (*big.Int, error)
 	Subscribe(ctx context.Context, cb func(cursor *big.Int)) error
 }
+
+// BoundedCursor is implemented by cursors that cannot serve values below a certain
+// point (e.g. chains whose history is only available from a specific height).
+type BoundedCursor interface {
+	GetEarliestValue(ctx context.Context) (*big.Int, error)
+}
+
+// HistoricalCursor is implemented by cursors whose history is served by different
+// upstreams (e.g. Flow sporks). CheckValue returns an error if the upstream that
+// serves a past value cannot serve it.
+type HistoricalCursor interface {
+	CheckValue(ctx context.Context, value *big.Int) error
+}
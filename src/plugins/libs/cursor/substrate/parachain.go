@@ -0,0 +1,187 @@
+package substrate
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"sync"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"golang.org/x/crypto/blake2b"
+)
+
+type (
+	// ParachainChainCursor follows relay chain finality instead of the finalized heads
+	// reported by the parachain's collators. For every finalized relay chain block, it
+	// reads the parachain head that was included in that block and reports the height
+	// of the parachain block that became finalized along with it.
+	ParachainChainCursor struct {
+		relay      *gsrpc.SubstrateAPI
+		paraID     uint32
+		storageKey types.StorageKey
+		mutex      *sync.Mutex
+	}
+
+	// parachainHead contains the leading fields of a SCALE-encoded parachain header.
+	// The remaining fields (state root, extrinsics root, digest) are not needed.
+	parachainHead struct {
+		ParentHash types.Hash
+		Number     types.BlockNumber
+	}
+)
+
+func NewParachainChainCursor(relay *gsrpc.SubstrateAPI, paraID uint32) cursor.Cursor {
+	return &ParachainChainCursor{relay: relay, paraID: paraID, mutex: &sync.Mutex{}}
+}
+
+func NewParachainLogger() *log.Logger {
+	return log.New(os.Stdout, fmt.Sprintf("[%s] ", "substrate-parachain-cursor"), log.LstdFlags)
+}
+
+// DecodeHeadData decodes the parachain block number from the head data that is
+// stored on the relay chain (i.e. `Paras.Heads`).
+func DecodeHeadData(headData []byte) (uint64, error) {
+	var head parachainHead
+	if err := codec.Decode(headData, &head); err != nil {
+		return 0, fmt.Errorf("failed to decode parachain head data: %w", err)
+	} else {
+		return uint64(head.Number), nil
+	}
+}
+
+// HashHeader computes the hash of a relay chain header (i.e. the blake2b-256 hash of
+// the SCALE-encoded header) so that it does not need to be looked up by its number.
+// The header can only be re-encoded if the client knows every item in its digest, so
+// false is returned if it contains an item that the client could not decode.
+func HashHeader(header *types.Header) (types.Hash, bool, error) {
+	for _, item := range header.Digest {
+		if !item.IsChangesTrieRoot && !item.IsPreRuntime && !item.IsConsensus && !item.IsSeal && !item.IsChangesTrieSignal && !item.IsOther {
+			return types.Hash{}, false, nil
+		}
+	}
+
+	if encoded, err := codec.Encode(header); err != nil {
+		return types.Hash{}, false, err
+	} else {
+		return types.Hash(blake2b.Sum256(encoded)), true, nil
+	}
+}
+
+func (streamer *ParachainChainCursor) getStorageKey() (types.StorageKey, error) {
+	streamer.mutex.Lock()
+	defer streamer.mutex.Unlock()
+
+	if streamer.storageKey != nil {
+		return streamer.storageKey, nil
+	}
+
+	meta, err := streamer.relay.RPC.State.GetMetadataLatest()
+	if err != nil {
+		return nil, err
+	}
+
+	paraID, err := codec.Encode(types.NewU32(streamer.paraID))
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := types.CreateStorageKey(meta, "Paras", "Heads", paraID)
+	if err != nil {
+		return nil, err
+	} else {
+		streamer.storageKey = key
+	}
+
+	return key, nil
+}
+
+func (streamer *ParachainChainCursor) getParachainHeight(relayBlockHash types.Hash) (uint64, error) {
+	key, err := streamer.getStorageKey()
+	if err != nil {
+		return 0, err
+	}
+
+	var headData types.Bytes
+	ok, err := streamer.relay.RPC.State.GetStorage(key, &headData, relayBlockHash)
+	if err != nil {
+		return 0, err
+	}
+	if !ok {
+		return 0, fmt.Errorf("parachain %d is not registered on the relay chain at block %s", streamer.paraID, relayBlockHash.Hex())
+	}
+
+	return DecodeHeadData(headData)
+}
+
+func (streamer *ParachainChainCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	sub, err := streamer.relay.RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		return err
+	} else {
+		defer sub.Unsubscribe()
+	}
+
+	var lastHeight *uint64 = nil
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err, ok := <-sub.Err():
+			if !ok {
+				return nil
+			} else {
+				return err
+			}
+		case data, ok := <-sub.Chan():
+			if !ok {
+				return nil
+			}
+
+			relayBlockHash, ok, err := HashHeader(&data)
+			if err != nil {
+				return err
+			}
+
+			// NOTE: the hash is only looked up if it cannot be computed from the header
+			if !ok {
+				relayBlockHash, err = streamer.relay.RPC.Chain.GetBlockHash(uint64(data.Number))
+				if err != nil {
+					return err
+				}
+			}
+
+			height, err := streamer.getParachainHeight(relayBlockHash)
+			if err != nil {
+				return err
+			}
+
+			// NOTE: a parachain block is not necessarily included in every relay chain
+			// block, so several relay chain blocks can finalize the same parachain head
+			if lastHeight == nil || *lastHeight < height {
+				cb(new(big.Int).SetUint64(height))
+			}
+			if lastHeight == nil {
+				lastHeight = new(uint64)
+			}
+			*lastHeight = height
+		}
+	}
+}
+
+func (streamer *ParachainChainCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	relayBlockHash, err := streamer.relay.RPC.Chain.GetFinalizedHead()
+	if err != nil {
+		return nil, err
+	}
+
+	if height, err := streamer.getParachainHeight(relayBlockHash); err != nil {
+		return nil, err
+	} else {
+		return new(big.Int).SetUint64(height), nil
+	}
+}
@@ -0,0 +1,251 @@
+package substrate
+
+import (
+	"context"
+	"math/big"
+	"slices"
+	"strings"
+	"testing"
+
+	gsrpc "github.com/centrifuge/go-substrate-rpc-client/v4"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/testutils/substrate_testutils"
+)
+
+// NOTE: no parachains are registered on the relay chain of the local dev node
+const UNREGISTERED_PARACHAIN_ID = 2000
+
+// NOTE: the stub relay chain only has this parachain registered
+const REGISTERED_PARACHAIN_ID = 1000
+
+func TestDecodeHeadData(t *testing.T) {
+	for _, number := range []uint32{0, 1, 63, 64, 16383, 16384, 4294967295} {
+		headData, err := codec.Encode(types.Header{
+			ParentHash: types.NewHash([]byte{1, 2, 3}),
+			Number:     types.BlockNumber(number),
+			Digest:     types.Digest{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		height, err := DecodeHeadData(headData)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if height != uint64(number) {
+			t.Fatalf("decoded the wrong parachain height (decoded = %d, expected = %d)", height, number)
+		}
+	}
+}
+
+func TestDecodeHeadDataInvalid(t *testing.T) {
+	if _, err := DecodeHeadData([]byte{1, 2, 3}); err == nil {
+		t.Fatal("expected truncated head data to fail decoding")
+	}
+}
+
+func TestHashHeader(t *testing.T) {
+	stateRoot, err := types.NewHashFromHexString("0x29d0d972cd27cbc511e9589fcb7a4506d5eb6a9e8df205f00472e5ab354a4e17")
+	if err != nil {
+		t.Fatal(err)
+	}
+	extrinsicsRoot, err := types.NewHashFromHexString("0x03170a2e7597b7b7e3d84c05391d139a62b157e78786d8c082f29dcf4c111314")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// NOTE: this is the genesis header of the Polkadot relay chain
+	hash, ok, err := HashHeader(&types.Header{StateRoot: stateRoot, ExtrinsicsRoot: extrinsicsRoot, Digest: types.Digest{}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := "0x91b171bb158e2d3848fa23a9f1c25182fb8e20313b2c1eb49219da7a70ce90c3"; !ok || hash.Hex() != expected {
+		t.Fatalf("expected hash %s but got %s (%t)", expected, hash.Hex(), ok)
+	}
+
+	// NOTE: a digest item that the client could not decode cannot be re-encoded
+	if _, ok, err := HashHeader(&types.Header{Digest: types.Digest{{}}}); ok || err != nil {
+		t.Fatalf("expected the hash of a header with an unknown digest item to be looked up but got: %t, %v", ok, err)
+	}
+}
+
+func TestParachainHeaderHashes(t *testing.T) {
+	backend, err := substrate_testutils.InitBackend()
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(func() {
+			backend.Client.Close()
+		})
+	}
+
+	sub, err := backend.RPC.Chain.SubscribeFinalizedHeads()
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		defer sub.Unsubscribe()
+	}
+
+	testCtx, testCancel := context.WithTimeout(context.Background(), TESTS_DUR)
+	defer testCancel()
+
+	// NOTE: the hashes of the finalized relay chain heads are computed from the headers
+	// instead of being looked up by their numbers, so they must match the node's hashes
+	count := 0
+	for testCtx.Err() == nil {
+		select {
+		case <-testCtx.Done():
+		case err := <-sub.Err():
+			t.Fatal(err)
+		case header := <-sub.Chan():
+			hash, ok, err := HashHeader(&header)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !ok {
+				continue
+			}
+
+			expected, err := backend.RPC.Chain.GetBlockHash(uint64(header.Number))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash != expected {
+				t.Fatalf("expected the hash of block %d to be %s but got %s", header.Number, expected.Hex(), hash.Hex())
+			}
+			count++
+		}
+	}
+
+	if count == 0 {
+		t.Fatal("expected at least one finalized head")
+	}
+}
+
+func TestParachainNotRegistered(t *testing.T) {
+	backend, err := substrate_testutils.InitBackend()
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(func() {
+			backend.Client.Close()
+		})
+	}
+
+	testCtx, testCancel := context.WithTimeout(context.Background(), TESTS_DUR)
+	defer testCancel()
+
+	chainCursor := NewParachainChainCursor(backend, UNREGISTERED_PARACHAIN_ID)
+	if _, err := chainCursor.GetLatestValue(testCtx); err == nil || !strings.Contains(err.Error(), "parachain 2000 is not registered") {
+		t.Fatalf("expected an unregistered parachain error but got: %v", err)
+	}
+
+	// NOTE: the storage of the relay chain is read at the first finalized head, which
+	// is sent as soon as the subscription starts
+	err = streamer.New(chainCursor, NewParachainLogger()).Subscribe(testCtx)
+	if err == nil || !strings.Contains(err.Error(), "parachain 2000 is not registered") {
+		t.Fatalf("expected an unregistered parachain error but got: %v", err)
+	}
+}
+
+func TestParachainFollowsRelayFinality(t *testing.T) {
+	relay, err := substrate_testutils.InitRelayBackend(REGISTERED_PARACHAIN_ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(relay.Close)
+	}
+
+	client, err := gsrpc.NewSubstrateAPI(relay.Url())
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(client.Client.Close)
+	}
+
+	testCtx, testCancel := context.WithTimeout(context.Background(), TESTS_DUR)
+	defer testCancel()
+
+	chainCursor := NewParachainChainCursor(client, REGISTERED_PARACHAIN_ID)
+	assertLatestValue(t, testCtx, chainCursor, 10)
+
+	cursors := make(chan uint64, 10)
+	errs := make(chan error, 1)
+	go func() {
+		errs <- chainCursor.Subscribe(testCtx, func(cursor *big.Int) { cursors <- cursor.Uint64() })
+	}()
+
+	// NOTE: the current finalized head is sent as soon as the subscription starts, so
+	// the relay chain is only advanced once it has been received
+	received := []uint64{receiveCursor(t, testCtx, cursors)}
+
+	// NOTE: the parachain head is not updated by every relay chain block, and the cursor
+	// should only advance when a new parachain head is finalized
+	for _, paraHeight := range []uint64{10, 11, 11, 12, 14} {
+		if err := relay.Finalize(paraHeight); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for len(received) < 4 {
+		received = append(received, receiveCursor(t, testCtx, cursors))
+	}
+
+	if expected := []uint64{10, 11, 12, 14}; !slices.Equal(received, expected) {
+		t.Fatalf("expected cursors %v but got %v", expected, received)
+	}
+	assertLatestValue(t, testCtx, chainCursor, 14)
+
+	testCancel()
+	if err := <-errs; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case cursor := <-cursors:
+		t.Fatalf("expected no more cursors but got %d", cursor)
+	default:
+	}
+}
+
+func TestParachainNotRegisteredOnRelay(t *testing.T) {
+	relay, err := substrate_testutils.InitRelayBackend(REGISTERED_PARACHAIN_ID, 10)
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(relay.Close)
+	}
+
+	client, err := gsrpc.NewSubstrateAPI(relay.Url())
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		t.Cleanup(client.Client.Close)
+	}
+
+	chainCursor := NewParachainChainCursor(client, UNREGISTERED_PARACHAIN_ID)
+	if _, err := chainCursor.GetLatestValue(context.Background()); err == nil || !strings.Contains(err.Error(), "parachain 2000 is not registered") {
+		t.Fatalf("expected an unregistered parachain error but got: %v", err)
+	}
+}
+
+func assertLatestValue(t *testing.T, ctx context.Context, chainCursor cursor.Cursor, expected uint64) {
+	if latest, err := chainCursor.GetLatestValue(ctx); err != nil {
+		t.Fatal(err)
+	} else if latest.Uint64() != expected {
+		t.Fatalf("expected the latest cursor to be %d but got %s", expected, latest.String())
+	}
+}
+
+func receiveCursor(t *testing.T, ctx context.Context, cursors chan uint64) uint64 {
+	select {
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for the next cursor")
+		return 0
+	case cursor := <-cursors:
+		return cursor
+	}
+}
@@ -0,0 +1,109 @@
+package runner
+
+import (
+	"context"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/upstream"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/grpc"
+)
+
+// Plugin is what a plugin binary provides on top of the bootstrap that all plugins
+// share.
+type Plugin struct {
+	Manifest *handshake.Manifest
+
+	// Connect connects to the upstream endpoints of a chain config. It is called once
+	// on startup and again for every reload.
+	Connect upstream.ConnectFunc
+
+	// Logger returns the logger of the plugin's streamer.
+	Logger func(conf *config.ChainConfig) *log.Logger
+
+	// Capabilities are reported in the handshake response along with the cursors and
+	// reload capabilities.
+	Capabilities []string
+}
+
+// Run prints the plugin's manifest if the CLI asks for it. Otherwise, it completes the
+// handshake, serves cursors and applies reloads until the plugin receives SIGINT or
+// SIGTERM. Any error is fatal.
+func Run(plugin *Plugin) {
+	if handshake.IsManifestRequest(os.Args) {
+		if err := handshake.WriteManifest(os.Stdout, plugin.Manifest); err != nil {
+			log.Fatal(err)
+		} else {
+			return
+		}
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	requests := handshake.NewRequestReader(os.Stdin)
+	req, err := requests.Read()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	conf := req.Config
+
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", conf.Server.Url())
+	if err != nil {
+		log.Fatal(err)
+	} else {
+		defer lis.Close()
+	}
+
+	chainCursor, disconnect, err := plugin.Connect(ctx, conf)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	app := api.New(
+		grpc.NewServer(),
+		streamer.New(
+			chainCursor,
+			plugin.Logger(conf),
+		),
+	)
+
+	conn := upstream.New(app.Stream, plugin.Connect, disconnect)
+	defer conn.Close()
+
+	// NOTE: if the subscription fails, then the errgroup's context is cancelled so the
+	// plugin shuts down and exits with an error (which lets the CLI restart it)
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.Go(func() error {
+		return app.Stream.Subscribe(egCtx)
+	})
+	eg.Go(func() error {
+		return app.Server.Serve(lis)
+	})
+
+	capabilities := append(append([]string{}, plugin.Capabilities...), handshake.CAPABILITY_RELOAD)
+	if err := handshake.WriteResponse(os.Stdout, handshake.NewResponse(lis.Addr().String(), capabilities...)); err != nil {
+		log.Fatal(err)
+	}
+
+	// NOTE: reading from stdin cannot be cancelled, so the listener is not part of the
+	// errgroup (it stops once the CLI closes stdin or the plugin exits)
+	go conn.Listen(egCtx, requests, os.Stdout)
+
+	log.Printf("Listening on %s\n", lis.Addr().String())
+	<-egCtx.Done()
+
+	app.Server.GracefulStop()
+	if err := eg.Wait(); err != nil {
+		log.Fatal(err)
+	}
+}
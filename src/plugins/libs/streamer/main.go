@@ -9,18 +9,29 @@ import (
 	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
 )
 
-type Streamer struct {
-	logger    *log.Logger
-	signal    *sync.Cond
-	cursor    cursor.Cursor
-	isStopped bool
-}
+type (
+	Streamer struct {
+		logger    *log.Logger
+		signal    *sync.Cond
+		cursor    cursor.Cursor
+		mutex     *sync.RWMutex
+		swaps     chan swap
+		isStopped bool
+	}
+
+	swap struct {
+		cursor cursor.Cursor
+		done   chan struct{}
+	}
+)
 
 func New(cursor cursor.Cursor, logger *log.Logger) *Streamer {
 	return &Streamer{
 		signal:    sync.NewCond(&sync.Mutex{}),
 		logger:    logger,
 		cursor:    cursor,
+		mutex:     &sync.RWMutex{},
+		swaps:     make(chan swap),
 		isStopped: false,
 	}
 }
@@ -44,10 +55,70 @@ func (streamer *Streamer) Subscribe(ctx context.Context) error {
 	}
 
 	streamer.logger.Printf("Waiting for new data...")
-	return streamer.cursor.Subscribe(ctx, func(cursor *big.Int) {
-		streamer.logger.Printf("Received new cursor: %s", cursor.String())
-		streamer.signal.Broadcast()
-	})
+	for {
+		subCtx, cancel := context.WithCancel(ctx)
+		errs := make(chan error, 1)
+		go func(chainCursor cursor.Cursor) {
+			errs <- chainCursor.Subscribe(subCtx, func(cursor *big.Int) {
+				streamer.logger.Printf("Received new cursor: %s", cursor.String())
+				streamer.signal.Broadcast()
+			})
+		}(streamer.current())
+
+		select {
+		case err := <-errs:
+			cancel()
+			return err
+		case next := <-streamer.swaps:
+			// NOTE: the previous subscription is stopped before the new cursor is used so
+			// that the caller can safely close the previous upstream connection once the
+			// swap is done - consumers waiting for the next cursor are not interrupted.
+			// The write lock waits for consumers that are still reading from the previous
+			// cursor (see read) to finish.
+			cancel()
+			<-errs
+			streamer.mutex.Lock()
+			streamer.cursor = next.cursor
+			streamer.mutex.Unlock()
+			close(next.done)
+			streamer.logger.Printf("Switched to a new upstream connection")
+		}
+	}
+}
+
+// Swap replaces the cursor that the streamer subscribes to without interrupting any
+// consumers. It blocks until the previous cursor is no longer in use (i.e. until no
+// subscription or consumer is reading from it). The context only applies until the
+// swap is accepted - once it is, the next cursor will be installed regardless.
+func (streamer *Streamer) Swap(ctx context.Context, next cursor.Cursor) error {
+	req := swap{cursor: next, done: make(chan struct{})}
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case streamer.swaps <- req:
+	}
+
+	// NOTE: returning early here would let the caller close the next cursor's upstream
+	// connection while the streamer is about to use it, so we wait for the swap to
+	// finish even if the context is cancelled in the meantime
+	<-req.done
+	return nil
+}
+
+func (streamer *Streamer) current() cursor.Cursor {
+	streamer.mutex.RLock()
+	defer streamer.mutex.RUnlock()
+	return streamer.cursor
+}
+
+// read calls the upstream with the current cursor. The read lock is held for the
+// whole call so that a swap cannot complete (and the previous upstream connection
+// cannot be closed) while a consumer is still using the previous cursor.
+func (streamer *Streamer) read(fn func(chainCursor cursor.Cursor) (*big.Int, error)) (*big.Int, error) {
+	streamer.mutex.RLock()
+	defer streamer.mutex.RUnlock()
+	return fn(streamer.cursor)
 }
 
 func (streamer *Streamer) WaitForNextCursor(ctx context.Context) error {
@@ -103,12 +174,39 @@ func (streamer *Streamer) WaitForNextCursor(ctx context.Context) error {
 	}
 }
 
+// GetEarliestCursor returns the lowest cursor that can be served or nil if the
+// underlying cursor has no lower bound.
+func (streamer *Streamer) GetEarliestCursor(ctx context.Context) (*big.Int, error) {
+	return streamer.read(func(chainCursor cursor.Cursor) (*big.Int, error) {
+		if bounded, ok := chainCursor.(cursor.BoundedCursor); ok {
+			return bounded.GetEarliestValue(ctx)
+		} else {
+			return nil, nil
+		}
+	})
+}
+
+// CheckCursor returns an error if a past cursor cannot be served by the upstream that
+// holds it. Cursors that are not split across upstreams are not checked.
+func (streamer *Streamer) CheckCursor(ctx context.Context, value *big.Int) error {
+	_, err := streamer.read(func(chainCursor cursor.Cursor) (*big.Int, error) {
+		if historical, ok := chainCursor.(cursor.HistoricalCursor); ok {
+			return nil, historical.CheckValue(ctx, value)
+		} else {
+			return nil, nil
+		}
+	})
+	return err
+}
+
 func (streamer *Streamer) GetNextCursor(ctx context.Context, curr *big.Int) (*big.Int, error) {
 	if streamer.isStopped {
 		return nil, ErrStreamerStopped
 	}
 
-	latestCursor, err := streamer.cursor.GetLatestValue(ctx)
+	latestCursor, err := streamer.read(func(chainCursor cursor.Cursor) (*big.Int, error) {
+		return chainCursor.GetLatestValue(ctx)
+	})
 	if err != nil {
 		return nil, err
 	}
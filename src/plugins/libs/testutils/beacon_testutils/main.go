@@ -0,0 +1,192 @@
+package beacon_testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NOTE: the stub uses a much smaller epoch than mainnet (32 slots) so that tests can
+// observe several finalized checkpoints within a short amount of time
+const SLOTS_PER_EPOCH = 4
+
+type (
+	event struct {
+		topic string
+		data  string
+	}
+
+	Backend struct {
+		Server      *httptest.Server
+		subscribers map[chan event]struct{}
+		mutex       *sync.Mutex
+		done        chan struct{}
+		disconnect  chan struct{}
+		streams     int
+		slot        uint64
+	}
+)
+
+func InitBackend() *Backend {
+	backend := &Backend{
+		subscribers: map[chan event]struct{}{},
+		mutex:       &sync.Mutex{},
+		done:        make(chan struct{}),
+		disconnect:  make(chan struct{}),
+		streams:     0,
+		slot:        0,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /eth/v1/beacon/headers/head", backend.handleHeadHeader)
+	mux.HandleFunc("GET /eth/v1/beacon/states/head/finality_checkpoints", backend.handleFinalityCheckpoints)
+	mux.HandleFunc("GET /eth/v1/events", backend.handleEvents)
+	backend.Server = httptest.NewServer(mux)
+
+	return backend
+}
+
+func (b *Backend) Url() string {
+	return b.Server.URL
+}
+
+func (b *Backend) Close() {
+	close(b.done)
+	b.Server.Close()
+}
+
+// CloseStreams ends all open event streams cleanly, like beacon nodes do with idle
+// streams. Streams that are opened afterwards are not affected.
+func (b *Backend) CloseStreams() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	close(b.disconnect)
+	b.disconnect = make(chan struct{})
+}
+
+// Streams returns the number of event streams that have been opened so far
+func (b *Backend) Streams() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.streams
+}
+
+func (b *Backend) LatestSlot() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return b.slot
+}
+
+func (b *Backend) LatestEpoch() uint64 {
+	return b.LatestSlot() / SLOTS_PER_EPOCH
+}
+
+// Advance produces a new head slot and, if the slot starts a new epoch, finalizes
+// the epoch that just ended. All open event streams are notified accordingly.
+func (b *Backend) Advance() uint64 {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.slot += 1
+	b.broadcast(event{
+		topic: "head",
+		data:  fmt.Sprintf(`{"slot":"%d","epoch_transition":%t}`, b.slot, b.slot%SLOTS_PER_EPOCH == 0),
+	})
+	if b.slot%SLOTS_PER_EPOCH == 0 {
+		b.broadcast(event{
+			topic: "finalized_checkpoint",
+			data:  fmt.Sprintf(`{"epoch":"%d","execution_optimistic":false}`, b.slot/SLOTS_PER_EPOCH),
+		})
+	}
+
+	return b.slot
+}
+
+func (b *Backend) broadcast(e event) {
+	for sub := range b.subscribers {
+		select {
+		case sub <- e:
+		default:
+		}
+	}
+}
+
+func (b *Backend) writeJSON(w http.ResponseWriter, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(map[string]any{"data": data}); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func (b *Backend) handleHeadHeader(w http.ResponseWriter, r *http.Request) {
+	b.writeJSON(w, map[string]any{
+		"canonical": true,
+		"header": map[string]any{
+			"message": map[string]any{
+				"slot": strconv.FormatUint(b.LatestSlot(), 10),
+			},
+		},
+	})
+}
+
+func (b *Backend) handleFinalityCheckpoints(w http.ResponseWriter, r *http.Request) {
+	b.writeJSON(w, map[string]any{
+		"finalized": map[string]any{
+			"epoch": strconv.FormatUint(b.LatestEpoch(), 10),
+		},
+	})
+}
+
+func (b *Backend) handleEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	topics := map[string]bool{}
+	for _, topic := range strings.Split(r.URL.Query().Get("topics"), ",") {
+		topics[topic] = true
+	}
+
+	events := make(chan event, 64)
+	b.mutex.Lock()
+	b.subscribers[events] = struct{}{}
+	b.streams += 1
+	disconnect := b.disconnect
+	b.mutex.Unlock()
+	defer func() {
+		b.mutex.Lock()
+		delete(b.subscribers, events)
+		b.mutex.Unlock()
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-b.done:
+			return
+		case <-disconnect:
+			return
+		case e := <-events:
+			if !topics[e.topic] {
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", e.topic, e.data); err != nil {
+				return
+			} else {
+				flusher.Flush()
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package beacon_testutils
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+type SlotGenerator struct {
+	backend *Backend
+	logger  *log.Logger
+}
+
+func NewSlotGenerator(backend *Backend, logger *log.Logger) *SlotGenerator {
+	return &SlotGenerator{backend: backend, logger: logger}
+}
+
+func NewSlotGeneratorLogger() *log.Logger {
+	return log.New(os.Stdout, fmt.Sprintf("[%s] ", "slot-generator"), log.LstdFlags)
+}
+
+func (generator *SlotGenerator) Start(ctx context.Context, interval time.Duration, count int) error {
+	timer := time.NewTimer(interval)
+	defer timer.Stop()
+	for {
+		timer.Reset(interval)
+		select {
+		case <-ctx.Done():
+			return nil
+		case _, ok := <-timer.C:
+			if !ok {
+				return nil
+			}
+			for i := 0; i < count; i++ {
+				generator.logger.Printf("New slot: %d", generator.backend.Advance())
+			}
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package substrate_testutils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"sync"
+
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types"
+	"github.com/centrifuge/go-substrate-rpc-client/v4/types/codec"
+	"golang.org/x/crypto/blake2b"
+	"golang.org/x/net/websocket"
+)
+
+type (
+	rpcRequest struct {
+		ID     json.RawMessage   `json:"id"`
+		Method string            `json:"method"`
+		Params []json.RawMessage `json:"params"`
+	}
+
+	rpcError struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+
+	rpcResponse struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id,omitempty"`
+		Method  string          `json:"method,omitempty"`
+		Params  any             `json:"params,omitempty"`
+		Result  any             `json:"result"`
+		Error   *rpcError       `json:"error,omitempty"`
+	}
+
+	relayConn struct {
+		ws    *websocket.Conn
+		mutex *sync.Mutex
+	}
+
+	relayBlock struct {
+		header types.Header
+		hash   types.Hash
+		head   []byte
+	}
+
+	// RelayBackend is a stub relay chain that serves the subset of the Substrate JSON-RPC
+	// API used by the parachain cursor. It has a single registered parachain, and each
+	// finalized relay chain block includes the parachain head passed to Finalize.
+	RelayBackend struct {
+		Server      *httptest.Server
+		metadata    string
+		storageKey  string
+		blocks      []relayBlock
+		subscribers map[*relayConn]string
+		mutex       *sync.Mutex
+		subID       int
+	}
+)
+
+func InitRelayBackend(paraID uint32, paraHeight uint64) (*RelayBackend, error) {
+	meta := types.NewMetadataV13()
+	meta.MagicNumber = types.MagicNumber
+	meta.AsMetadataV13.Modules = []types.ModuleMetadataV13{{
+		Name:       "Paras",
+		HasStorage: true,
+		Storage: types.StorageMetadataV13{
+			Prefix: "Paras",
+			Items: []types.StorageFunctionMetadataV13{{
+				Name:     "Heads",
+				Modifier: types.StorageFunctionModifierV0{IsOptional: true},
+				Type: types.StorageFunctionTypeV13{
+					IsMap: true,
+					AsMap: types.MapTypeV10{
+						Hasher: types.StorageHasherV10{IsTwox64Concat: true},
+						Key:    "ParaId",
+						Value:  "HeadData",
+					},
+				},
+			}},
+		},
+	}}
+
+	metadata, err := codec.EncodeToHex(meta)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedParaID, err := codec.Encode(types.NewU32(paraID))
+	if err != nil {
+		return nil, err
+	}
+
+	storageKey, err := types.CreateStorageKey(meta, "Paras", "Heads", encodedParaID)
+	if err != nil {
+		return nil, err
+	}
+
+	backend := &RelayBackend{
+		metadata:    metadata,
+		storageKey:  storageKey.Hex(),
+		blocks:      []relayBlock{},
+		subscribers: map[*relayConn]string{},
+		mutex:       &sync.Mutex{},
+		subID:       0,
+	}
+
+	if err := backend.Finalize(paraHeight); err != nil {
+		return nil, err
+	}
+
+	backend.Server = httptest.NewServer(websocket.Server{Handler: backend.handleConn})
+	return backend, nil
+}
+
+func (b *RelayBackend) Url() string {
+	return "ws" + strings.TrimPrefix(b.Server.URL, "http")
+}
+
+func (b *RelayBackend) Close() {
+	b.Server.CloseClientConnections()
+	b.Server.Close()
+}
+
+// Finalize produces a new finalized relay chain block that includes the parachain head
+// at the given height. All open finalized head subscriptions are notified.
+func (b *RelayBackend) Finalize(paraHeight uint64) error {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	header := types.Header{Number: types.BlockNumber(len(b.blocks)), Digest: types.Digest{}}
+	if len(b.blocks) != 0 {
+		header.ParentHash = b.blocks[len(b.blocks)-1].hash
+	}
+
+	encodedHeader, err := codec.Encode(header)
+	if err != nil {
+		return err
+	}
+
+	// NOTE: the head data of a parachain is its SCALE-encoded header
+	headData, err := codec.Encode(types.Header{Number: types.BlockNumber(paraHeight), Digest: types.Digest{}})
+	if err != nil {
+		return err
+	}
+
+	block := relayBlock{header: header, hash: types.Hash(blake2b.Sum256(encodedHeader)), head: headData}
+	b.blocks = append(b.blocks, block)
+	for conn, id := range b.subscribers {
+		conn.notify(id, block.header)
+	}
+
+	return nil
+}
+
+func (b *RelayBackend) handleConn(ws *websocket.Conn) {
+	conn := &relayConn{ws: ws, mutex: &sync.Mutex{}}
+	defer func() {
+		b.mutex.Lock()
+		delete(b.subscribers, conn)
+		b.mutex.Unlock()
+	}()
+
+	for {
+		var req rpcRequest
+		if err := websocket.JSON.Receive(ws, &req); err != nil {
+			return
+		}
+
+		result, err := b.handleRequest(conn, req)
+		if err != nil {
+			conn.send(rpcResponse{ID: req.ID, Error: &rpcError{Code: -32601, Message: err.Error()}})
+		} else {
+			conn.send(rpcResponse{ID: req.ID, Result: result})
+		}
+
+		// NOTE: like a real node, the current finalized head is sent as soon as the
+		// subscription starts
+		if req.Method == "chain_subscribeFinalizedHeads" && err == nil {
+			b.mutex.Lock()
+			conn.notify(result.(string), b.blocks[len(b.blocks)-1].header)
+			b.subscribers[conn] = result.(string)
+			b.mutex.Unlock()
+		}
+	}
+}
+
+func (b *RelayBackend) handleRequest(conn *relayConn, req rpcRequest) (any, error) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	switch req.Method {
+	case "state_getMetadata":
+		return b.metadata, nil
+	case "chain_getFinalizedHead":
+		return b.blocks[len(b.blocks)-1].hash.Hex(), nil
+	case "chain_getBlockHash":
+		var number types.BlockNumber
+		if len(req.Params) == 0 || json.Unmarshal(req.Params[0], &number) != nil || int(number) >= len(b.blocks) {
+			return nil, nil
+		} else {
+			return b.blocks[number].hash.Hex(), nil
+		}
+	case "state_getStorage":
+		return b.getStorage(req.Params)
+	case "chain_subscribeFinalizedHeads":
+		b.subID += 1
+		return fmt.Sprintf("%d", b.subID), nil
+	case "chain_unsubscribeFinalizedHeads":
+		delete(b.subscribers, conn)
+		return true, nil
+	default:
+		return nil, fmt.Errorf("method '%s' is not supported", req.Method)
+	}
+}
+
+func (b *RelayBackend) getStorage(params []json.RawMessage) (any, error) {
+	var key string
+	if len(params) == 0 || json.Unmarshal(params[0], &key) != nil {
+		return nil, fmt.Errorf("missing storage key")
+	}
+
+	block := b.blocks[len(b.blocks)-1]
+	if len(params) > 1 {
+		var hash types.Hash
+		if err := json.Unmarshal(params[1], &hash); err != nil {
+			return nil, err
+		}
+
+		found := false
+		for _, candidate := range b.blocks {
+			if candidate.hash == hash {
+				block, found = candidate, true
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown block %s", hash.Hex())
+		}
+	}
+
+	// NOTE: parachains other than the registered one have no head
+	if key != b.storageKey {
+		return nil, nil
+	} else {
+		return codec.EncodeToHex(types.NewBytes(block.head))
+	}
+}
+
+func (conn *relayConn) send(res rpcResponse) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	res.Version = "2.0"
+	websocket.JSON.Send(conn.ws, res)
+}
+
+func (conn *relayConn) notify(id string, header types.Header) {
+	conn.send(rpcResponse{
+		Method: "chain_finalizedHead",
+		Params: map[string]any{"subscription": id, "result": header},
+	})
+}
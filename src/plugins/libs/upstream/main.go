@@ -0,0 +1,95 @@
+package upstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"sync"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+)
+
+type (
+	// ConnectFunc connects to the upstream endpoints of a chain config. It returns the
+	// chain cursor along with a function that closes the connection.
+	ConnectFunc func(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error)
+
+	// Upstream owns the connection that a plugin's streamer reads from and replaces it
+	// whenever the CLI sends a new chain config.
+	Upstream struct {
+		stream  *streamer.Streamer
+		connect ConnectFunc
+		close   func()
+		mutex   *sync.Mutex
+	}
+)
+
+// New wraps the initial upstream connection of a plugin. The stream must read from
+// the cursor that was returned along with the disconnect function.
+func New(stream *streamer.Streamer, connect ConnectFunc, disconnect func()) *Upstream {
+	return &Upstream{stream: stream, connect: connect, close: disconnect, mutex: &sync.Mutex{}}
+}
+
+// Listen applies the reload requests that the CLI sends after the handshake until
+// the CLI closes the stream or the context is cancelled. The result of each reload is
+// written to w. If a reload fails, then the current connection is kept.
+func (u *Upstream) Listen(ctx context.Context, requests *handshake.RequestReader, w io.Writer) {
+	for ctx.Err() == nil {
+		req, err := requests.Read()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+
+		// NOTE: the request stream cannot be recovered after a malformed request, so
+		// the error is reported and no further reloads are accepted
+		if err != nil {
+			log.Printf("Failed to read reload request: %v\n", err)
+			if err := handshake.WriteReloadResponse(w, err); err != nil {
+				log.Printf("Failed to write reload response: %v\n", err)
+			}
+			return
+		}
+
+		err = u.reload(ctx, req.Config)
+		if err != nil {
+			log.Printf("Failed to reload the chain config: %v\n", err)
+		} else {
+			log.Printf("Reloaded the chain config\n")
+		}
+
+		if err := handshake.WriteReloadResponse(w, err); err != nil {
+			log.Printf("Failed to write reload response: %v\n", err)
+		}
+	}
+}
+
+func (u *Upstream) reload(ctx context.Context, conf *config.ChainConfig) error {
+	chainCursor, disconnect, err := u.connect(ctx, conf)
+	if err != nil {
+		return err
+	}
+
+	if err := u.stream.Swap(ctx, chainCursor); err != nil {
+		disconnect()
+		return err
+	}
+
+	u.mutex.Lock()
+	prev := u.close
+	u.close = disconnect
+	u.mutex.Unlock()
+
+	prev()
+	return nil
+}
+
+// Close closes the current upstream connection.
+func (u *Upstream) Close() {
+	u.mutex.Lock()
+	defer u.mutex.Unlock()
+	u.close()
+}
@@ -0,0 +1,216 @@
+package upstream
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math/big"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/chris-de-leon/chain-connectors-prototype/proto/go/pb"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/config"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/cli/libs/handshake"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/api"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/cursor"
+	"github.com/chris-de-leon/chain-connectors-prototype/src/plugins/libs/streamer"
+	"golang.org/x/net/nettest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+const (
+	BLOCK_TIME = time.Millisecond * 10
+	TEST_TIME  = time.Second * 10
+)
+
+// fakeCursor produces a new cursor every BLOCK_TIME. The height is shared between the
+// cursors of a test (like two connections to the same chain), and reads fail once the
+// cursor's connection has been closed.
+type fakeCursor struct {
+	height *atomic.Int64
+	closed atomic.Bool
+	mutex  sync.Mutex
+	gate   chan struct{}
+	held   chan struct{}
+}
+
+func newFakeCursor(height *atomic.Int64) *fakeCursor {
+	return &fakeCursor{height: height}
+}
+
+func (c *fakeCursor) GetLatestValue(ctx context.Context) (*big.Int, error) {
+	c.mutex.Lock()
+	gate, held := c.gate, c.held
+	c.gate, c.held = nil, nil
+	c.mutex.Unlock()
+
+	// NOTE: a gated read simulates a slow upstream call that is still in flight while
+	// the connection is swapped
+	if gate != nil {
+		close(held)
+		<-gate
+	}
+
+	if c.closed.Load() {
+		return nil, errors.New("use of closed connection")
+	} else {
+		return big.NewInt(c.height.Load()), nil
+	}
+}
+
+func (c *fakeCursor) Subscribe(ctx context.Context, cb func(cursor *big.Int)) error {
+	ticker := time.NewTicker(BLOCK_TIME)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if c.closed.Load() {
+				return errors.New("use of closed connection")
+			} else {
+				cb(big.NewInt(c.height.Add(1)))
+			}
+		}
+	}
+}
+
+// hold makes the next read block until the returned function is called. The held
+// channel is closed once the read is in flight.
+func (c *fakeCursor) hold() (held chan struct{}, release func()) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.gate = make(chan struct{})
+	c.held = make(chan struct{})
+	gate := c.gate
+	return c.held, func() { close(gate) }
+}
+
+func (c *fakeCursor) close() {
+	c.closed.Store(true)
+}
+
+func TestReload(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), TEST_TIME)
+	defer cancel()
+
+	height := &atomic.Int64{}
+	prev, next := newFakeCursor(height), newFakeCursor(height)
+
+	app := api.New(grpc.NewServer(), streamer.New(prev, log.New(io.Discard, "", 0)))
+	conn := New(app.Stream, func(ctx context.Context, conf *config.ChainConfig) (cursor.Cursor, func(), error) {
+		if conf.Conn.Wss == "wss://invalid" {
+			return nil, nil, errors.New("failed to connect")
+		} else {
+			return next, next.close, nil
+		}
+	}, prev.close)
+	defer conn.Close()
+
+	// NOTE: the gRPC server will automatically close the listener
+	lis, err := nettest.NewLocalListener("tcp")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscribed := make(chan error, 1)
+	go func() { subscribed <- app.Stream.Subscribe(ctx) }()
+	go app.Server.Serve(lis)
+	defer app.Server.Stop()
+
+	client, err := grpc.NewClient(lis.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	} else {
+		defer client.Close()
+	}
+
+	stream, err := pb.NewChainCursorClient(client).Cursors(ctx, &pb.StartCursor{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var last *big.Int
+	receive := func(count int) {
+		for range count {
+			cursor, err := stream.Recv()
+			if err != nil {
+				t.Fatalf("expected the stream to stay open but got: %v", err)
+			}
+
+			value, _ := new(big.Int).SetString(cursor.Value, 10)
+			if last != nil && value.Cmp(new(big.Int).Add(last, big.NewInt(1))) != 0 {
+				t.Fatalf("expected cursor %s to follow cursor %s", value, last)
+			}
+			last = value
+		}
+	}
+	receive(3)
+
+	// NOTE: reloads are sent the same way the CLI sends them
+	requestsReader, requestsWriter := io.Pipe()
+	responsesReader, responsesWriter := io.Pipe()
+	go conn.Listen(ctx, handshake.NewRequestReader(requestsReader), responsesWriter)
+	responses := bufio.NewScanner(responsesReader)
+
+	reload := func(wss string) *handshake.ReloadResponse {
+		conf := &config.ChainConfig{Server: &config.ServerConfig{}, Conn: &config.ConnectionConfg{Wss: wss}}
+		if err := handshake.WriteRequest(requestsWriter, handshake.NewRequest(conf)); err != nil {
+			t.Fatal(err)
+		}
+		if !responses.Scan() {
+			t.Fatalf("expected a reload response: %v", responses.Err())
+		}
+
+		res, ok, err := handshake.ParseReloadResponse(responses.Text())
+		if err != nil || !ok {
+			t.Fatalf("expected a reload response but got '%s' (%v)", responses.Text(), err)
+		}
+		return res
+	}
+
+	// NOTE: a failed reload keeps the current connection
+	if res := reload("wss://invalid"); res.Err() == nil || prev.closed.Load() {
+		t.Fatalf("expected the reload to fail without closing the connection: %+v", res)
+	}
+	receive(3)
+
+	// NOTE: the previous connection must stay open while a consumer is reading from it
+	held, release := prev.hold()
+	<-held
+
+	reloaded := make(chan *handshake.ReloadResponse, 1)
+	go func() { reloaded <- reload("wss://next") }()
+
+	select {
+	case res := <-reloaded:
+		t.Fatalf("expected the reload to wait for the read in flight: %+v", res)
+	case <-time.After(BLOCK_TIME * 10):
+	}
+	if prev.closed.Load() {
+		t.Fatal("expected the previous connection to stay open while it is in use")
+	}
+
+	release()
+	if res := <-reloaded; res.Err() != nil {
+		t.Fatal(res.Err())
+	}
+	if !prev.closed.Load() {
+		t.Fatal("expected the previous connection to be closed after the reload")
+	}
+
+	// NOTE: the stream keeps receiving cursors from the new connection
+	receive(5)
+
+	requestsWriter.Close()
+	cancel()
+	if err := <-subscribed; err != nil && !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+}